@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"syscall"
+	"time"
+
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/repository/postgres"
+)
+
+// runBenchStats implements "app bench-stats ..." and returns the process exit
+// code. It times GetStatistics served from the counters against the
+// aggregating query on the configured database and checks that both return
+// the same result.
+func runBenchStats(args []string) int {
+	fs := flag.NewFlagSet("bench-stats", flag.ContinueOnError)
+	n := fs.Int("n", 50, "timed runs per query")
+	team := fs.String("team", "", "team_name filter")
+	status := fs.String("status", "", "status filter (OPEN or MERGED)")
+	limit := fs.Int("limit", 100, "page size of both lists")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *n < 1 {
+		fmt.Fprintln(os.Stderr, "bench-stats: -n must be positive")
+		return 2
+	}
+
+	cfg, _ := setup()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	storage, err := connect(ctx, cfg.Postgres)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer storage.Close()
+
+	var filter model.StatisticsFilter
+	if *team != "" {
+		filter.TeamName = team
+	}
+	if *status != "" {
+		filter.Status = status
+	}
+	page := model.Page{Limit: *limit}
+
+	repos := []struct {
+		name string
+		repo *postgres.StatisticsRepository
+	}{
+		{"counters", postgres.NewStatisticsRepository(storage.Pool(), true)},
+		{"aggregate", postgres.NewStatisticsRepository(storage.Pool(), false)},
+	}
+
+	results := make([]*model.Statistics, len(repos))
+	for i, r := range repos {
+		// The first run warms up the connection and the caches; it also
+		// provides the result compared below.
+		if results[i], err = r.repo.GetStatistics(ctx, filter, page, page); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", r.name, err)
+			return 1
+		}
+
+		durations := make([]time.Duration, *n)
+		for j := range durations {
+			start := time.Now()
+			if _, err := r.repo.GetStatistics(ctx, filter, page, page); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", r.name, err)
+				return 1
+			}
+			durations[j] = time.Since(start)
+		}
+		slices.Sort(durations)
+
+		fmt.Printf("%-10s p50 %-10v p90 %-10v p99 %-10v max %v\n", r.name,
+			quantile(durations, 0.5), quantile(durations, 0.9), quantile(durations, 0.99), durations[len(durations)-1])
+	}
+
+	if !reflect.DeepEqual(results[0], results[1]) {
+		fmt.Fprintln(os.Stderr, "counters and aggregate results differ; run POST /admin/statistics/rebuild")
+		return 1
+	}
+	fmt.Printf("results match: %d prs, %d reviewers\n", results[0].TotalPRs, results[0].TotalReviewers)
+	return 0
+}
+
+// quantile returns the q-th quantile of sorted durations, rounding down.
+func quantile(sorted []time.Duration, q float64) time.Duration {
+	return sorted[int(q*float64(len(sorted)-1))]
+}
@@ -11,42 +11,121 @@ import (
 	"time"
 
 	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/auth"
 	"avito-pr-service/internal/config"
+	"avito-pr-service/internal/lib/logger"
 	"avito-pr-service/internal/lib/logger/sl"
+	"avito-pr-service/internal/metrics"
+	"avito-pr-service/internal/migrate"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/ratelimit"
 	"avito-pr-service/internal/repository/postgres"
 	"avito-pr-service/internal/server/handler"
+	"avito-pr-service/internal/server/middleware"
 	"avito-pr-service/internal/service"
+	"avito-pr-service/internal/tracing"
+	"avito-pr-service/migrations"
 
-	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+const usage = `usage: app [command]
+
+commands:
+  serve                      run the HTTP server (default)
+  migrate up                 apply all pending migrations
+  migrate down [N]           roll back the last N migrations (default 1)
+  migrate to VERSION         migrate up or down to VERSION (0 rolls back everything)
+  migrate status             print the current and pending versions
+  bench-stats [flags]        time /statistics from counters against aggregation
+                             (-n runs, -team, -status, -limit)
+`
+
 func main() {
+	cmd, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		cmd, args = os.Args[1], os.Args[2:]
+	}
+
+	switch cmd {
+	case "serve":
+		serve()
+	case "migrate":
+		os.Exit(runMigrate(args))
+	case "bench-stats":
+		os.Exit(runBenchStats(args))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+}
+
+// setup loads the configuration and installs the configured logger as the
+// slog default.
+func setup() (*config.Config, *slog.Logger) {
 	cfg := config.MustLoad()
 
-	log := slog.New(
-		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-	)
+	logHandler, err := logger.NewHandler(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		slog.Error("invalid log config", sl.Err(err))
+		os.Exit(1)
+	}
+	log := slog.New(tracing.NewLogHandler(logHandler))
+	slog.SetDefault(log)
 
-	log.Info("starting app", slog.Any("cfg", cfg))
+	return cfg, log
+}
 
-	connStr := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.Postgres.User,
-		cfg.Postgres.Password,
-		cfg.Postgres.Host,
-		cfg.Postgres.Port,
-		cfg.Postgres.DBName,
-	)
+func connect(ctx context.Context, cfg config.Postgres) (*postgres.Storage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return postgres.New(ctx, cfg.ConnString(), postgres.PoolConfig{
+		MaxConns:          cfg.Pool.MaxConns,
+		MinConns:          cfg.Pool.MinConns,
+		MaxConnLifetime:   cfg.Pool.MaxConnLifetime,
+		MaxConnIdleTime:   cfg.Pool.MaxConnIdleTime,
+		HealthCheckPeriod: cfg.Pool.HealthCheckPeriod,
+	})
+}
+
+func serve() {
+	cfg, log := setup()
+
+	log.Info("starting app", slog.Any("cfg", cfg))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	storageCtx, storageCancel := context.WithTimeout(ctx, 10*time.Second)
-	defer storageCancel()
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+			ServiceName: cfg.Tracing.ServiceName,
+			Exporter:    cfg.Tracing.Exporter,
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			log.Error("failed to set up tracing", sl.Err(err))
+			os.Exit(1)
+		}
+		defer func() {
+			flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer flushCancel()
+			if err := shutdownTracing(flushCtx); err != nil {
+				log.Error("failed to flush traces", sl.Err(err))
+			}
+		}()
+		log.Info("tracing enabled", slog.String("exporter", cfg.Tracing.Exporter))
+	}
 
-	storage, err := postgres.New(storageCtx, connStr)
+	storage, err := connect(ctx, cfg.Postgres)
 	if err != nil {
 		log.Error("failed to connect to postgres", sl.Err(err))
 		os.Exit(1)
@@ -55,28 +134,125 @@ func main() {
 
 	log.Info("connected to postgres")
 
+	migs, err := migrate.Load(migrations.FS)
+	if err != nil {
+		log.Error("failed to load migrations", sl.Err(err))
+		os.Exit(1)
+	}
+	if cfg.Migrations.AutoApply {
+		applied, err := migrate.New(storage.Pool(), migs).Up(ctx)
+		if err != nil {
+			log.Error("failed to apply migrations", slog.Any("applied", applied), sl.Err(err))
+			os.Exit(1)
+		}
+		log.Info("migrations applied", slog.Any("applied", applied), slog.Int64("version", migrate.Latest(migs)))
+	}
+
 	prRepo := postgres.NewPRRepository(storage.Pool())
 	userRepo := postgres.NewUserRepository(storage.Pool())
 	teamRepo := postgres.NewTeamRepository(storage.Pool(), userRepo)
-	statRepo := postgres.NewStatisticsRepository(storage.Pool())
+	statRepo := postgres.NewStatisticsRepository(storage.Pool(), cfg.Statistics.UseCounters)
+	tokenRepo := postgres.NewTokenRepository(storage.Pool())
 
-	prService := service.NewPRService(prRepo)
-	userService := service.NewUserService(userRepo)
+	prService := service.NewPRService(prRepo, teamRepo)
+	userService := service.NewUserService(userRepo, teamRepo)
 	teamService := service.NewTeamService(teamRepo, userRepo, prRepo)
 	statService := service.NewStatisticsService(statRepo)
+	authService := service.NewAuthService(tokenRepo)
+	scimService := service.NewSCIMService(userRepo, teamRepo, cfg.SCIM.DefaultTeam)
+	healthService := service.NewHealthService(postgres.NewHealthRepository(storage.Pool()), service.HealthConfig{
+		MaxPoolSaturation: cfg.Health.MaxPoolSaturation,
+		SchemaVersion:     migrate.Latest(migs),
+		CheckTimeout:      cfg.Health.CheckTimeout,
+	})
+
+	bootstrap := cfg.Auth.BootstrapTokens
+	if cfg.Auth.AdminToken != "" {
+		bootstrap = append(bootstrap, config.BootstrapToken{
+			ID:     "bootstrap-admin",
+			Name:   "bootstrap admin",
+			Token:  cfg.Auth.AdminToken,
+			Scopes: []string{model.ScopePRWrite, model.ScopeTeamAdmin, model.ScopeStatsRead},
+		})
+	}
+	for _, bt := range bootstrap {
+		if _, err := authService.EnsureToken(ctx, bt.ID, bt.Name, bt.Token, bt.Scopes); err != nil {
+			log.Error("failed to bootstrap api token", slog.String("token_id", bt.ID), sl.Err(err))
+			os.Exit(1)
+		}
+		log.Info("bootstrapped api token", slog.String("token_id", bt.ID))
+	}
 
 	prHandler := handler.NewPRHandler(prService)
 	userHandler := handler.NewUserHandler(userService, prService)
 	teamHandler := handler.NewTeamHandler(teamService)
 	statHandler := handler.NewStatisticsHandler(statService)
+	authHandler := handler.NewAuthHandler(authService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	adminHandler := handler.NewAdminHandler(teamService)
+	scimHandler := handler.NewSCIMHandler(scimService)
 
-	apiHandler := handler.NewAPIHandler(prHandler, userHandler, teamHandler, statHandler)
+	apiHandler := handler.NewAPIHandler(prHandler, userHandler, teamHandler, statHandler, authHandler, healthHandler, adminHandler, scimHandler)
 	r := chi.NewRouter()
 
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.Logger)
+	if cfg.Tracing.Enabled {
+		r.Use(middleware.Tracing)
+	}
+	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.RequestLogger(log))
+	r.Use(chimiddleware.Recoverer)
+	r.Use(middleware.Metrics)
+
+	if cfg.Statistics.CheckInterval > 0 {
+		go checkCounters(ctx, log, statRepo, cfg.Statistics.CheckInterval)
+	}
+	if cfg.Statistics.SnapshotInterval > 0 {
+		go writeSnapshots(ctx, log, statRepo, cfg.Statistics.SnapshotInterval)
+	}
+
+	prometheus.MustRegister(
+		metrics.NewPoolCollector(storage.Pool()),
+		metrics.NewOpenReviewsCollector(statRepo, 5*time.Second),
+	)
+
+	// Middlewares listed later wrap the earlier ones: Auth runs first, so the
+	// rate limiter can key clients by their verified token.
+	var apiMiddlewares []api.MiddlewareFunc
+	if cfg.RateLimit.Enabled {
+		limiter, err := newRateLimiter(ctx, log, cfg.RateLimit, storage)
+		if err != nil {
+			log.Error("failed to set up rate limiting", sl.Err(err))
+			os.Exit(1)
+		}
+		routes := make(map[string]ratelimit.Limit, len(cfg.RateLimit.Routes))
+		for path, l := range cfg.RateLimit.Routes {
+			routes[path] = ratelimit.Limit{Rate: l.Rate, Burst: l.Burst}
+		}
+		def := ratelimit.Limit{Rate: cfg.RateLimit.Default.Rate, Burst: cfg.RateLimit.Default.Burst}
+		apiMiddlewares = append(apiMiddlewares, middleware.RateLimit(log, limiter, def, routes))
+		log.Info("rate limiting enabled", slog.String("backend", cfg.RateLimit.Backend))
+	}
 
-	api.HandlerFromMux(apiHandler, r)
+	if cfg.Auth.Enabled {
+		var jwtVerifier middleware.JWTVerifier
+		if cfg.Auth.JWT.Enabled {
+			v, err := newJWTVerifier(ctx, log, cfg.Auth.JWT, userService)
+			if err != nil {
+				log.Error("failed to set up jwt verification", sl.Err(err))
+				os.Exit(1)
+			}
+			jwtVerifier = v
+			log.Info("jwt authentication enabled", slog.String("issuer", cfg.Auth.JWT.Issuer))
+		}
+		apiMiddlewares = append(apiMiddlewares, middleware.Auth(authService, jwtVerifier))
+	} else {
+		log.Warn("api authentication is disabled")
+	}
+
+	api.HandlerWithOptions(apiHandler, api.ChiServerOptions{
+		BaseRouter:  r,
+		Middlewares: apiMiddlewares,
+	})
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/openapi.json"),
 	))
@@ -94,6 +270,15 @@ func main() {
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+	metricsSrv := &http.Server{
+		Addr:         cfg.Metrics.Address,
+		Handler:      metricsMux,
+		ReadTimeout:  cfg.HTTPServer.Timeout,
+		WriteTimeout: cfg.HTTPServer.Timeout,
+	}
+
 	go func() {
 		log.Info("starting server", slog.String("address", cfg.HTTPServer.Address))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -102,6 +287,14 @@ func main() {
 		}
 	}()
 
+	go func() {
+		log.Info("starting metrics server", slog.String("address", cfg.Metrics.Address))
+		if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("failed to start metrics server", sl.Err(err))
+			os.Exit(1)
+		}
+	}()
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	log.Info("service ready, waiting for stop signal")
@@ -109,12 +302,124 @@ func main() {
 
 	log.Info("shutting down gracefully...")
 
+	healthService.StartDraining()
+	log.Info("readiness set to failing, draining", slog.Duration("delay", cfg.Health.DrainDelay))
+	time.Sleep(cfg.Health.DrainDelay)
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Error("server forced to shutdown", sl.Err(err))
 	}
+	if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+		log.Error("metrics server forced to shutdown", sl.Err(err))
+	}
 
 	log.Info("service stopped")
 }
+
+func newRateLimiter(ctx context.Context, log *slog.Logger, cfg config.RateLimit, storage *postgres.Storage) (ratelimit.Limiter, error) {
+	switch cfg.Backend {
+	case "memory":
+		return ratelimit.NewMemoryLimiter(cfg.IdleTTL), nil
+	case "postgres":
+		limiter := postgres.NewRateLimiter(storage.Pool())
+		go func() {
+			ticker := time.NewTicker(cfg.IdleTTL)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if _, err := limiter.PurgeIdle(ctx, cfg.IdleTTL); err != nil {
+						log.Warn("failed to purge idle rate limit buckets", sl.Err(err))
+					}
+				}
+			}
+		}()
+		return limiter, nil
+	default:
+		return nil, fmt.Errorf("rate_limit.backend: unknown backend %q", cfg.Backend)
+	}
+}
+
+// checkCounters rebuilds the statistics counters every interval. Drift means
+// something changed reviews past the repositories, so it is logged.
+func checkCounters(ctx context.Context, log *slog.Logger, repo *postgres.StatisticsRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check, err := repo.RebuildCounters(ctx)
+			if err != nil {
+				log.Warn("failed to check statistics counters", sl.Err(err))
+				continue
+			}
+			if check.TeamRows > 0 || check.ReviewerRows > 0 {
+				log.Warn("statistics counters drifted and were rebuilt",
+					slog.Int("team_rows", check.TeamRows),
+					slog.Int("reviewer_rows", check.ReviewerRows),
+				)
+			}
+		}
+	}
+}
+
+// writeSnapshots writes the stats snapshots of finished UTC days right away,
+// which backfills them on the first start, and then every interval.
+func writeSnapshots(ctx context.Context, log *slog.Logger, repo *postgres.StatisticsRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now().UTC()
+		yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+		days, err := repo.WriteSnapshots(ctx, yesterday)
+		if err != nil {
+			log.Warn("failed to write stats snapshots", slog.Int("days", days), sl.Err(err))
+		} else if days > 0 {
+			log.Info("stats snapshots written", slog.Int("days", days), slog.String("through", yesterday.Format(time.DateOnly)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func newJWTVerifier(ctx context.Context, log *slog.Logger, cfg config.JWT, users auth.UserGetter) (*auth.JWTVerifier, error) {
+	var (
+		keys *auth.KeySet
+		err  error
+	)
+	switch {
+	case cfg.JWKSPath != "":
+		keys, err = auth.NewFileKeySet(cfg.JWKSPath)
+	case cfg.JWKSURL != "":
+		keys, err = auth.NewURLKeySet(ctx, cfg.JWKSURL)
+		if err == nil {
+			go keys.RunRefresh(ctx, cfg.JWKSRefreshInterval, func(err error) {
+				log.Warn("failed to refresh jwks", sl.Err(err))
+			})
+		}
+	default:
+		return nil, fmt.Errorf("auth.jwt: jwks_path or jwks_url is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewJWTVerifier(auth.JWTConfig{
+		Issuer:        cfg.Issuer,
+		Audience:      cfg.Audience,
+		UserClaim:     cfg.UserClaim,
+		ScopesClaim:   cfg.ScopesClaim,
+		DefaultScopes: cfg.DefaultScopes,
+	}, keys, users), nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"avito-pr-service/internal/migrate"
+	"avito-pr-service/migrations"
+)
+
+// runMigrate implements "app migrate ..." and returns the process exit code.
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	cfg, _ := setup()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	migs, err := migrate.Load(migrations.FS)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	storage, err := connect(ctx, cfg.Postgres)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer storage.Close()
+
+	m := migrate.New(storage.Pool(), migs)
+
+	var done []int64
+	switch args[0] {
+	case "up":
+		done, err = m.Up(ctx)
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "migrate down: N must be a positive number, got %q\n", args[1])
+				return 2
+			}
+		}
+		done, err = m.Down(ctx, n)
+	case "to":
+		if len(args) < 2 {
+			fmt.Fprint(os.Stderr, usage)
+			return 2
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil || version < 0 {
+			fmt.Fprintf(os.Stderr, "migrate to: bad version %q\n", args[1])
+			return 2
+		}
+		done, err = m.To(ctx, version)
+	case "status":
+		return printStatus(ctx, m)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	for _, v := range done {
+		fmt.Printf("%s %d\n", args[0], v)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(done) == 0 {
+		fmt.Println("no change")
+	}
+	return 0
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) int {
+	st, err := m.Status(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	dirty := ""
+	if st.Dirty {
+		dirty = " (dirty)"
+	}
+	fmt.Printf("version: %d%s\nlatest:  %d\n", st.Version, dirty, st.Latest)
+	for _, mig := range st.Pending {
+		fmt.Printf("pending: %d_%s\n", mig.Version, mig.Name)
+	}
+	return 0
+}
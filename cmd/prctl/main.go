@@ -0,0 +1,16 @@
+// Command prctl is an operator CLI for the PR reviewer assignment service.
+// It talks to the HTTP API with the request and response types generated in
+// internal/api.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable:
+		return &printer{w: w}, nil
+	case outputJSON:
+		return &printer{w: w, json: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, want table or json", format)
+	}
+}
+
+// print writes v as indented JSON, or calls render to lay it out as columns.
+func (p *printer) print(v any, render func(t *table)) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	t := &table{tw: tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)}
+	render(t)
+	return t.tw.Flush()
+}
+
+type table struct {
+	tw *tabwriter.Writer
+}
+
+func (t *table) row(cols ...any) {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		parts[i] = fmt.Sprint(c)
+	}
+	fmt.Fprintln(t.tw, strings.Join(parts, "\t"))
+}
+
+func (t *table) blank() {
+	fmt.Fprintln(t.tw)
+}
+
+func orDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
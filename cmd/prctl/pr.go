@@ -0,0 +1,160 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"avito-pr-service/internal/api"
+
+	"github.com/spf13/cobra"
+)
+
+type prResponse struct {
+	PR api.PullRequest `json:"pr"`
+}
+
+type reassignResponse struct {
+	PR         api.PullRequest `json:"pr"`
+	ReplacedBy string          `json:"replaced_by"`
+}
+
+func newPRCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pr",
+		Aliases: []string{"pullrequest"},
+		Short:   "Manage pull requests",
+	}
+	cmd.AddCommand(newPRCreateCmd(a), newPRGetCmd(a), newPRMergeCmd(a), newPRReassignCmd(a))
+	return cmd
+}
+
+func newPRCreateCmd(a *app) *cobra.Command {
+	var (
+		name   string
+		author string
+		team   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create PR_ID --name NAME --author USER_ID [--team TEAM]",
+		Short: "Create a pull request and assign reviewers",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := api.PostPullRequestCreateJSONRequestBody{
+				PullRequestId:   args[0],
+				PullRequestName: name,
+				AuthorId:        author,
+			}
+			if team != "" {
+				body.TeamName = &team
+			}
+
+			var resp prResponse
+			if err := a.client.post(cmd.Context(), "/pullRequest/create", body, &resp); err != nil {
+				return err
+			}
+			return printPR(a, resp, resp.PR)
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "pull request title")
+	cmd.Flags().StringVar(&author, "author", "", "author user_id")
+	cmd.Flags().StringVar(&team, "team", "", "team to pick reviewers from (default: author's primary team)")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("author")
+	return cmd
+}
+
+func newPRGetCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get PR_ID",
+		Short: "Show a pull request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp prResponse
+			query := url.Values{"pull_request_id": {args[0]}}
+			if err := a.client.get(cmd.Context(), "/pullRequest/get", query, &resp); err != nil {
+				return err
+			}
+			return printPR(a, resp, resp.PR)
+		},
+	}
+}
+
+func newPRMergeCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge PR_ID",
+		Short: "Mark a pull request as merged",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := api.PostPullRequestMergeJSONRequestBody{PullRequestId: args[0]}
+
+			var resp prResponse
+			if err := a.client.post(cmd.Context(), "/pullRequest/merge", body, &resp); err != nil {
+				return err
+			}
+			return printPR(a, resp, resp.PR)
+		},
+	}
+}
+
+func newPRReassignCmd(a *app) *cobra.Command {
+	var (
+		oldReviewer string
+		escalate    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reassign PR_ID --old-reviewer USER_ID",
+		Short: "Replace a reviewer on an open pull request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := api.PostPullRequestReassignJSONRequestBody{PullRequestId: args[0], OldUserId: oldReviewer}
+			if escalate {
+				body.Escalate = &escalate
+			}
+
+			var resp reassignResponse
+			if err := a.client.post(cmd.Context(), "/pullRequest/reassign", body, &resp); err != nil {
+				return err
+			}
+			return a.out.print(resp, func(t *table) {
+				t.row("REPLACED", oldReviewer)
+				t.row("BY", resp.ReplacedBy)
+				t.blank()
+				prRows(t, resp.PR)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&oldReviewer, "old-reviewer", "", "user_id of the reviewer to replace")
+	cmd.Flags().BoolVar(&escalate, "escalate", false, "look for candidates in parent teams if the PR's team has none")
+	_ = cmd.MarkFlagRequired("old-reviewer")
+	return cmd
+}
+
+func printPR(a *app, v any, pr api.PullRequest) error {
+	return a.out.print(v, func(t *table) { prRows(t, pr) })
+}
+
+func prRows(t *table, pr api.PullRequest) {
+	reviewers := "-"
+	if len(pr.AssignedReviewers) > 0 {
+		reviewers = strings.Join(pr.AssignedReviewers, ", ")
+	}
+
+	t.row("PR", pr.PullRequestId)
+	t.row("NAME", pr.PullRequestName)
+	t.row("AUTHOR", pr.AuthorId)
+	t.row("TEAM", orDash(pr.TeamName))
+	t.row("STATUS", pr.Status)
+	t.row("REVIEWERS", reviewers)
+	t.row("CREATED", formatTime(pr.CreatedAt))
+	t.row("MERGED", formatTime(pr.MergedAt))
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
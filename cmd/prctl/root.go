@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the content of ~/.config/prctl/config.yaml.
+type fileConfig struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"`
+}
+
+type globalFlags struct {
+	configPath string
+	url        string
+	token      string
+	output     string
+}
+
+// app is shared by all subcommands; client is set up in PersistentPreRunE.
+type app struct {
+	flags  globalFlags
+	client *client
+	out    *printer
+}
+
+func newRootCmd() *cobra.Command {
+	a := &app{}
+
+	root := &cobra.Command{
+		Use:           "prctl",
+		Short:         "Operate the PR reviewer assignment service",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			// Completion scripts must work without a reachable server.
+			if cmd.Name() == "completion" || cmd.Parent() != nil && cmd.Parent().Name() == "completion" {
+				return nil
+			}
+			return a.init()
+		},
+	}
+
+	f := root.PersistentFlags()
+	f.StringVar(&a.flags.configPath, "config", defaultConfigPath(), "config file with url and token")
+	f.StringVar(&a.flags.url, "url", "", "service base URL (overrides config and PRCTL_URL)")
+	f.StringVar(&a.flags.token, "token", "", "bearer token (overrides config and PRCTL_TOKEN)")
+	f.StringVarP(&a.flags.output, "output", "o", outputTable, "output format: table or json")
+	_ = root.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputTable, outputJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	root.AddCommand(
+		newTeamCmd(a),
+		newUserCmd(a),
+		newPRCmd(a),
+		newStatsCmd(a),
+	)
+
+	return root
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "prctl", "config.yaml")
+}
+
+// init resolves the connection settings: flags win over environment
+// variables, which win over the config file.
+func (a *app) init() error {
+	var cfg fileConfig
+	if a.flags.configPath != "" {
+		data, err := os.ReadFile(a.flags.configPath)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return fmt.Errorf("parse %s: %w", a.flags.configPath, err)
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return err
+		}
+	}
+
+	if v := os.Getenv("PRCTL_URL"); v != "" {
+		cfg.URL = v
+	}
+	if v := os.Getenv("PRCTL_TOKEN"); v != "" {
+		cfg.Token = v
+	}
+	if a.flags.url != "" {
+		cfg.URL = a.flags.url
+	}
+	if a.flags.token != "" {
+		cfg.Token = a.flags.token
+	}
+
+	if cfg.URL == "" {
+		cfg.URL = "http://localhost:8080"
+	}
+
+	out, err := newPrinter(os.Stdout, a.flags.output)
+	if err != nil {
+		return err
+	}
+
+	a.client = newClient(cfg.URL, cfg.Token)
+	a.out = out
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"avito-pr-service/internal/api"
+
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd(a *app) *cobra.Command {
+	var team, from, to, status string
+	var limit, offset int
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show review statistics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query := url.Values{}
+			for name, value := range map[string]string{"team_name": team, "from": from, "to": to, "status": status} {
+				if value != "" {
+					query.Set(name, value)
+				}
+			}
+			if cmd.Flags().Changed("limit") {
+				query.Set("pr_limit", strconv.Itoa(limit))
+				query.Set("reviewers_limit", strconv.Itoa(limit))
+			}
+			if cmd.Flags().Changed("offset") {
+				query.Set("pr_offset", strconv.Itoa(offset))
+				query.Set("reviewers_offset", strconv.Itoa(offset))
+			}
+
+			var resp api.StatisticsResponse
+			if err := a.client.get(cmd.Context(), "/statistics", query, &resp); err != nil {
+				return err
+			}
+			return a.out.print(resp, func(t *table) {
+				t.row("TOTAL_PRS", resp.TotalPrs)
+				t.row("TOTAL_REVIEWERS", resp.TotalReviewers)
+				t.blank()
+				t.row("USER_ID", "USERNAME", "TEAM", "OPEN", "MERGED", "TOTAL")
+				for _, s := range resp.ReviewersStats {
+					t.row(s.UserId, s.Username, s.TeamName, s.OpenReviews, s.MergedReviews, s.TotalReviews)
+				}
+			})
+		},
+	}
+	cmd.Flags().StringVar(&team, "team", "", "only count PRs of this team and its members as reviewers")
+	cmd.Flags().StringVar(&from, "from", "", "only count PRs created at or after this RFC 3339 time")
+	cmd.Flags().StringVar(&to, "to", "", "only count PRs created before this RFC 3339 time")
+	cmd.Flags().StringVar(&status, "status", "", "only count PRs in this status (OPEN or MERGED)")
+	cmd.Flags().IntVar(&limit, "limit", 100, "page size of both the reviewer and the PR list")
+	cmd.Flags().IntVar(&offset, "offset", 0, "entries to skip in both the reviewer and the PR list")
+	cmd.AddCommand(newStatsSubtreeCmd(a))
+	return cmd
+}
+
+func newStatsSubtreeCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "subtree TEAM",
+		Short: "Show per-unit statistics for a team and its descendants",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp api.SubtreeStatisticsResponse
+			query := url.Values{"team_name": {args[0]}}
+			if err := a.client.get(cmd.Context(), "/statistics/subtree", query, &resp); err != nil {
+				return err
+			}
+			return a.out.print(resp, func(t *table) {
+				t.row("TEAM", "MEMBERS", "OPEN", "MERGED", "TOTAL", "SUBTREE_MEMBERS", "SUBTREE_TOTAL")
+				for _, u := range resp.Units {
+					name := strings.Repeat("  ", u.Depth) + u.TeamName
+					t.row(name, u.Members, u.OpenPrs, u.MergedPrs, u.TotalPrs, u.Subtree.Members, u.Subtree.TotalPrs)
+				}
+			})
+		},
+	}
+}
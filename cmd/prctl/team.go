@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"avito-pr-service/internal/api"
+
+	"github.com/spf13/cobra"
+)
+
+type teamResponse struct {
+	Team api.Team `json:"team"`
+}
+
+func newTeamCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "team",
+		Short: "Manage teams",
+	}
+	cmd.AddCommand(newTeamAddCmd(a), newTeamGetCmd(a), newTeamRenameCmd(a))
+	return cmd
+}
+
+func newTeamAddCmd(a *app) *cobra.Command {
+	var members []string
+
+	cmd := &cobra.Command{
+		Use:   "add TEAM --member USER_ID:USERNAME[:ROLE]...",
+		Short: "Create a team or add members to it",
+		Example: `  prctl team add backend --member u1:Alice:lead --member u2:Bob
+  prctl team add backend --member u3:Carol`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := api.PostTeamAddJSONRequestBody{TeamName: args[0], Members: []api.TeamMember{}}
+			for _, m := range members {
+				member, err := parseMember(m)
+				if err != nil {
+					return err
+				}
+				body.Members = append(body.Members, member)
+			}
+
+			var resp teamResponse
+			if err := a.client.post(cmd.Context(), "/team/add", body, &resp); err != nil {
+				return err
+			}
+			return printTeam(a, resp)
+		},
+	}
+	cmd.Flags().StringArrayVarP(&members, "member", "m", nil, "member as USER_ID:USERNAME[:ROLE], repeatable")
+	_ = cmd.MarkFlagRequired("member")
+	return cmd
+}
+
+func parseMember(s string) (api.TeamMember, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return api.TeamMember{}, fmt.Errorf("bad member %q, want USER_ID:USERNAME[:ROLE]", s)
+	}
+
+	member := api.TeamMember{UserId: parts[0], Username: parts[1], IsActive: true}
+	if len(parts) == 3 {
+		role := api.TeamRole(parts[2])
+		member.Role = &role
+	}
+	return member, nil
+}
+
+func newTeamGetCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get TEAM",
+		Short: "Show a team and its members",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp teamResponse
+			query := url.Values{"team_name": {args[0]}}
+			if err := a.client.get(cmd.Context(), "/team/get", query, &resp); err != nil {
+				return err
+			}
+			return printTeam(a, resp)
+		},
+	}
+}
+
+func newTeamRenameCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename TEAM NEW_NAME",
+		Short: "Rename a team",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := api.PostTeamRenameJSONRequestBody{TeamName: args[0], NewTeamName: args[1]}
+
+			var resp teamResponse
+			if err := a.client.post(cmd.Context(), "/team/rename", body, &resp); err != nil {
+				return err
+			}
+			return printTeam(a, resp)
+		},
+	}
+}
+
+func printTeam(a *app, resp teamResponse) error {
+	return a.out.print(resp, func(t *table) {
+		t.row("TEAM", resp.Team.TeamName)
+		t.blank()
+		t.row("USER_ID", "USERNAME", "ROLE", "ACTIVE")
+		for _, m := range resp.Team.Members {
+			role := "-"
+			if m.Role != nil {
+				role = string(*m.Role)
+			}
+			t.row(m.UserId, m.Username, role, m.IsActive)
+		}
+	})
+}
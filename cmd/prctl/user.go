@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/url"
+
+	"avito-pr-service/internal/api"
+
+	"github.com/spf13/cobra"
+)
+
+type userResponse struct {
+	User api.User `json:"user"`
+}
+
+func newUserCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+	cmd.AddCommand(
+		newUserGetCmd(a),
+		newUserSetActiveCmd(a, "activate", "Mark a user as active so they can be assigned again", true),
+		newUserSetActiveCmd(a, "deactivate", "Mark a user as inactive and stop assigning them", false),
+		newUserMoveCmd(a),
+	)
+	return cmd
+}
+
+func newUserGetCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get USER_ID",
+		Short: "Show a user and their team memberships",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp userResponse
+			query := url.Values{"user_id": {args[0]}}
+			if err := a.client.get(cmd.Context(), "/users/get", query, &resp); err != nil {
+				return err
+			}
+			return printUser(a, resp)
+		},
+	}
+}
+
+func newUserSetActiveCmd(a *app, use, short string, active bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " USER_ID",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := api.PostUsersSetIsActiveJSONRequestBody{UserId: args[0], IsActive: active}
+
+			var resp userResponse
+			if err := a.client.post(cmd.Context(), "/users/setIsActive", body, &resp); err != nil {
+				return err
+			}
+			return printUser(a, resp)
+		},
+	}
+}
+
+func newUserMoveCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "move USER_ID TEAM",
+		Short: "Move a user to another primary team",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := api.PostUsersUpdateJSONRequestBody{UserId: args[0], TeamName: &args[1]}
+
+			var resp userResponse
+			if err := a.client.post(cmd.Context(), "/users/update", body, &resp); err != nil {
+				return err
+			}
+			return printUser(a, resp)
+		},
+	}
+}
+
+func printUser(a *app, resp userResponse) error {
+	u := resp.User
+	return a.out.print(resp, func(t *table) {
+		t.row("USER_ID", u.UserId)
+		t.row("USERNAME", u.Username)
+		t.row("TEAM", u.TeamName)
+		t.row("ACTIVE", u.IsActive)
+		if u.Teams == nil || len(*u.Teams) == 0 {
+			return
+		}
+		t.blank()
+		t.row("TEAM", "ROLE", "ACTIVE")
+		for _, m := range *u.Teams {
+			t.row(m.TeamName, m.Role, m.IsActive)
+		}
+	})
+}
@@ -4,22 +4,61 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AffectedReviewReason.
+const (
+	AffectedReviewReasonAdd        AffectedReviewReason = "add"
+	AffectedReviewReasonDeactivate AffectedReviewReason = "deactivate"
+	AffectedReviewReasonMove       AffectedReviewReason = "move"
+	AffectedReviewReasonRemove     AffectedReviewReason = "remove"
+	AffectedReviewReasonUpdate     AffectedReviewReason = "update"
 )
 
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	BADREQUEST        ErrorResponseErrorCode = "BAD_REQUEST"
+	FORBIDDEN         ErrorResponseErrorCode = "FORBIDDEN"
+	INSUFFICIENTSCOPE ErrorResponseErrorCode = "INSUFFICIENT_SCOPE"
+	INTERNAL          ErrorResponseErrorCode = "INTERNAL"
+	INVALIDPARENT     ErrorResponseErrorCode = "INVALID_PARENT"
+	NOCANDIDATE       ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED       ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTFOUND          ErrorResponseErrorCode = "NOT_FOUND"
+	PLANSTALE         ErrorResponseErrorCode = "PLAN_STALE"
+	PREXISTS          ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED          ErrorResponseErrorCode = "PR_MERGED"
+	RATELIMITED       ErrorResponseErrorCode = "RATE_LIMITED"
+	TEAMEXISTS        ErrorResponseErrorCode = "TEAM_EXISTS"
+	UNAUTHORIZED      ErrorResponseErrorCode = "UNAUTHORIZED"
+	USEREXISTS        ErrorResponseErrorCode = "USER_EXISTS"
+	USERHASHISTORY    ErrorResponseErrorCode = "USER_HAS_HISTORY"
+	USERHASOPENPRS    ErrorResponseErrorCode = "USER_HAS_OPEN_PRS"
+	VALIDATIONFAILED  ErrorResponseErrorCode = "VALIDATION_FAILED"
+)
+
+// Defines values for HealthCheckStatus.
+const (
+	HealthCheckStatusFail HealthCheckStatus = "fail"
+	HealthCheckStatusOk   HealthCheckStatus = "ok"
+)
+
+// Defines values for HealthResponseStatus.
+const (
+	HealthResponseStatusFail HealthResponseStatus = "fail"
+	HealthResponseStatusOk   HealthResponseStatus = "ok"
 )
 
 // Defines values for PRStatsStatus.
@@ -36,21 +75,269 @@ const (
 
 // Defines values for PullRequestShortStatus.
 const (
-	MERGED PullRequestShortStatus = "MERGED"
-	OPEN   PullRequestShortStatus = "OPEN"
+	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
+	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
+)
+
+// Defines values for ReviewSettingsStrategy.
+const (
+	Random     ReviewSettingsStrategy = "random"
+	RoundRobin ReviewSettingsStrategy = "round_robin"
+)
+
+// Defines values for ScimErrorScimType.
+const (
+	InvalidFilter ScimErrorScimType = "invalidFilter"
+	InvalidPath   ScimErrorScimType = "invalidPath"
+	InvalidSyntax ScimErrorScimType = "invalidSyntax"
+	InvalidValue  ScimErrorScimType = "invalidValue"
+	Mutability    ScimErrorScimType = "mutability"
+	NoTarget      ScimErrorScimType = "noTarget"
+	TooMany       ScimErrorScimType = "tooMany"
+	Uniqueness    ScimErrorScimType = "uniqueness"
+)
+
+// Defines values for ScimMetaResourceType.
+const (
+	ScimMetaResourceTypeGroup ScimMetaResourceType = "Group"
+	ScimMetaResourceTypeUser  ScimMetaResourceType = "User"
+)
+
+// Defines values for StatisticsHistoryResponseGranularity.
+const (
+	StatisticsHistoryResponseGranularityDay   StatisticsHistoryResponseGranularity = "day"
+	StatisticsHistoryResponseGranularityMonth StatisticsHistoryResponseGranularity = "month"
+	StatisticsHistoryResponseGranularityWeek  StatisticsHistoryResponseGranularity = "week"
+)
+
+// Defines values for SyncTeamChangeAction.
+const (
+	Archive SyncTeamChangeAction = "archive"
+	Create  SyncTeamChangeAction = "create"
+	Restore SyncTeamChangeAction = "restore"
+)
+
+// Defines values for SyncUserChangeAction.
+const (
+	SyncUserChangeActionAdd        SyncUserChangeAction = "add"
+	SyncUserChangeActionDeactivate SyncUserChangeAction = "deactivate"
+	SyncUserChangeActionMove       SyncUserChangeAction = "move"
+	SyncUserChangeActionRemove     SyncUserChangeAction = "remove"
+	SyncUserChangeActionUpdate     SyncUserChangeAction = "update"
+)
+
+// Defines values for TeamRole.
+const (
+	Admin  TeamRole = "admin"
+	Lead   TeamRole = "lead"
+	Member TeamRole = "member"
+)
+
+// Defines values for TokenScope.
+const (
+	PrWrite   TokenScope = "pr:write"
+	StatsRead TokenScope = "stats:read"
+	TeamAdmin TokenScope = "team:admin"
+)
+
+// Defines values for GranularityQuery.
+const (
+	GranularityQueryDay   GranularityQuery = "day"
+	GranularityQueryMonth GranularityQuery = "month"
+	GranularityQueryWeek  GranularityQuery = "week"
+)
+
+// Defines values for MissingUsersQuery.
+const (
+	MissingUsersQueryDeactivate MissingUsersQuery = "deactivate"
+	MissingUsersQueryRemove     MissingUsersQuery = "remove"
+)
+
+// Defines values for StatusQuery.
+const (
+	StatusQueryMERGED StatusQuery = "MERGED"
+	StatusQueryOPEN   StatusQuery = "OPEN"
+)
+
+// Defines values for GetAdminExportParamsFormat.
+const (
+	GetAdminExportParamsFormatCsv  GetAdminExportParamsFormat = "csv"
+	GetAdminExportParamsFormatYaml GetAdminExportParamsFormat = "yaml"
+)
+
+// Defines values for PostAdminImportParamsFormat.
+const (
+	PostAdminImportParamsFormatCsv  PostAdminImportParamsFormat = "csv"
+	PostAdminImportParamsFormatYaml PostAdminImportParamsFormat = "yaml"
+)
+
+// Defines values for PostAdminSyncApplyParamsMissingUsers.
+const (
+	PostAdminSyncApplyParamsMissingUsersDeactivate PostAdminSyncApplyParamsMissingUsers = "deactivate"
+	PostAdminSyncApplyParamsMissingUsersRemove     PostAdminSyncApplyParamsMissingUsers = "remove"
+)
+
+// Defines values for PostAdminSyncPlanParamsMissingUsers.
+const (
+	Deactivate PostAdminSyncPlanParamsMissingUsers = "deactivate"
+	Remove     PostAdminSyncPlanParamsMissingUsers = "remove"
+)
+
+// Defines values for GetStatisticsParamsStatus.
+const (
+	MERGED GetStatisticsParamsStatus = "MERGED"
+	OPEN   GetStatisticsParamsStatus = "OPEN"
+)
+
+// Defines values for GetStatisticsHistoryParamsGranularity.
+const (
+	Day   GetStatisticsHistoryParamsGranularity = "day"
+	Month GetStatisticsHistoryParamsGranularity = "month"
+	Week  GetStatisticsHistoryParamsGranularity = "week"
+)
+
+// Defines values for GetStatisticsPairsParamsFormat.
+const (
+	Csv  GetStatisticsPairsParamsFormat = "csv"
+	Json GetStatisticsPairsParamsFormat = "json"
 )
 
+// AffectedReview defines model for AffectedReview.
+type AffectedReview struct {
+	AuthorId      string `json:"author_id"`
+	PullRequestId string `json:"pull_request_id"`
+
+	// Reason Действие с ревьювером, из-за которого он перестаёт быть активным участником команды PR
+	Reason     AffectedReviewReason `json:"reason"`
+	ReviewerId string               `json:"reviewer_id"`
+	TeamName   string               `json:"team_name"`
+}
+
+// AffectedReviewReason Действие с ревьювером, из-за которого он перестаёт быть активным участником команды PR
+type AffectedReviewReason string
+
+// ApiToken defines model for ApiToken.
+type ApiToken struct {
+	CreatedAt time.Time    `json:"created_at"`
+	Name      string       `json:"name"`
+	RevokedAt *time.Time   `json:"revoked_at"`
+	Scopes    []TokenScope `json:"scopes"`
+	TokenId   string       `json:"token_id"`
+
+	// UserId Пользователь, от имени которого действует токен
+	UserId *string `json:"user_id"`
+}
+
+// CounterCheck defines model for CounterCheck.
+type CounterCheck struct {
+	// ReviewerRows Строк reviewer_counters, расходившихся с пересчётом
+	ReviewerRows int `json:"reviewer_rows"`
+
+	// TeamRows Строк team_counters, расходившихся с пересчётом
+	TeamRows int `json:"team_rows"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
 		Code    ErrorResponseErrorCode `json:"code"`
 		Message string                 `json:"message"`
+
+		// TraceId Идентификатор трассировки запроса (также в заголовке `X-Trace-Id`),
+		// передаётся, когда трассировка включена
+		TraceId *string `json:"trace_id,omitempty"`
 	} `json:"error"`
+
+	// Rows Ошибки по строкам входного файла (для `VALIDATION_FAILED`)
+	Rows *[]RowError `json:"rows,omitempty"`
 }
 
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// FairnessResponse defines model for FairnessResponse.
+type FairnessResponse struct {
+	From      *time.Time     `json:"from,omitempty"`
+	Teams     []TeamFairness `json:"teams"`
+	To        *time.Time     `json:"to,omitempty"`
+	Tolerance float32        `json:"tolerance"`
+}
+
+// HealthCheck defines model for HealthCheck.
+type HealthCheck struct {
+	// Detail Дополнительные сведения, например текущая версия схемы
+	Detail    *string           `json:"detail,omitempty"`
+	Error     *string           `json:"error,omitempty"`
+	LatencyMs float64           `json:"latency_ms"`
+	Name      string            `json:"name"`
+	Status    HealthCheckStatus `json:"status"`
+}
+
+// HealthCheckStatus defines model for HealthCheck.Status.
+type HealthCheckStatus string
+
+// HealthResponse defines model for HealthResponse.
+type HealthResponse struct {
+	Checks []HealthCheck        `json:"checks"`
+	Status HealthResponseStatus `json:"status"`
+}
+
+// HealthResponseStatus defines model for HealthResponse.Status.
+type HealthResponseStatus string
+
+// ImportResult defines model for ImportResult.
+type ImportResult struct {
+	// DryRun Изменения проверены, но не сохранены
+	DryRun bool `json:"dry_run"`
+
+	// Members Членств в файле
+	Members int `json:"members"`
+
+	// Teams Команд в файле
+	Teams int `json:"teams"`
+
+	// TeamsCreated Сколько команд будет (или было) создано
+	TeamsCreated int `json:"teams_created"`
+
+	// UsersCreated Сколько пользователей будет (или было) создано
+	UsersCreated int `json:"users_created"`
+}
+
+// LoadOutlier defines model for LoadOutlier.
+type LoadOutlier struct {
+	Assignments int `json:"assignments"`
+
+	// Deviation Отклонение от среднего относительно среднего (0.5 - в полтора раза больше)
+	Deviation float32 `json:"deviation"`
+	UserId    string  `json:"user_id"`
+	Username  string  `json:"username"`
+	ZScore    float32 `json:"z_score"`
+}
+
+// MemberLoad defines model for MemberLoad.
+type MemberLoad struct {
+	// Assignments Назначенных ревью PR команды за окно
+	Assignments int `json:"assignments"`
+
+	// AuthoredPrs Собственных PR команды за окно, на которые участник не мог быть назначен
+	AuthoredPrs int    `json:"authored_prs"`
+	UserId      string `json:"user_id"`
+	Username    string `json:"username"`
+}
+
+// MemberPair defines model for MemberPair.
+type MemberPair struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+// PRReassignments defines model for PRReassignments.
+type PRReassignments struct {
+	PullRequestId string `json:"pull_request_id"`
+	Reassignments int    `json:"reassignments"`
+	TeamName      string `json:"team_name"`
+}
+
 // PRStats defines model for PRStats.
 type PRStats struct {
 	AuthorId        string `json:"author_id"`
@@ -65,6 +352,38 @@ type PRStats struct {
 // PRStatsStatus defines model for PRStats.Status.
 type PRStatsStatus string
 
+// PairMatrix defines model for PairMatrix.
+type PairMatrix struct {
+	From *time.Time `json:"from,omitempty"`
+
+	// Matrix `matrix[i][j]` - сколько ревью PR участника `members[i]` назначено участнику `members[j]`
+	Matrix [][]int `json:"matrix"`
+
+	// Members Активные участники команды по user_id; задают порядок строк и столбцов матрицы
+	Members []PairMember `json:"members"`
+
+	// NeverPaired Пары участников, ни разу не ревьюивших друг друга ни в одну сторону
+	NeverPaired []MemberPair `json:"never_paired"`
+	TeamName    string       `json:"team_name"`
+	To          *time.Time   `json:"to,omitempty"`
+
+	// TopPairs Самые частые пары автор-ревьювер, по убыванию числа назначений
+	TopPairs []ReviewPair `json:"top_pairs"`
+}
+
+// PairMember defines model for PairMember.
+type PairMember struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// Percentiles Перцентили длительности в секундах
+type Percentiles struct {
+	P50 float32 `json:"p50"`
+	P90 float32 `json:"p90"`
+	P99 float32 `json:"p99"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
 	// AssignedReviewers user_id назначенных ревьюверов (0..2)
@@ -75,6 +394,9 @@ type PullRequest struct {
 	PullRequestId     string            `json:"pull_request_id"`
 	PullRequestName   string            `json:"pull_request_name"`
 	Status            PullRequestStatus `json:"status"`
+
+	// TeamName Команда, из которой назначаются ревьюверы
+	TeamName *string `json:"team_name,omitempty"`
 }
 
 // PullRequestStatus defines model for PullRequest.Status.
@@ -91,6 +413,66 @@ type PullRequestShort struct {
 // PullRequestShortStatus defines model for PullRequestShort.Status.
 type PullRequestShortStatus string
 
+// ReviewPair defines model for ReviewPair.
+type ReviewPair struct {
+	Assignments int    `json:"assignments"`
+	AuthorId    string `json:"author_id"`
+	ReviewerId  string `json:"reviewer_id"`
+}
+
+// ReviewReassignment defines model for ReviewReassignment.
+type ReviewReassignment struct {
+	PullRequestId string `json:"pull_request_id"`
+
+	// ReplacedBy user_id нового ревьювера (null, если замены не нашлось)
+	ReplacedBy *string `json:"replaced_by"`
+}
+
+// ReviewSettings defines model for ReviewSettings.
+type ReviewSettings struct {
+	// LookbackDays Учитывать ревью автора, назначенные за последние N дней
+	LookbackDays *int `json:"lookback_days"`
+
+	// LookbackPrs Учитывать ревью последних N PR автора
+	LookbackPrs *int `json:"lookback_prs"`
+
+	// PairingDiversity Выбирать в первую очередь тех, кто реже ревьюил автора за окно; только для стратегии `random`.
+	// Окно задаётся ровно одним из `lookback_days` и `lookback_prs`.
+	PairingDiversity bool `json:"pairing_diversity"`
+
+	// Strategy `random` - случайные участники; `round_robin` - участники по очереди в порядке user_id,
+	// очередь общая для всех PR команды. Если поле не передано в `POST /team/reviewSettings`,
+	// стратегия команды не меняется (у новой команды - `random`).
+	Strategy *ReviewSettingsStrategy `json:"strategy,omitempty"`
+	TeamName string                  `json:"team_name"`
+}
+
+// ReviewSettingsStrategy `random` - случайные участники; `round_robin` - участники по очереди в порядке user_id,
+// очередь общая для всех PR команды. Если поле не передано в `POST /team/reviewSettings`,
+// стратегия команды не меняется (у новой команды - `random`).
+type ReviewSettingsStrategy string
+
+// ReviewerHistory defines model for ReviewerHistory.
+type ReviewerHistory struct {
+	Points []ReviewerHistoryPoint `json:"points"`
+	UserId string                 `json:"user_id"`
+}
+
+// ReviewerHistoryPoint defines model for ReviewerHistoryPoint.
+type ReviewerHistoryPoint struct {
+	AssignedReviews int `json:"assigned_reviews"`
+
+	// MergedReviews PR, смёрженных, пока пользователь был их ревьювером
+	MergedReviews int `json:"merged_reviews"`
+
+	// OpenReviews Открытых ревью на конец последнего дня периода
+	OpenReviews int `json:"open_reviews"`
+
+	// OpenReviewsAvg Среднее за день число открытых ревью
+	OpenReviewsAvg float32            `json:"open_reviews_avg"`
+	PeriodStart    openapi_types.Date `json:"period_start"`
+}
+
 // ReviewerStats defines model for ReviewerStats.
 type ReviewerStats struct {
 	// MergedReviews Merged PR отремотрены
@@ -106,190 +488,2314 @@ type ReviewerStats struct {
 	Username     string `json:"username"`
 }
 
+// ReviewerTiming defines model for ReviewerTiming.
+type ReviewerTiming struct {
+	MergedReviews int `json:"merged_reviews"`
+
+	// TimeToMerge От назначения ревьювера до merge
+	TimeToMerge *Percentiles `json:"time_to_merge"`
+	UserId      string       `json:"user_id"`
+	Username    string       `json:"username"`
+}
+
+// RowError defines model for RowError.
+type RowError struct {
+	// Line Номер строки во входном файле (0, если строку определить не удалось)
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ScimEnterpriseUser defines model for ScimEnterpriseUser.
+type ScimEnterpriseUser struct {
+	// Department Основная команда пользователя
+	Department *string `json:"department,omitempty"`
+}
+
+// ScimError defines model for ScimError.
+type ScimError struct {
+	Detail   *string            `json:"detail,omitempty"`
+	Schemas  []string           `json:"schemas"`
+	ScimType *ScimErrorScimType `json:"scimType,omitempty"`
+	Status   string             `json:"status"`
+}
+
+// ScimErrorScimType defines model for ScimError.ScimType.
+type ScimErrorScimType string
+
+// ScimGroup Группа SCIM - команда; `id` и `displayName` совпадают с `team_name`
+type ScimGroup struct {
+	DisplayName string           `json:"displayName"`
+	ExternalId  *string          `json:"externalId,omitempty"`
+	Id          *string          `json:"id,omitempty"`
+	Members     *[]ScimMemberRef `json:"members,omitempty"`
+	Meta        *ScimMeta        `json:"meta,omitempty"`
+	Schemas     []string         `json:"schemas"`
+}
+
+// ScimListResponse defines model for ScimListResponse.
+type ScimListResponse struct {
+	Resources    []interface{} `json:"Resources"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Schemas      []string      `json:"schemas"`
+	StartIndex   int           `json:"startIndex"`
+	TotalResults int           `json:"totalResults"`
+}
+
+// ScimMemberRef defines model for ScimMemberRef.
+type ScimMemberRef struct {
+	Ref     *string `json:"$ref,omitempty"`
+	Display *string `json:"display,omitempty"`
+	Value   string  `json:"value"`
+}
+
+// ScimMeta defines model for ScimMeta.
+type ScimMeta struct {
+	Location     string               `json:"location"`
+	ResourceType ScimMetaResourceType `json:"resourceType"`
+}
+
+// ScimMetaResourceType defines model for ScimMeta.ResourceType.
+type ScimMetaResourceType string
+
+// ScimName defines model for ScimName.
+type ScimName struct {
+	FamilyName *string `json:"familyName,omitempty"`
+	Formatted  *string `json:"formatted,omitempty"`
+	GivenName  *string `json:"givenName,omitempty"`
+}
+
+// ScimPatchOperation defines model for ScimPatchOperation.
+type ScimPatchOperation struct {
+	// Op add, remove или replace (без учёта регистра)
+	Op    string      `json:"op"`
+	Path  *string     `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ScimPatchRequest defines model for ScimPatchRequest.
+type ScimPatchRequest struct {
+	Operations []ScimPatchOperation `json:"Operations"`
+	Schemas    []string             `json:"schemas"`
+}
+
+// ScimUser Пользователь SCIM. `id` и `userName` совпадают с `user_id`; `displayName`
+// (или `name.formatted`) - это `username`; `active` - глобальный флаг активности.
+type ScimUser struct {
+	Active                                            *bool               `json:"active,omitempty"`
+	DisplayName                                       *string             `json:"displayName,omitempty"`
+	ExternalId                                        *string             `json:"externalId,omitempty"`
+	Groups                                            *[]ScimMemberRef    `json:"groups,omitempty"`
+	Id                                                *string             `json:"id,omitempty"`
+	Meta                                              *ScimMeta           `json:"meta,omitempty"`
+	Name                                              *ScimName           `json:"name,omitempty"`
+	Schemas                                           []string            `json:"schemas"`
+	UrnIetfParamsScimSchemasExtensionEnterprise20User *ScimEnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	UserName                                          string              `json:"userName"`
+}
+
+// StatisticsHistoryResponse defines model for StatisticsHistoryResponse.
+type StatisticsHistoryResponse struct {
+	From        openapi_types.Date                   `json:"from"`
+	Granularity StatisticsHistoryResponseGranularity `json:"granularity"`
+	Reviewers   []ReviewerHistory                    `json:"reviewers"`
+	TeamName    *string                              `json:"team_name,omitempty"`
+	Teams       []TeamHistory                        `json:"teams"`
+	To          openapi_types.Date                   `json:"to"`
+}
+
+// StatisticsHistoryResponseGranularity defines model for StatisticsHistoryResponse.Granularity.
+type StatisticsHistoryResponseGranularity string
+
 // StatisticsResponse defines model for StatisticsResponse.
 type StatisticsResponse struct {
-	PrStats        []PRStats       `json:"pr_stats"`
+	// PrStats Страница PR, начиная с самых новых
+	PrStats []PRStats `json:"pr_stats"`
+
+	// ReviewersStats Страница ревьюверов по убыванию числа ревью
 	ReviewersStats []ReviewerStats `json:"reviewers_stats"`
 
-	// TotalPrs Всего PR в системе
+	// TotalPrs Всего PR, подходящих под фильтры
 	TotalPrs int `json:"total_prs"`
 
-	// TotalReviewers Всего пользователей
+	// TotalReviewers Всего ревьюверов, подходящих под фильтры
 	TotalReviewers int `json:"total_reviewers"`
 }
 
+// SubtreeStatisticsResponse defines model for SubtreeStatisticsResponse.
+type SubtreeStatisticsResponse struct {
+	RootTeam string      `json:"root_team"`
+	Units    []UnitStats `json:"units"`
+}
+
+// SyncPlan defines model for SyncPlan.
+type SyncPlan struct {
+	AffectedReviews []AffectedReview `json:"affected_reviews"`
+
+	// PlanId Отпечаток плана; передайте его в `/admin/sync/apply`, чтобы применить именно этот план
+	PlanId string           `json:"plan_id"`
+	Teams  []SyncTeamChange `json:"teams"`
+	Users  []SyncUserChange `json:"users"`
+}
+
+// SyncReassignment defines model for SyncReassignment.
+type SyncReassignment struct {
+	PullRequestId string `json:"pull_request_id"`
+
+	// ReplacedBy Новый ревьювер (null, если замены не нашлось)
+	ReplacedBy *string `json:"replaced_by"`
+	ReviewerId string  `json:"reviewer_id"`
+}
+
+// SyncTeamChange defines model for SyncTeamChange.
+type SyncTeamChange struct {
+	Action   SyncTeamChangeAction `json:"action"`
+	TeamName string               `json:"team_name"`
+}
+
+// SyncTeamChangeAction defines model for SyncTeamChange.Action.
+type SyncTeamChangeAction string
+
+// SyncUserChange defines model for SyncUserChange.
+type SyncUserChange struct {
+	Action    SyncUserChangeAction `json:"action"`
+	FromTeams []string             `json:"from_teams"`
+
+	// PrimaryTeam Основная команда после применения (для add, move, update)
+	PrimaryTeam *string  `json:"primary_team,omitempty"`
+	Reason      *string  `json:"reason,omitempty"`
+	ToTeams     []string `json:"to_teams"`
+	UserId      string   `json:"user_id"`
+	Username    string   `json:"username"`
+}
+
+// SyncUserChangeAction defines model for SyncUserChange.Action.
+type SyncUserChangeAction string
+
 // Team defines model for Team.
 type Team struct {
 	Members  []TeamMember `json:"members"`
 	TeamName string       `json:"team_name"`
 }
 
+// TeamFairness defines model for TeamFairness.
+type TeamFairness struct {
+	Assignments int `json:"assignments"`
+
+	// Distribution Участники по убыванию нагрузки
+	Distribution []MemberLoad `json:"distribution"`
+
+	// Gini Коэффициент Джини, 0 - нагрузка поровну
+	Gini float32 `json:"gini"`
+
+	// MaxMinRatio Отношение максимума к минимуму; null, если кому-то не досталось ни одного ревью
+	MaxMinRatio *float32 `json:"max_min_ratio"`
+	Mean        float32  `json:"mean"`
+
+	// Members Активных участников команды
+	Members  int           `json:"members"`
+	Outliers []LoadOutlier `json:"outliers"`
+
+	// Stddev Стандартное отклонение по всем участникам
+	Stddev   float32 `json:"stddev"`
+	TeamName string  `json:"team_name"`
+}
+
+// TeamHistory defines model for TeamHistory.
+type TeamHistory struct {
+	Points   []TeamHistoryPoint `json:"points"`
+	TeamName string             `json:"team_name"`
+}
+
+// TeamHistoryPoint defines model for TeamHistoryPoint.
+type TeamHistoryPoint struct {
+	CreatedPrs int `json:"created_prs"`
+	MergedPrs  int `json:"merged_prs"`
+
+	// OpenPrs Открытых PR на конец последнего дня периода
+	OpenPrs int `json:"open_prs"`
+
+	// OpenPrsAvg Среднее за день число открытых PR
+	OpenPrsAvg  float32            `json:"open_prs_avg"`
+	PeriodStart openapi_types.Date `json:"period_start"`
+}
+
 // TeamMember defines model for TeamMember.
 type TeamMember struct {
-	IsActive bool   `json:"is_active"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	// IsActive Активен ли участник в этой команде
+	IsActive bool `json:"is_active"`
+
+	// Role Роль участника в команде. Для токенов, привязанных к пользователю:
+	// lead и admin управляют участниками своей команды и переназначают ревьюверов на её PR,
+	// member может только снять с PR самого себя. Роль admin может выдать только admin.
+	Role     *TeamRole `json:"role,omitempty"`
+	UserId   string    `json:"user_id"`
+	Username string    `json:"username"`
 }
 
-// User defines model for User.
-type User struct {
-	IsActive bool   `json:"is_active"`
-	TeamName string `json:"team_name"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
-}
+// TeamMembership defines model for TeamMembership.
+type TeamMembership struct {
+	IsActive bool `json:"is_active"`
 
-// TeamNameQuery defines model for TeamNameQuery.
-type TeamNameQuery = string
+	// Role Роль участника в команде. Для токенов, привязанных к пользователю:
+	// lead и admin управляют участниками своей команды и переназначают ревьюверов на её PR,
+	// member может только снять с PR самого себя. Роль admin может выдать только admin.
+	Role     TeamRole `json:"role"`
+	TeamName string   `json:"team_name"`
+}
 
-// UserIdQuery defines model for UserIdQuery.
-type UserIdQuery = string
+// TeamNode defines model for TeamNode.
+type TeamNode struct {
+	Children   *[]TeamNode `json:"children,omitempty"`
+	ParentTeam *string     `json:"parent_team"`
+	TeamName   string      `json:"team_name"`
+}
 
-// PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
-type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id"`
-	PullRequestId   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
+// TeamRole Роль участника в команде. Для токенов, привязанных к пользователю:
+// lead и admin управляют участниками своей команды и переназначают ревьюверов на её PR,
+// member может только снять с PR самого себя. Роль admin может выдать только admin.
+type TeamRole string
+
+// TeamTiming defines model for TeamTiming.
+type TeamTiming struct {
+	MergedPrs             int          `json:"merged_prs"`
+	Reassignments         int          `json:"reassignments"`
+	TeamName              string       `json:"team_name"`
+	TimeToFirstAssignment *Percentiles `json:"time_to_first_assignment"`
+	TimeToMerge           *Percentiles `json:"time_to_merge"`
+	TotalPrs              int          `json:"total_prs"`
 }
 
-// PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
-type PostPullRequestMergeJSONBody struct {
-	PullRequestId string `json:"pull_request_id"`
+// TimingStatisticsResponse defines model for TimingStatisticsResponse.
+type TimingStatisticsResponse struct {
+	From *time.Time `json:"from,omitempty"`
+
+	// PullRequests PR, у которых менялся ревьювер, по убыванию числа переназначений
+	PullRequests []PRReassignments `json:"pull_requests"`
+	Reviewers    []ReviewerTiming  `json:"reviewers"`
+	Summary      TimingSummary     `json:"summary"`
+	Teams        []TeamTiming      `json:"teams"`
+	To           *time.Time        `json:"to,omitempty"`
 }
 
-// PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
-type PostPullRequestReassignJSONBody struct {
-	OldUserId     string `json:"old_user_id"`
-	PullRequestId string `json:"pull_request_id"`
+// TimingSummary defines model for TimingSummary.
+type TimingSummary struct {
+	MergedPrs int `json:"merged_prs"`
+
+	// MergedUnderTwoReviewers Смёрженные PR, у которых меньше двух ревьюверов
+	MergedUnderTwoReviewers int `json:"merged_under_two_reviewers"`
+
+	// MergedUnderTwoReviewersShare Доля таких PR среди смёрженных (от 0 до 1)
+	MergedUnderTwoReviewersShare float32 `json:"merged_under_two_reviewers_share"`
+	Reassignments                int     `json:"reassignments"`
+	ReassignmentsPerPr           float32 `json:"reassignments_per_pr"`
+
+	// TimeToFirstAssignment От создания PR до первого назначения ревьювера
+	TimeToFirstAssignment *Percentiles `json:"time_to_first_assignment"`
+
+	// TimeToMerge От создания PR до merge
+	TimeToMerge *Percentiles `json:"time_to_merge"`
+	TotalPrs    int          `json:"total_prs"`
+
+	// UnassignedPrs PR, которым ни разу не был назначен ревьювер
+	UnassignedPrs int `json:"unassigned_prs"`
 }
 
-// GetTeamGetParams defines parameters for GetTeamGet.
-type GetTeamGetParams struct {
-	// TeamName Уникальное имя команды
-	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+// TokenScope defines model for TokenScope.
+type TokenScope string
+
+// UnitStats defines model for UnitStats.
+type UnitStats struct {
+	// Depth Глубина относительно корня поддерева
+	Depth      int        `json:"depth"`
+	Members    int        `json:"members"`
+	MergedPrs  int        `json:"merged_prs"`
+	OpenPrs    int        `json:"open_prs"`
+	ParentTeam *string    `json:"parent_team"`
+	Subtree    UnitTotals `json:"subtree"`
+	TeamName   string     `json:"team_name"`
+	TotalPrs   int        `json:"total_prs"`
 }
 
-// GetUsersGetReviewParams defines parameters for GetUsersGetReview.
-type GetUsersGetReviewParams struct {
-	// UserId Идентификатор пользователя
-	UserId UserIdQuery `form:"user_id" json:"user_id"`
+// UnitTotals defines model for UnitTotals.
+type UnitTotals struct {
+	// Members Уникальные участники всего поддерева
+	Members   int `json:"members"`
+	MergedPrs int `json:"merged_prs"`
+	OpenPrs   int `json:"open_prs"`
+	Teams     int `json:"teams"`
+	TotalPrs  int `json:"total_prs"`
 }
 
-// PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
-type PostUsersSetIsActiveJSONBody struct {
-	IsActive bool   `json:"is_active"`
-	UserId   string `json:"user_id"`
+// User defines model for User.
+type User struct {
+	IsActive bool `json:"is_active"`
+
+	// TeamName Основная команда пользователя
+	TeamName string `json:"team_name"`
+
+	// Teams Все команды, в которых состоит пользователь
+	Teams    *[]TeamMembership `json:"teams,omitempty"`
+	UserId   string            `json:"user_id"`
+	Username string            `json:"username"`
 }
 
-// PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
-type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
+// FromQuery defines model for FromQuery.
+type FromQuery = time.Time
+
+// GranularityQuery defines model for GranularityQuery.
+type GranularityQuery string
+
+// HistoryFromQuery defines model for HistoryFromQuery.
+type HistoryFromQuery = openapi_types.Date
+
+// HistoryToQuery defines model for HistoryToQuery.
+type HistoryToQuery = openapi_types.Date
+
+// MissingUsersQuery defines model for MissingUsersQuery.
+type MissingUsersQuery string
+
+// PRLimitQuery defines model for PRLimitQuery.
+type PRLimitQuery = int
+
+// PROffsetQuery defines model for PROffsetQuery.
+type PROffsetQuery = int
+
+// ReviewersLimitQuery defines model for ReviewersLimitQuery.
+type ReviewersLimitQuery = int
+
+// ReviewersOffsetQuery defines model for ReviewersOffsetQuery.
+type ReviewersOffsetQuery = int
+
+// ScimCountQuery defines model for ScimCountQuery.
+type ScimCountQuery = int
+
+// ScimFilterQuery defines model for ScimFilterQuery.
+type ScimFilterQuery = string
+
+// ScimIdPath defines model for ScimIdPath.
+type ScimIdPath = string
+
+// ScimStartIndexQuery defines model for ScimStartIndexQuery.
+type ScimStartIndexQuery = int
+
+// StatusQuery defines model for StatusQuery.
+type StatusQuery string
+
+// TeamFilterQuery defines model for TeamFilterQuery.
+type TeamFilterQuery = string
+
+// TeamNameQuery defines model for TeamNameQuery.
+type TeamNameQuery = string
+
+// ToQuery defines model for ToQuery.
+type ToQuery = time.Time
+
+// ToleranceQuery defines model for ToleranceQuery.
+type ToleranceQuery = float32
+
+// UserIdQuery defines model for UserIdQuery.
+type UserIdQuery = string
+
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = ErrorResponse
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = ErrorResponse
+
+// GetAdminExportParams defines parameters for GetAdminExport.
+type GetAdminExportParams struct {
+	Format *GetAdminExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetAdminExportParamsFormat defines parameters for GetAdminExport.
+type GetAdminExportParamsFormat string
+
+// PostAdminImportParams defines parameters for PostAdminImport.
+type PostAdminImportParams struct {
+	// DryRun Только проверить файл и посчитать изменения, ничего не сохраняя
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// Format Формат файла, если он не задан в `Content-Type`
+	Format *PostAdminImportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// PostAdminImportParamsFormat defines parameters for PostAdminImport.
+type PostAdminImportParamsFormat string
+
+// PostAdminSyncApplyParams defines parameters for PostAdminSyncApply.
+type PostAdminSyncApplyParams struct {
+	// MissingUsers Что делать с пользователями, которых нет в желаемом состоянии: деактивировать
+	// или удалить (авторов PR и ревьюверов смёрженных PR удалить нельзя, они деактивируются)
+	MissingUsers *PostAdminSyncApplyParamsMissingUsers `form:"missing_users,omitempty" json:"missing_users,omitempty"`
+
+	// PlanId Идентификатор проверенного плана
+	PlanId *string `form:"plan_id,omitempty" json:"plan_id,omitempty"`
+}
+
+// PostAdminSyncApplyParamsMissingUsers defines parameters for PostAdminSyncApply.
+type PostAdminSyncApplyParamsMissingUsers string
+
+// PostAdminSyncPlanParams defines parameters for PostAdminSyncPlan.
+type PostAdminSyncPlanParams struct {
+	// MissingUsers Что делать с пользователями, которых нет в желаемом состоянии: деактивировать
+	// или удалить (авторов PR и ревьюверов смёрженных PR удалить нельзя, они деактивируются)
+	MissingUsers *PostAdminSyncPlanParamsMissingUsers `form:"missing_users,omitempty" json:"missing_users,omitempty"`
+}
+
+// PostAdminSyncPlanParamsMissingUsers defines parameters for PostAdminSyncPlan.
+type PostAdminSyncPlanParamsMissingUsers string
+
+// PostAuthTokensCreateJSONBody defines parameters for PostAuthTokensCreate.
+type PostAuthTokensCreateJSONBody struct {
+	Name   string       `json:"name"`
+	Scopes []TokenScope `json:"scopes"`
+
+	// UserId Привязать токен к пользователю: тогда его действия дополнительно
+	// ограничены ролями пользователя в командах
+	UserId *string `json:"user_id,omitempty"`
+}
+
+// PostAuthTokensRevokeJSONBody defines parameters for PostAuthTokensRevoke.
+type PostAuthTokensRevokeJSONBody struct {
+	TokenId string `json:"token_id"`
+}
+
+// PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
+type PostPullRequestCreateJSONBody struct {
+	AuthorId        string `json:"author_id"`
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+
+	// TeamName Команда, для которой создаётся PR (по умолчанию основная команда автора)
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// GetPullRequestGetParams defines parameters for GetPullRequestGet.
+type GetPullRequestGetParams struct {
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
+type PostPullRequestMergeJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
+type PostPullRequestReassignJSONBody struct {
+	// Escalate Если в команде PR нет кандидатов, искать их в родительских подразделениях
+	Escalate      *bool  `json:"escalate,omitempty"`
+	OldUserId     string `json:"old_user_id"`
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// GetScimV2GroupsParams defines parameters for GetScimV2Groups.
+type GetScimV2GroupsParams struct {
+	// Filter Фильтр вида `атрибут eq "значение"`. Пользователи: `id`, `userName`, `displayName`,
+	// `active`; группы: `id`, `displayName`
+	Filter     *ScimFilterQuery     `form:"filter,omitempty" json:"filter,omitempty"`
+	StartIndex *ScimStartIndexQuery `form:"startIndex,omitempty" json:"startIndex,omitempty"`
+	Count      *ScimCountQuery      `form:"count,omitempty" json:"count,omitempty"`
+}
+
+// GetScimV2UsersParams defines parameters for GetScimV2Users.
+type GetScimV2UsersParams struct {
+	// Filter Фильтр вида `атрибут eq "значение"`. Пользователи: `id`, `userName`, `displayName`,
+	// `active`; группы: `id`, `displayName`
+	Filter     *ScimFilterQuery     `form:"filter,omitempty" json:"filter,omitempty"`
+	StartIndex *ScimStartIndexQuery `form:"startIndex,omitempty" json:"startIndex,omitempty"`
+	Count      *ScimCountQuery      `form:"count,omitempty" json:"count,omitempty"`
+}
+
+// GetStatisticsParams defines parameters for GetStatistics.
+type GetStatisticsParams struct {
+	// TeamName Учитывать только PR команды и ревьюверов из её участников
+	TeamName *TeamFilterQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// From Учитывать PR, созданные не раньше этого момента (RFC 3339)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Учитывать PR, созданные раньше этого момента (RFC 3339)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// Status Учитывать только PR в этом статусе
+	Status *GetStatisticsParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// PrLimit Размер страницы pr_stats
+	PrLimit *PRLimitQuery `form:"pr_limit,omitempty" json:"pr_limit,omitempty"`
+
+	// PrOffset Сколько записей pr_stats пропустить
+	PrOffset *PROffsetQuery `form:"pr_offset,omitempty" json:"pr_offset,omitempty"`
+
+	// ReviewersLimit Размер страницы reviewers_stats
+	ReviewersLimit *ReviewersLimitQuery `form:"reviewers_limit,omitempty" json:"reviewers_limit,omitempty"`
+
+	// ReviewersOffset Сколько записей reviewers_stats пропустить
+	ReviewersOffset *ReviewersOffsetQuery `form:"reviewers_offset,omitempty" json:"reviewers_offset,omitempty"`
+}
+
+// GetStatisticsParamsStatus defines parameters for GetStatistics.
+type GetStatisticsParamsStatus string
+
+// GetStatisticsFairnessParams defines parameters for GetStatisticsFairness.
+type GetStatisticsFairnessParams struct {
+	// TeamName Учитывать только PR команды и ревьюверов из её участников
+	TeamName *TeamFilterQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// From Учитывать PR, созданные не раньше этого момента (RFC 3339)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Учитывать PR, созданные раньше этого момента (RFC 3339)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// Tolerance Насколько нагрузка участника может отличаться от средней по команде, прежде чем он попадёт в выбросы (0.5 - на 50%)
+	Tolerance *ToleranceQuery `form:"tolerance,omitempty" json:"tolerance,omitempty"`
+}
+
+// GetStatisticsHistoryParams defines parameters for GetStatisticsHistory.
+type GetStatisticsHistoryParams struct {
+	// TeamName Учитывать только PR команды и ревьюверов из её участников
+	TeamName *TeamFilterQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// From Первый день ряда (UTC), по умолчанию - 90 дней, 26 недель или 12 месяцев до `to`
+	From *HistoryFromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Последний день ряда включительно (UTC), по умолчанию - вчера
+	To *HistoryToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// Granularity Шаг ряда; недели начинаются с понедельника
+	Granularity *GetStatisticsHistoryParamsGranularity `form:"granularity,omitempty" json:"granularity,omitempty"`
+}
+
+// GetStatisticsHistoryParamsGranularity defines parameters for GetStatisticsHistory.
+type GetStatisticsHistoryParamsGranularity string
+
+// GetStatisticsPairsParams defines parameters for GetStatisticsPairs.
+type GetStatisticsPairsParams struct {
+	TeamName string `form:"team_name" json:"team_name"`
+
+	// From Учитывать PR, созданные не раньше этого момента (RFC 3339)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Учитывать PR, созданные раньше этого момента (RFC 3339)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// Top Сколько самых частых пар вернуть
+	Top    *int                            `form:"top,omitempty" json:"top,omitempty"`
+	Format *GetStatisticsPairsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetStatisticsPairsParamsFormat defines parameters for GetStatisticsPairs.
+type GetStatisticsPairsParamsFormat string
+
+// GetStatisticsSubtreeParams defines parameters for GetStatisticsSubtree.
+type GetStatisticsSubtreeParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// GetStatisticsTimingParams defines parameters for GetStatisticsTiming.
+type GetStatisticsTimingParams struct {
+	// From Учитывать PR, созданные не раньше этого момента (RFC 3339)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Учитывать PR, созданные раньше этого момента (RFC 3339)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetTeamGetParams defines parameters for GetTeamGet.
+type GetTeamGetParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostTeamRenameJSONBody defines parameters for PostTeamRename.
+type PostTeamRenameJSONBody struct {
+	NewTeamName string `json:"new_team_name"`
+	TeamName    string `json:"team_name"`
+}
+
+// GetTeamReviewSettingsParams defines parameters for GetTeamReviewSettings.
+type GetTeamReviewSettingsParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostTeamSetParentJSONBody defines parameters for PostTeamSetParent.
+type PostTeamSetParentJSONBody struct {
+	// ParentTeam null или отсутствие поля отвязывает команду
+	ParentTeam *string `json:"parent_team"`
+	TeamName   string  `json:"team_name"`
+}
+
+// GetTeamSubtreeParams defines parameters for GetTeamSubtree.
+type GetTeamSubtreeParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostUsersCreateJSONBody defines parameters for PostUsersCreate.
+type PostUsersCreateJSONBody struct {
+	IsActive *bool  `json:"is_active,omitempty"`
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// PostUsersDeleteJSONBody defines parameters for PostUsersDelete.
+type PostUsersDeleteJSONBody struct {
+	UserId string `json:"user_id"`
+}
+
+// GetUsersGetParams defines parameters for GetUsersGet.
+type GetUsersGetParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// GetUsersGetReviewParams defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
+type PostUsersSetIsActiveJSONBody struct {
+	IsActive bool   `json:"is_active"`
+	UserId   string `json:"user_id"`
+}
+
+// PostUsersUpdateJSONBody defines parameters for PostUsersUpdate.
+type PostUsersUpdateJSONBody struct {
+	TeamName *string `json:"team_name,omitempty"`
+	UserId   string  `json:"user_id"`
+	Username *string `json:"username,omitempty"`
+}
+
+// PostAuthTokensCreateJSONRequestBody defines body for PostAuthTokensCreate for application/json ContentType.
+type PostAuthTokensCreateJSONRequestBody PostAuthTokensCreateJSONBody
+
+// PostAuthTokensRevokeJSONRequestBody defines body for PostAuthTokensRevoke for application/json ContentType.
+type PostAuthTokensRevokeJSONRequestBody PostAuthTokensRevokeJSONBody
+
+// PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
+type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
+
+// PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
+type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
+
+// PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
+type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
+
+// PostScimV2GroupsApplicationScimPlusJSONRequestBody defines body for PostScimV2Groups for application/scim+json ContentType.
+type PostScimV2GroupsApplicationScimPlusJSONRequestBody = ScimGroup
+
+// PatchScimV2GroupsIdApplicationScimPlusJSONRequestBody defines body for PatchScimV2GroupsId for application/scim+json ContentType.
+type PatchScimV2GroupsIdApplicationScimPlusJSONRequestBody = ScimPatchRequest
+
+// PostScimV2UsersApplicationScimPlusJSONRequestBody defines body for PostScimV2Users for application/scim+json ContentType.
+type PostScimV2UsersApplicationScimPlusJSONRequestBody = ScimUser
+
+// PatchScimV2UsersIdApplicationScimPlusJSONRequestBody defines body for PatchScimV2UsersId for application/scim+json ContentType.
+type PatchScimV2UsersIdApplicationScimPlusJSONRequestBody = ScimPatchRequest
+
+// PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
+type PostTeamAddJSONRequestBody = Team
+
+// PostTeamRenameJSONRequestBody defines body for PostTeamRename for application/json ContentType.
+type PostTeamRenameJSONRequestBody PostTeamRenameJSONBody
+
+// PostTeamReviewSettingsJSONRequestBody defines body for PostTeamReviewSettings for application/json ContentType.
+type PostTeamReviewSettingsJSONRequestBody = ReviewSettings
+
+// PostTeamSetParentJSONRequestBody defines body for PostTeamSetParent for application/json ContentType.
+type PostTeamSetParentJSONRequestBody PostTeamSetParentJSONBody
+
+// PostUsersCreateJSONRequestBody defines body for PostUsersCreate for application/json ContentType.
+type PostUsersCreateJSONRequestBody PostUsersCreateJSONBody
+
+// PostUsersDeleteJSONRequestBody defines body for PostUsersDelete for application/json ContentType.
+type PostUsersDeleteJSONRequestBody PostUsersDeleteJSONBody
+
+// PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
+type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+
+// PostUsersUpdateJSONRequestBody defines body for PostUsersUpdate for application/json ContentType.
+type PostUsersUpdateJSONRequestBody PostUsersUpdateJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Выгрузить все команды и участников в CSV или YAML
+	// (GET /admin/export)
+	GetAdminExport(w http.ResponseWriter, r *http.Request, params GetAdminExportParams)
+	// Массово импортировать команды и пользователей из CSV или YAML
+	// (POST /admin/import)
+	PostAdminImport(w http.ResponseWriter, r *http.Request, params PostAdminImportParams)
+	// Пересчитать счётчики статистики
+	// (POST /admin/statistics/rebuild)
+	PostAdminStatisticsRebuild(w http.ResponseWriter, r *http.Request)
+	// Привести оргструктуру к желаемому состоянию
+	// (POST /admin/sync/apply)
+	PostAdminSyncApply(w http.ResponseWriter, r *http.Request, params PostAdminSyncApplyParams)
+	// Сравнить желаемое состояние оргструктуры с текущим
+	// (POST /admin/sync/plan)
+	PostAdminSyncPlan(w http.ResponseWriter, r *http.Request, params PostAdminSyncPlanParams)
+	// Выпустить API-токен
+	// (POST /auth/tokens/create)
+	PostAuthTokensCreate(w http.ResponseWriter, r *http.Request)
+	// Отозвать API-токен
+	// (POST /auth/tokens/revoke)
+	PostAuthTokensRevoke(w http.ResponseWriter, r *http.Request)
+	// Процесс жив (liveness probe)
+	// (GET /health/live)
+	GetHealthLive(w http.ResponseWriter, r *http.Request)
+	// Сервис готов принимать трафик (readiness probe)
+	// (GET /health/ready)
+	GetHealthReady(w http.ResponseWriter, r *http.Request)
+	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
+	// (POST /pullRequest/create)
+	PostPullRequestCreate(w http.ResponseWriter, r *http.Request)
+	// Получить PR с назначенными ревьюверами
+	// (GET /pullRequest/get)
+	GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams)
+	// Пометить PR как MERGED (идемпотентная операция)
+	// (POST /pullRequest/merge)
+	PostPullRequestMerge(w http.ResponseWriter, r *http.Request)
+	// Переназначить конкретного ревьювера на другого из его команды
+	// (POST /pullRequest/reassign)
+	PostPullRequestReassign(w http.ResponseWriter, r *http.Request)
+	// Список групп (команд)
+	// (GET /scim/v2/Groups)
+	GetScimV2Groups(w http.ResponseWriter, r *http.Request, params GetScimV2GroupsParams)
+	// Создать группу
+	// (POST /scim/v2/Groups)
+	PostScimV2Groups(w http.ResponseWriter, r *http.Request)
+	// Удалить группу
+	// (DELETE /scim/v2/Groups/{id})
+	DeleteScimV2GroupsId(w http.ResponseWriter, r *http.Request, id ScimIdPath)
+	// Получить группу
+	// (GET /scim/v2/Groups/{id})
+	GetScimV2GroupsId(w http.ResponseWriter, r *http.Request, id ScimIdPath)
+	// Изменить состав группы
+	// (PATCH /scim/v2/Groups/{id})
+	PatchScimV2GroupsId(w http.ResponseWriter, r *http.Request, id ScimIdPath)
+	// Возможности SCIM-сервера
+	// (GET /scim/v2/ServiceProviderConfig)
+	GetScimV2ServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+	// Список пользователей
+	// (GET /scim/v2/Users)
+	GetScimV2Users(w http.ResponseWriter, r *http.Request, params GetScimV2UsersParams)
+	// Создать пользователя
+	// (POST /scim/v2/Users)
+	PostScimV2Users(w http.ResponseWriter, r *http.Request)
+	// Деактивировать пользователя
+	// (DELETE /scim/v2/Users/{id})
+	DeleteScimV2UsersId(w http.ResponseWriter, r *http.Request, id ScimIdPath)
+	// Получить пользователя
+	// (GET /scim/v2/Users/{id})
+	GetScimV2UsersId(w http.ResponseWriter, r *http.Request, id ScimIdPath)
+	// Изменить пользователя
+	// (PATCH /scim/v2/Users/{id})
+	PatchScimV2UsersId(w http.ResponseWriter, r *http.Request, id ScimIdPath)
+	// Получить статистику по PR и ревьюверам
+	// (GET /statistics)
+	GetStatistics(w http.ResponseWriter, r *http.Request, params GetStatisticsParams)
+	// Получить равномерность распределения ревью в командах
+	// (GET /statistics/fairness)
+	GetStatisticsFairness(w http.ResponseWriter, r *http.Request, params GetStatisticsFairnessParams)
+	// Получить динамику нагрузки по дням, неделям или месяцам
+	// (GET /statistics/history)
+	GetStatisticsHistory(w http.ResponseWriter, r *http.Request, params GetStatisticsHistoryParams)
+	// Получить матрицу пар автор-ревьювер команды
+	// (GET /statistics/pairs)
+	GetStatisticsPairs(w http.ResponseWriter, r *http.Request, params GetStatisticsPairsParams)
+	// Получить статистику по поддереву подразделений
+	// (GET /statistics/subtree)
+	GetStatisticsSubtree(w http.ResponseWriter, r *http.Request, params GetStatisticsSubtreeParams)
+	// Временные метрики ревью
+	// (GET /statistics/timing)
+	GetStatisticsTiming(w http.ResponseWriter, r *http.Request, params GetStatisticsTimingParams)
+	// Создать команду с участниками (создаёт/обновляет пользователей)
+	// (POST /team/add)
+	PostTeamAdd(w http.ResponseWriter, r *http.Request)
+	// Получить команду с участниками
+	// (GET /team/get)
+	GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams)
+	// Переименовать команду
+	// (POST /team/rename)
+	PostTeamRename(w http.ResponseWriter, r *http.Request)
+	// Получить настройки выбора ревьюверов команды
+	// (GET /team/reviewSettings)
+	GetTeamReviewSettings(w http.ResponseWriter, r *http.Request, params GetTeamReviewSettingsParams)
+	// Изменить настройки выбора ревьюверов команды
+	// (POST /team/reviewSettings)
+	PostTeamReviewSettings(w http.ResponseWriter, r *http.Request)
+	// Привязать команду к родительскому подразделению (или отвязать)
+	// (POST /team/setParent)
+	PostTeamSetParent(w http.ResponseWriter, r *http.Request)
+	// Получить поддерево подразделений начиная с команды
+	// (GET /team/subtree)
+	GetTeamSubtree(w http.ResponseWriter, r *http.Request, params GetTeamSubtreeParams)
+	// Создать пользователя в существующей команде
+	// (POST /users/create)
+	PostUsersCreate(w http.ResponseWriter, r *http.Request)
+	// Удалить пользователя (открытые ревью переназначаются)
+	// (POST /users/delete)
+	PostUsersDelete(w http.ResponseWriter, r *http.Request)
+	// Получить пользователя
+	// (GET /users/get)
+	GetUsersGet(w http.ResponseWriter, r *http.Request, params GetUsersGetParams)
+	// Получить PR'ы, где пользователь назначен ревьювером
+	// (GET /users/getReview)
+	GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams)
+	// Установить флаг активности пользователя
+	// (POST /users/setIsActive)
+	PostUsersSetIsActive(w http.ResponseWriter, r *http.Request)
+	// Изменить имя и/или команду пользователя
+	// (POST /users/update)
+	PostUsersUpdate(w http.ResponseWriter, r *http.Request)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// Выгрузить все команды и участников в CSV или YAML
+// (GET /admin/export)
+func (_ Unimplemented) GetAdminExport(w http.ResponseWriter, r *http.Request, params GetAdminExportParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Массово импортировать команды и пользователей из CSV или YAML
+// (POST /admin/import)
+func (_ Unimplemented) PostAdminImport(w http.ResponseWriter, r *http.Request, params PostAdminImportParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Пересчитать счётчики статистики
+// (POST /admin/statistics/rebuild)
+func (_ Unimplemented) PostAdminStatisticsRebuild(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Привести оргструктуру к желаемому состоянию
+// (POST /admin/sync/apply)
+func (_ Unimplemented) PostAdminSyncApply(w http.ResponseWriter, r *http.Request, params PostAdminSyncApplyParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сравнить желаемое состояние оргструктуры с текущим
+// (POST /admin/sync/plan)
+func (_ Unimplemented) PostAdminSyncPlan(w http.ResponseWriter, r *http.Request, params PostAdminSyncPlanParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Выпустить API-токен
+// (POST /auth/tokens/create)
+func (_ Unimplemented) PostAuthTokensCreate(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отозвать API-токен
+// (POST /auth/tokens/revoke)
+func (_ Unimplemented) PostAuthTokensRevoke(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Процесс жив (liveness probe)
+// (GET /health/live)
+func (_ Unimplemented) GetHealthLive(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сервис готов принимать трафик (readiness probe)
+// (GET /health/ready)
+func (_ Unimplemented) GetHealthReady(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
+// (POST /pullRequest/create)
+func (_ Unimplemented) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить PR с назначенными ревьюверами
+// (GET /pullRequest/get)
+func (_ Unimplemented) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Пометить PR как MERGED (идемпотентная операция)
+// (POST /pullRequest/merge)
+func (_ Unimplemented) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переназначить конкретного ревьювера на другого из его команды
+// (POST /pullRequest/reassign)
+func (_ Unimplemented) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Список групп (команд)
+// (GET /scim/v2/Groups)
+func (_ Unimplemented) GetScimV2Groups(w http.ResponseWriter, r *http.Request, params GetScimV2GroupsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать группу
+// (POST /scim/v2/Groups)
+func (_ Unimplemented) PostScimV2Groups(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить группу
+// (DELETE /scim/v2/Groups/{id})
+func (_ Unimplemented) DeleteScimV2GroupsId(w http.ResponseWriter, r *http.Request, id ScimIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить группу
+// (GET /scim/v2/Groups/{id})
+func (_ Unimplemented) GetScimV2GroupsId(w http.ResponseWriter, r *http.Request, id ScimIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменить состав группы
+// (PATCH /scim/v2/Groups/{id})
+func (_ Unimplemented) PatchScimV2GroupsId(w http.ResponseWriter, r *http.Request, id ScimIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Возможности SCIM-сервера
+// (GET /scim/v2/ServiceProviderConfig)
+func (_ Unimplemented) GetScimV2ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Список пользователей
+// (GET /scim/v2/Users)
+func (_ Unimplemented) GetScimV2Users(w http.ResponseWriter, r *http.Request, params GetScimV2UsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать пользователя
+// (POST /scim/v2/Users)
+func (_ Unimplemented) PostScimV2Users(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Деактивировать пользователя
+// (DELETE /scim/v2/Users/{id})
+func (_ Unimplemented) DeleteScimV2UsersId(w http.ResponseWriter, r *http.Request, id ScimIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить пользователя
+// (GET /scim/v2/Users/{id})
+func (_ Unimplemented) GetScimV2UsersId(w http.ResponseWriter, r *http.Request, id ScimIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменить пользователя
+// (PATCH /scim/v2/Users/{id})
+func (_ Unimplemented) PatchScimV2UsersId(w http.ResponseWriter, r *http.Request, id ScimIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить статистику по PR и ревьюверам
+// (GET /statistics)
+func (_ Unimplemented) GetStatistics(w http.ResponseWriter, r *http.Request, params GetStatisticsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить равномерность распределения ревью в командах
+// (GET /statistics/fairness)
+func (_ Unimplemented) GetStatisticsFairness(w http.ResponseWriter, r *http.Request, params GetStatisticsFairnessParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить динамику нагрузки по дням, неделям или месяцам
+// (GET /statistics/history)
+func (_ Unimplemented) GetStatisticsHistory(w http.ResponseWriter, r *http.Request, params GetStatisticsHistoryParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить матрицу пар автор-ревьювер команды
+// (GET /statistics/pairs)
+func (_ Unimplemented) GetStatisticsPairs(w http.ResponseWriter, r *http.Request, params GetStatisticsPairsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить статистику по поддереву подразделений
+// (GET /statistics/subtree)
+func (_ Unimplemented) GetStatisticsSubtree(w http.ResponseWriter, r *http.Request, params GetStatisticsSubtreeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Временные метрики ревью
+// (GET /statistics/timing)
+func (_ Unimplemented) GetStatisticsTiming(w http.ResponseWriter, r *http.Request, params GetStatisticsTimingParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать команду с участниками (создаёт/обновляет пользователей)
+// (POST /team/add)
+func (_ Unimplemented) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить команду с участниками
+// (GET /team/get)
+func (_ Unimplemented) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переименовать команду
+// (POST /team/rename)
+func (_ Unimplemented) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить настройки выбора ревьюверов команды
+// (GET /team/reviewSettings)
+func (_ Unimplemented) GetTeamReviewSettings(w http.ResponseWriter, r *http.Request, params GetTeamReviewSettingsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменить настройки выбора ревьюверов команды
+// (POST /team/reviewSettings)
+func (_ Unimplemented) PostTeamReviewSettings(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Привязать команду к родительскому подразделению (или отвязать)
+// (POST /team/setParent)
+func (_ Unimplemented) PostTeamSetParent(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить поддерево подразделений начиная с команды
+// (GET /team/subtree)
+func (_ Unimplemented) GetTeamSubtree(w http.ResponseWriter, r *http.Request, params GetTeamSubtreeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать пользователя в существующей команде
+// (POST /users/create)
+func (_ Unimplemented) PostUsersCreate(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить пользователя (открытые ревью переназначаются)
+// (POST /users/delete)
+func (_ Unimplemented) PostUsersDelete(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить пользователя
+// (GET /users/get)
+func (_ Unimplemented) GetUsersGet(w http.ResponseWriter, r *http.Request, params GetUsersGetParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить PR'ы, где пользователь назначен ревьювером
+// (GET /users/getReview)
+func (_ Unimplemented) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Установить флаг активности пользователя
+// (POST /users/setIsActive)
+func (_ Unimplemented) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменить имя и/или команду пользователя
+// (POST /users/update)
+func (_ Unimplemented) PostUsersUpdate(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetAdminExport operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminExport(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAdminExportParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminExport(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminImport operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminImport(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostAdminImportParams
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminImport(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminStatisticsRebuild operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminStatisticsRebuild(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminStatisticsRebuild(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminSyncApply operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminSyncApply(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostAdminSyncApplyParams
+
+	// ------------- Optional query parameter "missing_users" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "missing_users", r.URL.Query(), &params.MissingUsers)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "missing_users", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "plan_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "plan_id", r.URL.Query(), &params.PlanId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "plan_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminSyncApply(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminSyncPlan operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminSyncPlan(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostAdminSyncPlanParams
+
+	// ------------- Optional query parameter "missing_users" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "missing_users", r.URL.Query(), &params.MissingUsers)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "missing_users", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminSyncPlan(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthTokensCreate operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthTokensCreate(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthTokensCreate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAuthTokensRevoke operation middleware
+func (siw *ServerInterfaceWrapper) PostAuthTokensRevoke(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAuthTokensRevoke(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHealthLive operation middleware
+func (siw *ServerInterfaceWrapper) GetHealthLive(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealthLive(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHealthReady operation middleware
+func (siw *ServerInterfaceWrapper) GetHealthReady(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealthReady(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestCreate operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"pr:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestCreate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestGet operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestGet(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestGetParams
+
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	if paramValue := r.URL.Query().Get("pull_request_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "pull_request_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", r.URL.Query(), &params.PullRequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestGet(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestMerge operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"pr:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestMerge(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestReassign operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"pr:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReassign(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimV2Groups operation middleware
+func (siw *ServerInterfaceWrapper) GetScimV2Groups(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetScimV2GroupsParams
+
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", r.URL.Query(), &params.Filter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filter", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "startIndex" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "startIndex", r.URL.Query(), &params.StartIndex)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "startIndex", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "count", r.URL.Query(), &params.Count)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "count", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimV2Groups(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostScimV2Groups operation middleware
+func (siw *ServerInterfaceWrapper) PostScimV2Groups(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostScimV2Groups(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteScimV2GroupsId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteScimV2GroupsId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ScimIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteScimV2GroupsId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimV2GroupsId operation middleware
+func (siw *ServerInterfaceWrapper) GetScimV2GroupsId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ScimIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimV2GroupsId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PatchScimV2GroupsId operation middleware
+func (siw *ServerInterfaceWrapper) PatchScimV2GroupsId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ScimIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchScimV2GroupsId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimV2ServiceProviderConfig operation middleware
+func (siw *ServerInterfaceWrapper) GetScimV2ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimV2ServiceProviderConfig(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimV2Users operation middleware
+func (siw *ServerInterfaceWrapper) GetScimV2Users(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetScimV2UsersParams
+
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filter", r.URL.Query(), &params.Filter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filter", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "startIndex" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "startIndex", r.URL.Query(), &params.StartIndex)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "startIndex", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "count", r.URL.Query(), &params.Count)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "count", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimV2Users(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostScimV2Users operation middleware
+func (siw *ServerInterfaceWrapper) PostScimV2Users(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostScimV2Users(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteScimV2UsersId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteScimV2UsersId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ScimIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteScimV2UsersId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimV2UsersId operation middleware
+func (siw *ServerInterfaceWrapper) GetScimV2UsersId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ScimIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimV2UsersId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PatchScimV2UsersId operation middleware
+func (siw *ServerInterfaceWrapper) PatchScimV2UsersId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ScimIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchScimV2UsersId(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatistics operation middleware
+func (siw *ServerInterfaceWrapper) GetStatistics(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"stats:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatisticsParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "pr_limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pr_limit", r.URL.Query(), &params.PrLimit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pr_limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "pr_offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pr_offset", r.URL.Query(), &params.PrOffset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pr_offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "reviewers_limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reviewers_limit", r.URL.Query(), &params.ReviewersLimit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "reviewers_limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "reviewers_offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reviewers_offset", r.URL.Query(), &params.ReviewersOffset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "reviewers_offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatistics(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatisticsFairness operation middleware
+func (siw *ServerInterfaceWrapper) GetStatisticsFairness(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"stats:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatisticsFairnessParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tolerance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tolerance", r.URL.Query(), &params.Tolerance)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tolerance", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatisticsFairness(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatisticsHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetStatisticsHistory(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"stats:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatisticsHistoryParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "granularity" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "granularity", r.URL.Query(), &params.Granularity)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "granularity", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatisticsHistory(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatisticsPairs operation middleware
+func (siw *ServerInterfaceWrapper) GetStatisticsPairs(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"stats:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatisticsPairsParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "top" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "top", r.URL.Query(), &params.Top)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "top", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatisticsPairs(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatisticsSubtree operation middleware
+func (siw *ServerInterfaceWrapper) GetStatisticsSubtree(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"stats:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatisticsSubtreeParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatisticsSubtree(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatisticsTiming operation middleware
+func (siw *ServerInterfaceWrapper) GetStatisticsTiming(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"stats:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatisticsTimingParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatisticsTiming(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamAdd operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamAdd(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTeamGet operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamGetParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamGet(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamRename operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamRename(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
-type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
+	handler.ServeHTTP(w, r)
+}
 
-// PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
-type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
+// GetTeamReviewSettings operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamReviewSettings(w http.ResponseWriter, r *http.Request) {
 
-// PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
-type PostTeamAddJSONRequestBody = Team
+	var err error
 
-// PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
-type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+	ctx := r.Context()
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
-	// (POST /pullRequest/create)
-	PostPullRequestCreate(w http.ResponseWriter, r *http.Request)
-	// Пометить PR как MERGED (идемпотентная операция)
-	// (POST /pullRequest/merge)
-	PostPullRequestMerge(w http.ResponseWriter, r *http.Request)
-	// Переназначить конкретного ревьювера на другого из его команды
-	// (POST /pullRequest/reassign)
-	PostPullRequestReassign(w http.ResponseWriter, r *http.Request)
-	// Получить статистику по PR и ревьюверам
-	// (GET /statistics)
-	GetStatistics(w http.ResponseWriter, r *http.Request)
-	// Создать команду с участниками (создаёт/обновляет пользователей)
-	// (POST /team/add)
-	PostTeamAdd(w http.ResponseWriter, r *http.Request)
-	// Получить команду с участниками
-	// (GET /team/get)
-	GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams)
-	// Получить PR'ы, где пользователь назначен ревьювером
-	// (GET /users/getReview)
-	GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams)
-	// Установить флаг активности пользователя
-	// (POST /users/setIsActive)
-	PostUsersSetIsActive(w http.ResponseWriter, r *http.Request)
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+	r = r.WithContext(ctx)
 
-type Unimplemented struct{}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamReviewSettingsParams
 
-// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
-// (POST /pullRequest/create)
-func (_ Unimplemented) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	// ------------- Required query parameter "team_name" -------------
 
-// Пометить PR как MERGED (идемпотентная операция)
-// (POST /pullRequest/merge)
-func (_ Unimplemented) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
 
-// Переназначить конкретного ревьювера на другого из его команды
-// (POST /pullRequest/reassign)
-func (_ Unimplemented) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
 
-// Получить статистику по PR и ревьюверам
-// (GET /statistics)
-func (_ Unimplemented) GetStatistics(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
 
-// Создать команду с участниками (создаёт/обновляет пользователей)
-// (POST /team/add)
-func (_ Unimplemented) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamReviewSettings(w, r, params)
+	}))
 
-// Получить команду с участниками
-// (GET /team/get)
-func (_ Unimplemented) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// Получить PR'ы, где пользователь назначен ревьювером
-// (GET /users/getReview)
-func (_ Unimplemented) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	handler.ServeHTTP(w, r)
 }
 
-// Установить флаг активности пользователя
-// (POST /users/setIsActive)
-func (_ Unimplemented) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// PostTeamReviewSettings operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamReviewSettings(w http.ResponseWriter, r *http.Request) {
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
-}
+	ctx := r.Context()
 
-type MiddlewareFunc func(http.Handler) http.Handler
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
 
-// PostPullRequestCreate operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestCreate(w, r)
+		siw.Handler.PostTeamReviewSettings(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -299,11 +2805,17 @@ func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// PostPullRequestMerge operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+// PostTeamSetParent operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamSetParent(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestMerge(w, r)
+		siw.Handler.PostTeamSetParent(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -313,11 +2825,37 @@ func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// PostPullRequestReassign operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+// GetTeamSubtree operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamSubtree(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamSubtreeParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestReassign(w, r)
+		siw.Handler.GetTeamSubtree(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -327,11 +2865,17 @@ func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter
 	handler.ServeHTTP(w, r)
 }
 
-// GetStatistics operation middleware
-func (siw *ServerInterfaceWrapper) GetStatistics(w http.ResponseWriter, r *http.Request) {
+// PostUsersCreate operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersCreate(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetStatistics(w, r)
+		siw.Handler.PostUsersCreate(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -341,11 +2885,17 @@ func (siw *ServerInterfaceWrapper) GetStatistics(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// PostTeamAdd operation middleware
-func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+// PostUsersDelete operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersDelete(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostTeamAdd(w, r)
+		siw.Handler.PostUsersDelete(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -355,31 +2905,37 @@ func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// GetTeamGet operation middleware
-func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
+// GetUsersGet operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersGet(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetTeamGetParams
+	var params GetUsersGetParams
 
-	// ------------- Required query parameter "team_name" -------------
+	// ------------- Required query parameter "user_id" -------------
 
-	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
 
 	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetTeamGet(w, r, params)
+		siw.Handler.GetUsersGet(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -394,6 +2950,12 @@ func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *h
 
 	var err error
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetUsersGetReviewParams
 
@@ -426,6 +2988,12 @@ func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *h
 // PostUsersSetIsActive operation middleware
 func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.PostUsersSetIsActive(w, r)
 	}))
@@ -437,6 +3005,26 @@ func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
+// PostUsersUpdate operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersUpdate(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"team:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersUpdate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -550,30 +3138,135 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/export", wrapper.GetAdminExport)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/import", wrapper.PostAdminImport)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/statistics/rebuild", wrapper.PostAdminStatisticsRebuild)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/sync/apply", wrapper.PostAdminSyncApply)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/sync/plan", wrapper.PostAdminSyncPlan)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/auth/tokens/create", wrapper.PostAuthTokensCreate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/auth/tokens/revoke", wrapper.PostAuthTokensRevoke)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/health/live", wrapper.GetHealthLive)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/health/ready", wrapper.GetHealthReady)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/create", wrapper.PostPullRequestCreate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/get", wrapper.GetPullRequestGet)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/merge", wrapper.PostPullRequestMerge)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/reassign", wrapper.PostPullRequestReassign)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Groups", wrapper.GetScimV2Groups)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/scim/v2/Groups", wrapper.PostScimV2Groups)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/scim/v2/Groups/{id}", wrapper.DeleteScimV2GroupsId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Groups/{id}", wrapper.GetScimV2GroupsId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/scim/v2/Groups/{id}", wrapper.PatchScimV2GroupsId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/ServiceProviderConfig", wrapper.GetScimV2ServiceProviderConfig)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Users", wrapper.GetScimV2Users)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/scim/v2/Users", wrapper.PostScimV2Users)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/scim/v2/Users/{id}", wrapper.DeleteScimV2UsersId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Users/{id}", wrapper.GetScimV2UsersId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/scim/v2/Users/{id}", wrapper.PatchScimV2UsersId)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/statistics", wrapper.GetStatistics)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/statistics/fairness", wrapper.GetStatisticsFairness)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/statistics/history", wrapper.GetStatisticsHistory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/statistics/pairs", wrapper.GetStatisticsPairs)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/statistics/subtree", wrapper.GetStatisticsSubtree)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/statistics/timing", wrapper.GetStatisticsTiming)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/team/add", wrapper.PostTeamAdd)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/team/get", wrapper.GetTeamGet)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/rename", wrapper.PostTeamRename)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/reviewSettings", wrapper.GetTeamReviewSettings)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/reviewSettings", wrapper.PostTeamReviewSettings)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/setParent", wrapper.PostTeamSetParent)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/subtree", wrapper.GetTeamSubtree)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/create", wrapper.PostUsersCreate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/delete", wrapper.PostUsersDelete)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/get", wrapper.GetUsersGet)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/users/getReview", wrapper.GetUsersGetReview)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/users/setIsActive", wrapper.PostUsersSetIsActive)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/update", wrapper.PostUsersUpdate)
+	})
 
 	return r
 }
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const jwtLeeway = 30 * time.Second
+
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	// UserClaim names the claim holding users.user_id, usually "sub" or
+	// "preferred_username".
+	UserClaim string
+	// ScopesClaim names the claim with the caller's scopes, either a
+	// space-separated string ("scope") or an array ("scp").
+	ScopesClaim string
+	// DefaultScopes apply when the token carries no known scope.
+	DefaultScopes []string
+}
+
+type UserGetter interface {
+	GetUser(ctx context.Context, userID string) (*model.User, error)
+}
+
+// JWTVerifier authenticates callers by JWTs issued by the company SSO.
+type JWTVerifier struct {
+	cfg    JWTConfig
+	keys   *KeySet
+	users  UserGetter
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(cfg JWTConfig, keys *KeySet, users UserGetter) *JWTVerifier {
+	return &JWTVerifier{
+		cfg:   cfg,
+		keys:  keys,
+		users: users,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(jwtLeeway),
+		),
+	}
+}
+
+// Verify checks the token signature and registered claims and maps the caller
+// to an existing user. Any failure is reported as ErrUnauthorized; the
+// returned error wraps the reason for logging.
+func (v *JWTVerifier) Verify(ctx context.Context, raw string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", int_errors.ErrUnauthorized, err)
+	}
+
+	userID, _ := claims[v.cfg.UserClaim].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("%w: claim %q is missing", int_errors.ErrUnauthorized, v.cfg.UserClaim)
+	}
+
+	user, err := v.users.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, int_errors.ErrUserNotFound) {
+			return nil, fmt.Errorf("%w: unknown user %q", int_errors.ErrUnauthorized, userID)
+		}
+		return nil, err
+	}
+
+	scopes := v.scopes(claims)
+	if len(scopes) == 0 {
+		scopes = v.cfg.DefaultScopes
+	}
+
+	name, _ := claims["name"].(string)
+	if name == "" {
+		name = user.Username
+	}
+
+	return &Principal{
+		Name:   name,
+		Scopes: scopes,
+		UserID: user.ID,
+	}, nil
+}
+
+func (v *JWTVerifier) scopes(claims jwt.MapClaims) []string {
+	var raw []string
+	switch value := claims[v.cfg.ScopesClaim].(type) {
+	case string:
+		raw = strings.Fields(value)
+	case []any:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+
+	// IdP tokens carry scopes for many audiences; keep only ours.
+	scopes := make([]string, 0, len(raw))
+	for _, s := range raw {
+		if model.IsValidScope(s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://sso.example.com"
+	testAudience = "pr-service"
+)
+
+type fakeUsers map[string]*model.User
+
+func (f fakeUsers) GetUser(_ context.Context, userID string) (*model.User, error) {
+	if u, ok := f[userID]; ok {
+		return u, nil
+	}
+	return nil, int_errors.ErrUserNotFound
+}
+
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+	set *KeySet
+}
+
+// newTestKeys generates an RSA and an EC signing key and serves their public
+// halves as a static JWKS file under the kids "rsa-1" and "ec-1".
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+
+	enc := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	doc := map[string][]jwk{"keys": {
+		{
+			Kty: "RSA", Kid: "rsa-1", Use: "sig",
+			N: enc(rsaKey.N.Bytes()),
+			E: enc(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{
+			Kty: "EC", Kid: "ec-1", Use: "sig", Crv: "P-256",
+			X: enc(ecKey.X.FillBytes(make([]byte, 32))),
+			Y: enc(ecKey.Y.FillBytes(make([]byte, 32))),
+		},
+		{Kty: "RSA", Kid: "enc-1", Use: "enc", N: enc(rsaKey.N.Bytes()), E: "AQAB"},
+	}}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("encode jwks: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+	set, err := NewFileKeySet(path)
+	if err != nil {
+		t.Fatalf("load jwks: %v", err)
+	}
+
+	return &testKeys{rsa: rsaKey, ec: ecKey, set: set}
+}
+
+func (k *testKeys) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	var key any = k.rsa
+	if _, ok := method.(*jwt.SigningMethodECDSA); ok {
+		key = k.ec
+	}
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return raw
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":                testIssuer,
+		"aud":                testAudience,
+		"sub":                "u1",
+		"preferred_username": "u2",
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+		"scope":              "openid pr:write profile",
+	}
+}
+
+func newTestVerifier(keys *testKeys, userClaim string) *JWTVerifier {
+	users := fakeUsers{
+		"u1": {ID: "u1", Username: "Alice"},
+		"u2": {ID: "u2", Username: "Bob"},
+	}
+	return NewJWTVerifier(JWTConfig{
+		Issuer:        testIssuer,
+		Audience:      testAudience,
+		UserClaim:     userClaim,
+		ScopesClaim:   "scope",
+		DefaultScopes: []string{model.ScopeStatsRead},
+	}, keys.set, users)
+}
+
+func TestJWTVerifierVerify(t *testing.T) {
+	keys := newTestKeys(t)
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     string
+		claims  func(jwt.MapClaims)
+		wantErr bool
+	}{
+		{name: "valid rsa", method: jwt.SigningMethodRS256, kid: "rsa-1"},
+		{name: "valid ec", method: jwt.SigningMethodES256, kid: "ec-1"},
+		{
+			name: "expired", method: jwt.SigningMethodRS256, kid: "rsa-1", wantErr: true,
+			claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		},
+		{
+			name: "expired within leeway", method: jwt.SigningMethodRS256, kid: "rsa-1",
+			claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-jwtLeeway / 2).Unix() },
+		},
+		{
+			name: "missing exp", method: jwt.SigningMethodRS256, kid: "rsa-1", wantErr: true,
+			claims: func(c jwt.MapClaims) { delete(c, "exp") },
+		},
+		{
+			name: "wrong issuer", method: jwt.SigningMethodRS256, kid: "rsa-1", wantErr: true,
+			claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		},
+		{
+			name: "wrong audience", method: jwt.SigningMethodRS256, kid: "rsa-1", wantErr: true,
+			claims: func(c jwt.MapClaims) { c["aud"] = []string{"other-service"} },
+		},
+		{name: "unknown kid", method: jwt.SigningMethodRS256, kid: "rsa-2", wantErr: true},
+		{name: "encryption key", method: jwt.SigningMethodRS256, kid: "enc-1", wantErr: true},
+		{
+			name: "unknown user", method: jwt.SigningMethodRS256, kid: "rsa-1", wantErr: true,
+			claims: func(c jwt.MapClaims) { c["sub"] = "u404" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			raw := keys.sign(t, tt.method, tt.kid, claims)
+
+			p, err := newTestVerifier(keys, "sub").Verify(context.Background(), raw)
+			if tt.wantErr {
+				if !errors.Is(err, int_errors.ErrUnauthorized) {
+					t.Fatalf("Verify() error = %v, want ErrUnauthorized", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if p.UserID != "u1" {
+				t.Errorf("UserID = %q, want u1", p.UserID)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierRejectsHMAC(t *testing.T) {
+	keys := newTestKeys(t)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	token.Header["kid"] = "rsa-1"
+	raw, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	if _, err := newTestVerifier(keys, "sub").Verify(context.Background(), raw); !errors.Is(err, int_errors.ErrUnauthorized) {
+		t.Fatalf("Verify() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestJWTVerifierClaimMapping(t *testing.T) {
+	keys := newTestKeys(t)
+
+	tests := []struct {
+		name       string
+		userClaim  string
+		claims     func(jwt.MapClaims)
+		wantUser   string
+		wantName   string
+		wantScopes []string
+	}{
+		{
+			name:       "sub",
+			userClaim:  "sub",
+			wantUser:   "u1",
+			wantName:   "Alice",
+			wantScopes: []string{model.ScopePRWrite},
+		},
+		{
+			name:       "preferred_username",
+			userClaim:  "preferred_username",
+			wantUser:   "u2",
+			wantName:   "Bob",
+			wantScopes: []string{model.ScopePRWrite},
+		},
+		{
+			name:      "name claim and scope array",
+			userClaim: "sub",
+			claims: func(c jwt.MapClaims) {
+				c["name"] = "Alice Liddell"
+				c["scope"] = []string{"stats:read", "email", "team:admin"}
+			},
+			wantUser:   "u1",
+			wantName:   "Alice Liddell",
+			wantScopes: []string{model.ScopeStatsRead, model.ScopeTeamAdmin},
+		},
+		{
+			name:       "no known scope falls back to defaults",
+			userClaim:  "sub",
+			claims:     func(c jwt.MapClaims) { c["scope"] = "openid profile" },
+			wantUser:   "u1",
+			wantName:   "Alice",
+			wantScopes: []string{model.ScopeStatsRead},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			raw := keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims)
+
+			p, err := newTestVerifier(keys, tt.userClaim).Verify(context.Background(), raw)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if p.UserID != tt.wantUser {
+				t.Errorf("UserID = %q, want %q", p.UserID, tt.wantUser)
+			}
+			if p.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", p.Name, tt.wantName)
+			}
+			if !slices.Equal(p.Scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %v, want %v", p.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierMissingUserClaim(t *testing.T) {
+	keys := newTestKeys(t)
+
+	claims := validClaims()
+	delete(claims, "preferred_username")
+	raw := keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims)
+
+	if _, err := newTestVerifier(keys, "preferred_username").Verify(context.Background(), raw); !errors.Is(err, int_errors.ErrUnauthorized) {
+		t.Fatalf("Verify() error = %v, want ErrUnauthorized", err)
+	}
+}
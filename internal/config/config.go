@@ -1,47 +1,345 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const redacted = "[REDACTED]"
+
 type Config struct {
 	Postgres   Postgres   `yaml:"postgres"`
 	HTTPServer HTTPServer `yaml:"http_server"`
+	Metrics    Metrics    `yaml:"metrics"`
+	Auth       Auth       `yaml:"auth"`
+	RateLimit  RateLimit  `yaml:"rate_limit"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Health     Health     `yaml:"health"`
+	Log        Log        `yaml:"log"`
+	Migrations Migrations `yaml:"migrations"`
+	SCIM       SCIM       `yaml:"scim"`
+	Statistics Statistics `yaml:"statistics"`
+}
+
+// LogValue lists the sections explicitly: slog formats a plain struct with
+// fmt and would not call the LogValue methods that redact secrets below.
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("postgres", c.Postgres),
+		slog.Any("http_server", c.HTTPServer),
+		slog.Any("metrics", c.Metrics),
+		slog.Any("auth", c.Auth),
+		slog.Any("rate_limit", c.RateLimit),
+		slog.Any("tracing", c.Tracing),
+		slog.Any("health", c.Health),
+		slog.Any("log", c.Log),
+		slog.Any("migrations", c.Migrations),
+		slog.Any("scim", c.SCIM),
+		slog.Any("statistics", c.Statistics),
+	)
+}
+
+type Migrations struct {
+	// AutoApply runs pending migrations on start. Replicas starting at the
+	// same time wait for each other on an advisory lock.
+	AutoApply bool `yaml:"auto_apply" env:"MIGRATIONS_AUTO_APPLY"`
+}
+
+type SCIM struct {
+	// DefaultTeam is the primary team of provisioned users whose enterprise
+	// extension carries no department. It is created on first use.
+	DefaultTeam string `yaml:"default_team" env:"SCIM_DEFAULT_TEAM" env-default:"unassigned"`
+}
+
+type Statistics struct {
+	// UseCounters serves /statistics without a time range from counters kept
+	// up to date by PR changes rather than aggregating every review.
+	// Defaults to true in defaults().
+	UseCounters bool `yaml:"use_counters" env:"STATS_USE_COUNTERS"`
+	// CheckInterval is how often the counters are checked against the
+	// reviews and rebuilt if they drifted; 0 disables the check. Defaults to
+	// an hour in defaults().
+	CheckInterval time.Duration `yaml:"check_interval" env:"STATS_CHECK_INTERVAL"`
+	// SnapshotInterval is how often the service looks for finished UTC days
+	// without a stats snapshot and writes them; 0 disables the job. Defaults
+	// to an hour in defaults().
+	SnapshotInterval time.Duration `yaml:"snapshot_interval" env:"STATS_SNAPSHOT_INTERVAL"`
+}
+
+type Log struct {
+	// Level is one of debug, info, warn, error.
+	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+	// Format is text or json.
+	Format string `yaml:"format" env:"LOG_FORMAT" env-default:"json"`
 }
 
+// Postgres takes either a complete DSN or discrete fields. With a DSN the
+// discrete fields, TLS settings included, are ignored.
 type Postgres struct {
-	Host     string `yaml:"host" env-default:"localhost"`
-	Port     string `yaml:"port" env-default:"5432"`
-	User     string `yaml:"user" env-required:"true"`
-	Password string `yaml:"password" env-required:"true"`
-	DBName   string `yaml:"dbname" env-required:"true"`
+	DSN      string `yaml:"dsn" env:"DATABASE_URL"`
+	Host     string `yaml:"host" env:"POSTGRES_HOST" env-default:"localhost"`
+	Port     string `yaml:"port" env:"POSTGRES_PORT" env-default:"5432"`
+	User     string `yaml:"user" env:"POSTGRES_USER"`
+	Password string `yaml:"password" env:"POSTGRES_PASSWORD"`
+	DBName   string `yaml:"dbname" env:"POSTGRES_DB"`
+	// SSLMode is a libpq sslmode: disable, allow, prefer, require, verify-ca
+	// or verify-full.
+	SSLMode     string `yaml:"sslmode" env:"POSTGRES_SSLMODE" env-default:"disable"`
+	SSLRootCert string `yaml:"sslrootcert" env:"POSTGRES_SSLROOTCERT"`
+	SSLCert     string `yaml:"sslcert" env:"POSTGRES_SSLCERT"`
+	SSLKey      string `yaml:"sslkey" env:"POSTGRES_SSLKEY"`
+	Pool        Pool   `yaml:"pool"`
+}
+
+type Pool struct {
+	MaxConns          int32         `yaml:"max_conns" env:"POSTGRES_POOL_MAX_CONNS" env-default:"25"`
+	MinConns          int32         `yaml:"min_conns" env:"POSTGRES_POOL_MIN_CONNS" env-default:"2"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"POSTGRES_POOL_MAX_CONN_LIFETIME" env-default:"1h"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"POSTGRES_POOL_MAX_CONN_IDLE_TIME" env-default:"30m"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"POSTGRES_POOL_HEALTH_CHECK_PERIOD" env-default:"1m"`
+}
+
+// ConnString returns the DSN, or builds a URL from the discrete fields.
+func (p Postgres) ConnString() string {
+	if p.DSN != "" {
+		return p.DSN
+	}
+
+	q := url.Values{}
+	q.Set("sslmode", p.SSLMode)
+	if p.SSLRootCert != "" {
+		q.Set("sslrootcert", p.SSLRootCert)
+	}
+	if p.SSLCert != "" {
+		q.Set("sslcert", p.SSLCert)
+	}
+	if p.SSLKey != "" {
+		q.Set("sslkey", p.SSLKey)
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(p.User, p.Password),
+		Host:     net.JoinHostPort(p.Host, p.Port),
+		Path:     "/" + p.DBName,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+func (p Postgres) LogValue() slog.Value {
+	if p.DSN != "" {
+		return slog.GroupValue(
+			slog.String("dsn", redactDSN(p.DSN)),
+			slog.Any("pool", p.Pool),
+		)
+	}
+	return slog.GroupValue(
+		slog.String("host", p.Host),
+		slog.String("port", p.Port),
+		slog.String("user", p.User),
+		slog.String("password", redacted),
+		slog.String("dbname", p.DBName),
+		slog.String("sslmode", p.SSLMode),
+		slog.Any("pool", p.Pool),
+	)
+}
+
+// redactDSN masks the password of a URL DSN. Key/value DSNs cannot be masked
+// reliably and are hidden entirely.
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		return redacted
+	}
+	return u.Redacted()
 }
 
 type HTTPServer struct {
-	Address     string        `yaml:"address" env-default:"localhost:8080"`
-	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
-	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	Address     string        `yaml:"address" env:"HTTP_ADDRESS" env-default:"localhost:8080"`
+	Timeout     time.Duration `yaml:"timeout" env:"HTTP_TIMEOUT" env-default:"4s"`
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"60s"`
 }
 
-func MustLoad() *Config {
-	configPath := os.Getenv("CONFIG_PATH")
-	if configPath == "" {
-		log.Fatal("CONFIG_PATH is not set")
+// Metrics is the listener serving /metrics. It is kept off the API address so
+// that only the network the scraper lives in can reach it.
+type Metrics struct {
+	Address string `yaml:"address" env:"METRICS_ADDRESS" env-default:"localhost:9090"`
+}
+
+type Auth struct {
+	// Enabled defaults to true in defaults(); an env-default would turn an
+	// explicit "enabled: false" back on.
+	Enabled bool `yaml:"enabled" env:"AUTH_ENABLED"`
+	// AdminToken is the secret of the first admin token, registered at
+	// startup as "bootstrap-admin" with every scope so that the first admin
+	// can mint further tokens through the API. It is meant to come from the
+	// environment; no value is shipped with the config file.
+	AdminToken string `yaml:"admin_token" env:"AUTH_ADMIN_TOKEN"`
+	// BootstrapTokens are further tokens registered at startup.
+	BootstrapTokens BootstrapTokens `yaml:"bootstrap_tokens" env:"AUTH_BOOTSTRAP_TOKENS"`
+	JWT             JWT             `yaml:"jwt"`
+}
+
+// LogValue keeps bootstrap token secrets out of the log; only their IDs are
+// shown.
+func (a Auth) LogValue() slog.Value {
+	ids := make([]string, 0, len(a.BootstrapTokens))
+	for _, bt := range a.BootstrapTokens {
+		ids = append(ids, bt.ID)
 	}
+	return slog.GroupValue(
+		slog.Bool("enabled", a.Enabled),
+		slog.Bool("admin_token", a.AdminToken != ""),
+		slog.Any("bootstrap_tokens", ids),
+		slog.Any("jwt", a.JWT),
+	)
+}
+
+// JWT configures verification of SSO-issued tokens. Exactly one of JWKSPath
+// and JWKSURL must be set when it is enabled.
+type JWT struct {
+	Enabled             bool          `yaml:"enabled" env:"AUTH_JWT_ENABLED"`
+	Issuer              string        `yaml:"issuer" env:"AUTH_JWT_ISSUER"`
+	Audience            string        `yaml:"audience" env:"AUTH_JWT_AUDIENCE"`
+	JWKSPath            string        `yaml:"jwks_path" env:"AUTH_JWT_JWKS_PATH"`
+	JWKSURL             string        `yaml:"jwks_url" env:"AUTH_JWT_JWKS_URL"`
+	JWKSRefreshInterval time.Duration `yaml:"jwks_refresh_interval" env:"AUTH_JWT_JWKS_REFRESH_INTERVAL" env-default:"1h"`
+	UserClaim           string        `yaml:"user_claim" env:"AUTH_JWT_USER_CLAIM" env-default:"sub"`
+	ScopesClaim         string        `yaml:"scopes_claim" env:"AUTH_JWT_SCOPES_CLAIM" env-default:"scope"`
+	DefaultScopes       []string      `yaml:"default_scopes" env:"AUTH_JWT_DEFAULT_SCOPES"`
+}
+
+// RateLimit configures per-client token buckets. Clients are identified by
+// their bearer token, or by IP address when they send none. Routes maps an
+// API path such as /pullRequest/create to its own limit; other paths use
+// Default.
+type RateLimit struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// Backend is "memory" (per replica) or "postgres" (shared by replicas).
+	Backend string      `yaml:"backend" env:"RATE_LIMIT_BACKEND" env-default:"memory"`
+	Default RouteLimit  `yaml:"default" env-prefix:"RATE_LIMIT_DEFAULT_"`
+	Routes  RouteLimits `yaml:"routes" env:"RATE_LIMIT_ROUTES"`
+	// IdleTTL is how long an untouched bucket is kept before it is dropped.
+	IdleTTL time.Duration `yaml:"idle_ttl" env:"RATE_LIMIT_IDLE_TTL" env-default:"10m"`
+}
+
+type RouteLimit struct {
+	// Rate is the sustained number of requests per second.
+	Rate  float64 `yaml:"rate" json:"rate" env:"RATE" env-default:"10"`
+	Burst int     `yaml:"burst" json:"burst" env:"BURST" env-default:"20"`
+}
+
+// RouteLimits is read from the environment as a JSON object of the same
+// shape as the YAML map: {"/pullRequest/create": {"rate": 2, "burst": 5}}.
+// A value from the environment replaces the routes of the file.
+type RouteLimits map[string]RouteLimit
+
+func (r *RouteLimits) SetValue(s string) error {
+	*r = nil
+	return decodeJSONEnv(s, r)
+}
+
+// Tracing configures OpenTelemetry. Exporter is "otlp" (OTLP over HTTP) or
+// "stdout" for local debugging.
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled" env:"TRACING_ENABLED"`
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME" env-default:"avito-pr-service"`
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"otlp"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+type Health struct {
+	// MaxPoolSaturation is the share of busy pool connections above which
+	// /health/ready fails.
+	MaxPoolSaturation float64       `yaml:"max_pool_saturation" env:"HEALTH_MAX_POOL_SATURATION" env-default:"0.9"`
+	CheckTimeout      time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" env-default:"2s"`
+	// DrainDelay is how long readiness fails before the server stops, so
+	// that load balancers notice and stop routing to this instance. Its
+	// default is set in defaults() so that 0s can turn draining off.
+	DrainDelay time.Duration `yaml:"drain_delay" env:"HEALTH_DRAIN_DELAY"`
+}
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		log.Fatalf("config file does not exist: %s", configPath)
+type BootstrapToken struct {
+	ID     string   `yaml:"id" json:"id"`
+	Name   string   `yaml:"name" json:"name"`
+	Token  string   `yaml:"token" json:"token"`
+	Scopes []string `yaml:"scopes" json:"scopes"`
+}
+
+// BootstrapTokens is read from the environment as a JSON array of tokens
+// with the same fields as the YAML list. A value from the environment
+// replaces the tokens of the file.
+type BootstrapTokens []BootstrapToken
+
+func (t *BootstrapTokens) SetValue(s string) error {
+	*t = nil
+	return decodeJSONEnv(s, t)
+}
+
+// decodeJSONEnv decodes a structured setting given as JSON in an environment
+// variable. Unknown keys are rejected so that a typo does not silently drop
+// a token or limit.
+func decodeJSONEnv(s string, v any) error {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
 	}
+	return nil
+}
 
-	var cfg Config
+// defaults holds the values whose zero value is a meaningful setting.
+// cleanenv applies env-default to every zero field, so such defaults cannot
+// be expressed as tags.
+func defaults() Config {
+	return Config{
+		Auth:   Auth{Enabled: true},
+		Health: Health{DrainDelay: 5 * time.Second},
+		Statistics: Statistics{
+			UseCounters:      true,
+			CheckInterval:    time.Hour,
+			SnapshotInterval: time.Hour,
+		},
+	}
+}
 
-	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
-		log.Fatalf("cannot read config: %s", err)
+// Load reads the YAML file named by CONFIG_PATH, if set, and then
+// environment variables, which take precedence. The result is validated as a
+// whole, so that every problem is reported at once.
+func Load() (*Config, error) {
+	cfg := defaults()
+
+	if configPath := os.Getenv("CONFIG_PATH"); configPath != "" {
+		if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
+			return nil, fmt.Errorf("read config %s: %w", configPath, err)
+		}
+	} else if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("read environment: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	return &cfg
+	return &cfg, nil
+}
+
+func MustLoad() *Config {
+	cfg, err := Load()
+	if err != nil {
+		log.Fatalf("invalid config:\n%s", err)
+	}
+	return cfg
 }
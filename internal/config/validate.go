@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true,
+	"require": true, "verify-ca": true, "verify-full": true,
+}
+
+// Validate checks the whole configuration and joins every problem found
+// into a single error, one line per field.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	pg := c.Postgres
+	if pg.DSN == "" {
+		if pg.Host == "" {
+			add("postgres.host: required without postgres.dsn")
+		}
+		if pg.User == "" {
+			add("postgres.user: required without postgres.dsn")
+		}
+		if pg.DBName == "" {
+			add("postgres.dbname: required without postgres.dsn")
+		}
+		if !sslModes[pg.SSLMode] {
+			add("postgres.sslmode: unknown mode %q", pg.SSLMode)
+		}
+	}
+	if pg.Pool.MaxConns < 1 {
+		add("postgres.pool.max_conns: must be at least 1")
+	}
+	if pg.Pool.MinConns < 0 || pg.Pool.MinConns > pg.Pool.MaxConns {
+		add("postgres.pool.min_conns: must be between 0 and max_conns")
+	}
+	if pg.Pool.HealthCheckPeriod <= 0 {
+		add("postgres.pool.health_check_period: must be positive")
+	}
+
+	if c.HTTPServer.Address == "" {
+		add("http_server.address: required")
+	}
+	if c.HTTPServer.Timeout <= 0 {
+		add("http_server.timeout: must be positive")
+	}
+	if c.Metrics.Address == "" {
+		add("metrics.address: required")
+	} else if c.Metrics.Address == c.HTTPServer.Address {
+		add("metrics.address: must differ from http_server.address")
+	}
+
+	if c.Auth.Enabled && c.Auth.AdminToken == "" && len(c.Auth.BootstrapTokens) == 0 {
+		add("auth.admin_token: required when auth is enabled, set AUTH_ADMIN_TOKEN")
+	}
+	for i, bt := range c.Auth.BootstrapTokens {
+		if bt.ID == "" || bt.Token == "" {
+			add("auth.bootstrap_tokens[%d]: id and token are required", i)
+		}
+	}
+	if jwt := c.Auth.JWT; jwt.Enabled {
+		if jwt.Issuer == "" || jwt.Audience == "" {
+			add("auth.jwt: issuer and audience are required")
+		}
+		if (jwt.JWKSPath == "") == (jwt.JWKSURL == "") {
+			add("auth.jwt: set exactly one of jwks_path and jwks_url")
+		}
+		if jwt.JWKSURL != "" && jwt.JWKSRefreshInterval <= 0 {
+			add("auth.jwt.jwks_refresh_interval: must be positive")
+		}
+	}
+
+	if rl := c.RateLimit; rl.Enabled {
+		if rl.Backend != "memory" && rl.Backend != "postgres" {
+			add("rate_limit.backend: unknown backend %q", rl.Backend)
+		}
+		if rl.Default.Rate <= 0 || rl.Default.Burst < 1 {
+			add("rate_limit.default: rate must be positive and burst at least 1")
+		}
+		for path, l := range rl.Routes {
+			if l.Rate <= 0 || l.Burst < 1 {
+				add("rate_limit.routes[%s]: rate must be positive and burst at least 1", path)
+			}
+		}
+		if rl.IdleTTL <= 0 {
+			add("rate_limit.idle_ttl: must be positive")
+		}
+	}
+
+	if tr := c.Tracing; tr.Enabled {
+		if tr.Exporter != "otlp" && tr.Exporter != "stdout" {
+			add("tracing.exporter: unknown exporter %q", tr.Exporter)
+		}
+		if tr.SampleRatio < 0 || tr.SampleRatio > 1 {
+			add("tracing.sample_ratio: must be between 0 and 1")
+		}
+	}
+
+	if s := c.Health.MaxPoolSaturation; s <= 0 || s > 1 {
+		add("health.max_pool_saturation: must be in (0, 1]")
+	}
+	if c.Health.CheckTimeout <= 0 {
+		add("health.check_timeout: must be positive")
+	}
+	if c.Health.DrainDelay < 0 {
+		add("health.drain_delay: must not be negative")
+	}
+
+	if strings.TrimSpace(c.SCIM.DefaultTeam) == "" {
+		add("scim.default_team: must not be empty")
+	}
+	if c.Statistics.CheckInterval < 0 {
+		add("statistics.check_interval: must not be negative")
+	}
+	if c.Statistics.SnapshotInterval < 0 {
+		add("statistics.snapshot_interval: must not be negative")
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		add("log.level: unknown level %q", c.Log.Level)
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		add("log.format: unknown format %q", c.Log.Format)
+	}
+
+	return errors.Join(errs...)
+}
@@ -5,14 +5,23 @@ import "errors"
 var (
 	ErrPRExists                = errors.New("pull request already exists")
 	ErrUserNotFound            = errors.New("user not found")
+	ErrUserExists              = errors.New("user already exists")
 	ErrPRNotFound              = errors.New("pull request not found")
 	ErrTeamNotFound            = errors.New("team not found")
 	ErrTeamExists              = errors.New("team already exists")
+	ErrTeamHierarchyCycle      = errors.New("team hierarchy would contain a cycle")
 	ErrPRMerged                = errors.New("pull request already merged")
 	ErrReviewerNotAssigned     = errors.New("reviewer is not assigned to pull request")
 	ErrNoReplacementCandidate  = errors.New("no active candidate available")
 	ErrUserHasOpenPullRequests = errors.New("user has open pull requests")
+	ErrUserHasHistory          = errors.New("user has pull request history")
 	ErrAuthorNotFound          = errors.New("author not found")
 	ErrAuthorNotActive         = errors.New("author is not active")
 	ErrNoTeamFound             = errors.New("author has no team")
+	ErrAuthorNotInTeam         = errors.New("author is not a member of team")
+	ErrUnauthorized            = errors.New("invalid or revoked bearer token")
+	ErrTokenNotFound           = errors.New("api token not found")
+	ErrInvalidScope            = errors.New("unknown api token scope")
+	ErrForbidden               = errors.New("action is not allowed for the caller's team role")
+	ErrSyncPlanStale           = errors.New("org state changed since the sync plan was made")
 )
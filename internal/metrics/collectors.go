@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exports pgxpool statistics, read at scrape time.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired     *prometheus.Desc
+	idle         *prometheus.Desc
+	total        *prometheus.Desc
+	max          *prometheus.Desc
+	acquires     *prometheus.Desc
+	emptyAcquire *prometheus.Desc
+	canceled     *prometheus.Desc
+	waitSeconds  *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &PoolCollector{
+		pool:         pool,
+		acquired:     desc("acquired_connections", "Connections currently checked out of the pool."),
+		idle:         desc("idle_connections", "Idle connections in the pool."),
+		total:        desc("total_connections", "Open connections, acquired, idle and being established."),
+		max:          desc("max_connections", "Configured maximum pool size."),
+		acquires:     desc("acquires_total", "Successful connection acquires."),
+		emptyAcquire: desc("empty_acquires_total", "Acquires that had to wait because the pool was empty."),
+		canceled:     desc("canceled_acquires_total", "Acquires canceled by their context."),
+		waitSeconds:  desc("acquire_wait_seconds_total", "Total time spent waiting for a connection."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.emptyAcquire
+	ch <- c.canceled
+	ch <- c.waitSeconds
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.waitSeconds, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
+
+type OpenReviewsSource interface {
+	GetOpenReviewCounts(ctx context.Context) (map[string]int, error)
+}
+
+// OpenReviewsCollector exports the number of open reviews per reviewer. The
+// value lives in the database, so it is queried on every scrape rather than
+// tracked in process, which would drift between replicas.
+type OpenReviewsCollector struct {
+	source  OpenReviewsSource
+	timeout time.Duration
+	desc    *prometheus.Desc
+	errDesc *prometheus.Desc
+}
+
+func NewOpenReviewsCollector(source OpenReviewsSource, timeout time.Duration) *OpenReviewsCollector {
+	return &OpenReviewsCollector{
+		source:  source,
+		timeout: timeout,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_reviews"),
+			"Open pull requests each reviewer is assigned to.",
+			[]string{"reviewer"}, nil,
+		),
+		errDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_reviews_scrape_error"),
+			"1 if the last open reviews query failed.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *OpenReviewsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+	ch <- c.errDesc
+}
+
+func (c *OpenReviewsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	counts, err := c.source.GetOpenReviewCounts(ctx)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.errDesc, prometheus.GaugeValue, 1)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.errDesc, prometheus.GaugeValue, 0)
+
+	for reviewer, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), reviewer)
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "pr_service"
+
+// Reassignment outcomes used as the "outcome" label of Reassignments.
+const (
+	OutcomeReassigned  = "reassigned"
+	OutcomeNoCandidate = "no_candidate"
+	OutcomeNotAssigned = "not_assigned"
+	OutcomeMerged      = "pr_merged"
+	OutcomeNotFound    = "not_found"
+	OutcomeForbidden   = "forbidden"
+	OutcomeError       = "error"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	PRsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pull_requests_created_total",
+		Help:      "Pull requests created.",
+	})
+
+	PRsMerged = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pull_requests_merged_total",
+		Help:      "Pull requests moved to MERGED; repeated merges are not counted.",
+	})
+
+	Reassignments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reassignments_total",
+		Help:      "Reviewer reassignment attempts by outcome.",
+	}, []string{"outcome"})
+
+	AssignmentSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "assignment_size",
+		Help:      "Number of reviewers assigned to a newly created pull request.",
+		Buckets:   []float64{0, 1, 2},
+	})
+)
@@ -0,0 +1,305 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey identifies the advisory lock that serialises migration runs, so
+// that replicas starting together apply each migration once.
+const lockKey int64 = 0x70725f6d6967 // "pr_mig"
+
+var (
+	ErrDirty           = errors.New("database is dirty: a previous migration failed halfway, fix it by hand and reset schema_migrations")
+	ErrUnknownVersion  = errors.New("unknown migration version")
+	ErrNoMoreMigration = errors.New("no migration to roll back")
+)
+
+var fileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load reads migrations from fsys ordered by version. Every version needs
+// both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	const op = "migrate.Load"
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, e.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("%s: migration %d_%s needs both up and down files", op, mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Latest returns the highest version in migrations, or 0 when there are none.
+func Latest(migrations []Migration) int64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+type Status struct {
+	Version int64
+	Dirty   bool
+	Latest  int64
+	Pending []Migration
+}
+
+// Migrator applies migrations and keeps the current version in the same
+// schema_migrations table as golang-migrate, so either tool can take over.
+// Each migration runs in its own transaction together with the version
+// update, so a failure leaves the previous version in place.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func New(pool *pgxpool.Pool, migrations []Migration) *Migrator {
+	return &Migrator{pool: pool, migrations: migrations}
+}
+
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	const op = "Migrator.Status"
+
+	var st *Status
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		st = &Status{Version: version, Dirty: dirty, Latest: Latest(m.migrations)}
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				st.Pending = append(st.Pending, mig)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return st, nil
+}
+
+// Up applies all pending migrations and returns the versions applied.
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	return m.To(ctx, Latest(m.migrations))
+}
+
+// Down rolls back the last n applied migrations.
+func (m *Migrator) Down(ctx context.Context, n int) ([]int64, error) {
+	const op = "Migrator.Down"
+
+	var target int64
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, _, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if version == 0 {
+			return ErrNoMoreMigration
+		}
+		idx := m.index(version)
+		if idx < 0 {
+			return fmt.Errorf("%w: database is at %d", ErrUnknownVersion, version)
+		}
+		if idx-n < 0 {
+			target = 0
+		} else {
+			target = m.migrations[idx-n].Version
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return m.To(ctx, target)
+}
+
+// To migrates up or down to version; 0 rolls everything back. It returns the
+// versions that were applied or rolled back, in order.
+func (m *Migrator) To(ctx context.Context, target int64) ([]int64, error) {
+	const op = "Migrator.To"
+
+	if target != 0 && m.index(target) < 0 {
+		return nil, fmt.Errorf("%s: %w: %d", op, ErrUnknownVersion, target)
+	}
+
+	var done []int64
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w (version %d)", ErrDirty, version)
+		}
+		// A database migrated by a newer build must not be touched by
+		// this one.
+		if version != 0 && m.index(version) < 0 {
+			return fmt.Errorf("%w: database is at %d", ErrUnknownVersion, version)
+		}
+
+		if target >= version {
+			for _, mig := range m.migrations {
+				if mig.Version <= version || mig.Version > target {
+					continue
+				}
+				if err := apply(ctx, conn, mig.Up, mig.Version); err != nil {
+					return fmt.Errorf("up %d_%s: %w", mig.Version, mig.Name, err)
+				}
+				done = append(done, mig.Version)
+			}
+			return nil
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version > version || mig.Version <= target {
+				continue
+			}
+			prev := int64(0)
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+			if err := apply(ctx, conn, mig.Down, prev); err != nil {
+				return fmt.Errorf("down %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+	return done, nil
+}
+
+func (m *Migrator) index(version int64) int {
+	for i, mig := range m.migrations {
+		if mig.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory
+// lock. Session locks belong to a connection, hence no plain pool calls.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("take advisory lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func currentVersion(ctx context.Context, conn *pgxpool.Conn) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// apply runs one migration script and records newVersion in the same
+// transaction. Version 0 means no migration applied: the table is left empty,
+// as golang-migrate does.
+func apply(ctx context.Context, conn *pgxpool.Conn, script string, newVersion int64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// No arguments: pgx uses the simple protocol, which accepts a script of
+	// several statements.
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if newVersion > 0 {
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`, newVersion); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
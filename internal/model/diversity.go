@@ -0,0 +1,51 @@
+package model
+
+import (
+	"cmp"
+	"crypto/md5"
+	"encoding/hex"
+	"slices"
+)
+
+// ReviewCandidate is a possible reviewer of a PR. RecentReviews counts the
+// reviews of the author's other PRs they were assigned within the team's
+// look-back.
+type ReviewCandidate struct {
+	UserID        string
+	RecentReviews int
+}
+
+// RankByDiversity returns the IDs of at most limit candidates, those who
+// reviewed the author least recently first. Ties are broken by a hash of the
+// PR and user IDs rather than at random: the choice is reproducible for a
+// given PR, yet differs between PRs so that the same people are not always
+// picked first.
+func RankByDiversity(prID string, candidates []ReviewCandidate, limit int) []string {
+	type ranked struct {
+		ReviewCandidate
+		hash string
+	}
+
+	rs := make([]ranked, len(candidates))
+	for i, c := range candidates {
+		sum := md5.Sum([]byte(prID + ":" + c.UserID))
+		rs[i] = ranked{ReviewCandidate: c, hash: hex.EncodeToString(sum[:])}
+	}
+
+	slices.SortFunc(rs, func(a, b ranked) int {
+		return cmp.Or(
+			cmp.Compare(a.RecentReviews, b.RecentReviews),
+			cmp.Compare(a.hash, b.hash),
+			cmp.Compare(a.UserID, b.UserID),
+		)
+	})
+
+	var ids []string
+	for _, r := range rs {
+		if len(ids) >= limit {
+			break
+		}
+		ids = append(ids, r.UserID)
+	}
+	return ids
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRankByDiversity(t *testing.T) {
+	tied := []ReviewCandidate{{UserID: "u1"}, {UserID: "u2"}, {UserID: "u3"}, {UserID: "u4"}}
+
+	tests := []struct {
+		name       string
+		prID       string
+		candidates []ReviewCandidate
+		limit      int
+		want       []string
+	}{
+		{
+			name: "fewest recent reviews first",
+			prID: "pr-1",
+			candidates: []ReviewCandidate{
+				{UserID: "u1", RecentReviews: 3},
+				{UserID: "u2", RecentReviews: 0},
+				{UserID: "u3", RecentReviews: 1},
+				{UserID: "u4", RecentReviews: 2},
+			},
+			limit: 4,
+			want:  []string{"u2", "u3", "u4", "u1"},
+		},
+		{
+			name: "penalty outweighs the tie-break",
+			prID: "pr-1",
+			candidates: []ReviewCandidate{
+				// u2 has the lowest hash for pr-1 but reviewed the author.
+				{UserID: "u1"},
+				{UserID: "u2", RecentReviews: 1},
+				{UserID: "u3"},
+			},
+			limit: 2,
+			want:  []string{"u3", "u1"},
+		},
+		{
+			// md5("pr-1:u2") < md5("pr-1:u3") < md5("pr-1:u4") < md5("pr-1:u1").
+			name:       "ties by md5 of pr and user",
+			prID:       "pr-1",
+			candidates: tied,
+			limit:      4,
+			want:       []string{"u2", "u3", "u4", "u1"},
+		},
+		{
+			// md5("pr-2:u2") < md5("pr-2:u4") < md5("pr-2:u3") < md5("pr-2:u1").
+			name:       "tie order differs between prs",
+			prID:       "pr-2",
+			candidates: tied,
+			limit:      4,
+			want:       []string{"u2", "u4", "u3", "u1"},
+		},
+		{
+			name:       "limit",
+			prID:       "pr-2",
+			candidates: tied,
+			limit:      2,
+			want:       []string{"u2", "u4"},
+		},
+		{
+			name:       "fewer candidates than the limit",
+			prID:       "pr-1",
+			candidates: []ReviewCandidate{{UserID: "u1", RecentReviews: 1}},
+			limit:      2,
+			want:       []string{"u1"},
+		},
+		{
+			name:  "no candidates",
+			prID:  "pr-1",
+			limit: 2,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RankByDiversity(tt.prID, tt.candidates, tt.limit)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("RankByDiversity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankByDiversityIgnoresInputOrder(t *testing.T) {
+	candidates := []ReviewCandidate{
+		{UserID: "u1", RecentReviews: 1},
+		{UserID: "u2"},
+		{UserID: "u3", RecentReviews: 1},
+		{UserID: "u4"},
+	}
+	want := RankByDiversity("pr-7", candidates, len(candidates))
+
+	reversed := slices.Clone(candidates)
+	slices.Reverse(reversed)
+	if got := RankByDiversity("pr-7", reversed, len(candidates)); !slices.Equal(got, want) {
+		t.Errorf("RankByDiversity() on reversed input = %v, want %v", got, want)
+	}
+}
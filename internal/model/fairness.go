@@ -0,0 +1,123 @@
+package model
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// MemberLoad is how many reviews an active member was assigned in a window.
+// AuthoredPRs counts the member's own PRs of the team in the same window,
+// which they could not be assigned to.
+type MemberLoad struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	Assignments int    `json:"assignments"`
+	AuthoredPRs int    `json:"authored_prs"`
+}
+
+type TeamLoad struct {
+	TeamName string
+	Members  []MemberLoad
+}
+
+// LoadOutlier is a member whose load is off the team mean by more than the
+// tolerance. Deviation is relative to the mean: 0.5 is half as much again,
+// -0.5 half as much.
+type LoadOutlier struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	Assignments int     `json:"assignments"`
+	Deviation   float64 `json:"deviation"`
+	ZScore      float64 `json:"z_score"`
+}
+
+// TeamFairness describes how evenly reviews were spread over a team's active
+// members. MaxMinRatio is nil when someone got no reviews at all.
+type TeamFairness struct {
+	TeamName     string        `json:"team_name"`
+	Members      int           `json:"members"`
+	Assignments  int           `json:"assignments"`
+	Mean         float64       `json:"mean"`
+	StdDev       float64       `json:"stddev"`
+	Gini         float64       `json:"gini"`
+	MaxMinRatio  *float64      `json:"max_min_ratio"`
+	Distribution []MemberLoad  `json:"distribution"`
+	Outliers     []LoadOutlier `json:"outliers"`
+}
+
+type FairnessReport struct {
+	From      *time.Time     `json:"from,omitempty"`
+	To        *time.Time     `json:"to,omitempty"`
+	Tolerance float64        `json:"tolerance"`
+	Teams     []TeamFairness `json:"teams"`
+}
+
+// MeasureFairness computes the spread of a team's review load. StdDev is the
+// population deviation, since the members are the whole population rather
+// than a sample of it.
+func MeasureFairness(team TeamLoad, tolerance float64) TeamFairness {
+	f := TeamFairness{
+		TeamName:     team.TeamName,
+		Members:      len(team.Members),
+		Distribution: team.Members,
+		Outliers:     []LoadOutlier{},
+	}
+	if f.Distribution == nil {
+		f.Distribution = []MemberLoad{}
+	}
+	if len(team.Members) == 0 {
+		return f
+	}
+
+	counts := make([]int, len(team.Members))
+	for i, m := range team.Members {
+		counts[i] = m.Assignments
+		f.Assignments += m.Assignments
+	}
+	slices.Sort(counts)
+
+	n := float64(len(counts))
+	f.Mean = float64(f.Assignments) / n
+
+	var squares float64
+	for _, c := range counts {
+		squares += (float64(c) - f.Mean) * (float64(c) - f.Mean)
+	}
+	f.StdDev = math.Sqrt(squares / n)
+
+	// Gini over the ascending counts: sum((2i - n - 1) * x_i) / (n * sum(x)).
+	if f.Assignments > 0 {
+		var weighted float64
+		for i, c := range counts {
+			weighted += float64(2*(i+1)-len(counts)-1) * float64(c)
+		}
+		f.Gini = weighted / (n * float64(f.Assignments))
+	}
+
+	if lo, hi := counts[0], counts[len(counts)-1]; lo > 0 {
+		ratio := float64(hi) / float64(lo)
+		f.MaxMinRatio = &ratio
+	}
+
+	if f.Mean == 0 {
+		return f
+	}
+	for _, m := range team.Members {
+		deviation := (float64(m.Assignments) - f.Mean) / f.Mean
+		if math.Abs(deviation) <= tolerance {
+			continue
+		}
+		o := LoadOutlier{
+			UserID:      m.UserID,
+			Username:    m.Username,
+			Assignments: m.Assignments,
+			Deviation:   deviation,
+		}
+		if f.StdDev > 0 {
+			o.ZScore = (float64(m.Assignments) - f.Mean) / f.StdDev
+		}
+		f.Outliers = append(f.Outliers, o)
+	}
+	return f
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestMeasureFairness(t *testing.T) {
+	ratio := func(r float64) *float64 { return &r }
+	loads := func(counts ...int) []MemberLoad {
+		members := make([]MemberLoad, len(counts))
+		for i, c := range counts {
+			members[i] = MemberLoad{UserID: fmt.Sprintf("u%d", i+1), Assignments: c}
+		}
+		return members
+	}
+
+	tests := []struct {
+		name     string
+		members  []MemberLoad
+		mean     float64
+		stdDev   float64
+		gini     float64
+		ratio    *float64
+		outliers []string
+	}{
+		{name: "no members"},
+		{name: "all equal", members: loads(4, 4, 4), mean: 4, ratio: ratio(1)},
+		{name: "all zero", members: loads(0, 0, 0)},
+		{name: "single member", members: loads(5), mean: 5, ratio: ratio(1)},
+		{
+			// Gini: (-2*0 + 0*2 + 2*4) / (3 * 6).
+			name:     "someone with zero",
+			members:  loads(4, 0, 2),
+			mean:     2,
+			stdDev:   math.Sqrt(8.0 / 3),
+			gini:     4.0 / 9,
+			outliers: []string{"u1", "u2"},
+		},
+		{
+			// Gini: (-3*1 - 1*2 + 1*3 + 3*4) / (4 * 10).
+			name:     "known gini",
+			members:  loads(1, 2, 3, 4),
+			mean:     2.5,
+			stdDev:   math.Sqrt(1.25),
+			gini:     0.25,
+			ratio:    ratio(4),
+			outliers: []string{"u1", "u4"},
+		},
+	}
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := MeasureFairness(TeamLoad{TeamName: "backend", Members: tt.members}, 0.5)
+
+			if f.Members != len(tt.members) || len(f.Distribution) != len(tt.members) {
+				t.Errorf("Members = %d, Distribution = %d entries, want %d", f.Members, len(f.Distribution), len(tt.members))
+			}
+			if !near(f.Mean, tt.mean) {
+				t.Errorf("Mean = %v, want %v", f.Mean, tt.mean)
+			}
+			if !near(f.StdDev, tt.stdDev) {
+				t.Errorf("StdDev = %v, want %v", f.StdDev, tt.stdDev)
+			}
+			if !near(f.Gini, tt.gini) {
+				t.Errorf("Gini = %v, want %v", f.Gini, tt.gini)
+			}
+			switch {
+			case tt.ratio == nil && f.MaxMinRatio != nil:
+				t.Errorf("MaxMinRatio = %v, want nil", *f.MaxMinRatio)
+			case tt.ratio != nil && (f.MaxMinRatio == nil || !near(*f.MaxMinRatio, *tt.ratio)):
+				t.Errorf("MaxMinRatio = %v, want %v", f.MaxMinRatio, *tt.ratio)
+			}
+
+			var outliers []string
+			for _, o := range f.Outliers {
+				outliers = append(outliers, o.UserID)
+				if want := (float64(o.Assignments) - tt.mean) / tt.stdDev; !near(o.ZScore, want) {
+					t.Errorf("outlier %s ZScore = %v, want %v", o.UserID, o.ZScore, want)
+				}
+			}
+			if !slices.Equal(outliers, tt.outliers) {
+				t.Errorf("outliers = %v, want %v", outliers, tt.outliers)
+			}
+		})
+	}
+}
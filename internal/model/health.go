@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	HealthOK   = "ok"
+	HealthFail = "fail"
+)
+
+type HealthCheck struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+	Detail  string
+	Error   string
+}
+
+type HealthReport struct {
+	Status string
+	Checks []HealthCheck
+}
+
+func (r *HealthReport) Healthy() bool {
+	return r.Status == HealthOK
+}
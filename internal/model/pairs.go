@@ -0,0 +1,96 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+type PairMember struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ReviewPair is how many reviews of AuthorID's PRs ReviewerID was assigned.
+type ReviewPair struct {
+	AuthorID    string `json:"author_id"`
+	ReviewerID  string `json:"reviewer_id"`
+	Assignments int    `json:"assignments"`
+}
+
+// MemberPair is an unordered pair of members; A sorts before B.
+type MemberPair struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+// PairMatrix is the author x reviewer matrix of a team: Matrix[i][j] counts
+// the reviews of Members[i]'s PRs assigned to Members[j].
+type PairMatrix struct {
+	TeamName    string       `json:"team_name"`
+	From        *time.Time   `json:"from,omitempty"`
+	To          *time.Time   `json:"to,omitempty"`
+	Members     []PairMember `json:"members"`
+	Matrix      [][]int      `json:"matrix"`
+	TopPairs    []ReviewPair `json:"top_pairs"`
+	NeverPaired []MemberPair `json:"never_paired"`
+}
+
+// BuildPairMatrix lays the pair counts out over the members, in their order.
+// Pairs involving anyone who is not a member are dropped. TopPairs holds at
+// most top non-zero pairs, most assignments first; NeverPaired lists the
+// members who did not review each other in either direction.
+func BuildPairMatrix(members []PairMember, pairs []ReviewPair, top int) PairMatrix {
+	m := PairMatrix{
+		Members:     members,
+		Matrix:      make([][]int, len(members)),
+		TopPairs:    []ReviewPair{},
+		NeverPaired: []MemberPair{},
+	}
+	if m.Members == nil {
+		m.Members = []PairMember{}
+	}
+
+	index := make(map[string]int, len(members))
+	for i, member := range members {
+		index[member.UserID] = i
+		m.Matrix[i] = make([]int, len(members))
+	}
+
+	var counted []ReviewPair
+	for _, p := range pairs {
+		a, okA := index[p.AuthorID]
+		r, okR := index[p.ReviewerID]
+		if !okA || !okR || p.Assignments == 0 {
+			continue
+		}
+		m.Matrix[a][r] += p.Assignments
+		counted = append(counted, p)
+	}
+
+	slices.SortFunc(counted, func(x, y ReviewPair) int {
+		return cmp.Or(
+			cmp.Compare(y.Assignments, x.Assignments),
+			cmp.Compare(x.AuthorID, y.AuthorID),
+			cmp.Compare(x.ReviewerID, y.ReviewerID),
+		)
+	})
+	if len(counted) > top {
+		counted = counted[:top]
+	}
+	m.TopPairs = append(m.TopPairs, counted...)
+
+	for i := range members {
+		for j := i + 1; j < len(members); j++ {
+			if m.Matrix[i][j] > 0 || m.Matrix[j][i] > 0 {
+				continue
+			}
+			a, b := members[i].UserID, members[j].UserID
+			if b < a {
+				a, b = b, a
+			}
+			m.NeverPaired = append(m.NeverPaired, MemberPair{A: a, B: b})
+		}
+	}
+	return m
+}
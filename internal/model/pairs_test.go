@@ -0,0 +1,94 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildPairMatrix(t *testing.T) {
+	members := []PairMember{{UserID: "u3"}, {UserID: "u1"}, {UserID: "u2"}}
+
+	tests := []struct {
+		name        string
+		members     []PairMember
+		pairs       []ReviewPair
+		top         int
+		matrix      [][]int
+		topPairs    []ReviewPair
+		neverPaired []MemberPair
+	}{
+		{
+			name:    "non-members and zero counts are dropped",
+			members: members,
+			pairs: []ReviewPair{
+				{AuthorID: "u1", ReviewerID: "u2", Assignments: 2},
+				{AuthorID: "u1", ReviewerID: "ex", Assignments: 5},
+				{AuthorID: "ex", ReviewerID: "u3", Assignments: 5},
+				{AuthorID: "u3", ReviewerID: "u1", Assignments: 0},
+			},
+			top:         10,
+			matrix:      [][]int{{0, 0, 0}, {0, 0, 2}, {0, 0, 0}},
+			topPairs:    []ReviewPair{{AuthorID: "u1", ReviewerID: "u2", Assignments: 2}},
+			neverPaired: []MemberPair{{A: "u1", B: "u3"}, {A: "u2", B: "u3"}},
+		},
+		{
+			name:    "top keeps the most assignments, ties by author then reviewer",
+			members: members,
+			pairs: []ReviewPair{
+				{AuthorID: "u3", ReviewerID: "u1", Assignments: 1},
+				{AuthorID: "u2", ReviewerID: "u3", Assignments: 3},
+				{AuthorID: "u2", ReviewerID: "u1", Assignments: 3},
+				{AuthorID: "u1", ReviewerID: "u3", Assignments: 3},
+			},
+			top:    2,
+			matrix: [][]int{{0, 1, 0}, {3, 0, 0}, {3, 3, 0}},
+			topPairs: []ReviewPair{
+				{AuthorID: "u1", ReviewerID: "u3", Assignments: 3},
+				{AuthorID: "u2", ReviewerID: "u1", Assignments: 3},
+			},
+			neverPaired: []MemberPair{},
+		},
+		{
+			name:    "one direction is enough to have paired",
+			members: members,
+			pairs: []ReviewPair{
+				{AuthorID: "u2", ReviewerID: "u3", Assignments: 1},
+				{AuthorID: "u1", ReviewerID: "u2", Assignments: 1},
+			},
+			top:    10,
+			matrix: [][]int{{0, 0, 0}, {0, 0, 1}, {1, 0, 0}},
+			topPairs: []ReviewPair{
+				{AuthorID: "u1", ReviewerID: "u2", Assignments: 1},
+				{AuthorID: "u2", ReviewerID: "u3", Assignments: 1},
+			},
+			neverPaired: []MemberPair{{A: "u1", B: "u3"}},
+		},
+		{
+			name:        "no members",
+			pairs:       []ReviewPair{{AuthorID: "u1", ReviewerID: "u2", Assignments: 1}},
+			top:         10,
+			matrix:      [][]int{},
+			topPairs:    []ReviewPair{},
+			neverPaired: []MemberPair{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := BuildPairMatrix(tt.members, tt.pairs, tt.top)
+
+			if m.Members == nil {
+				t.Error("Members is nil, want an empty slice")
+			}
+			if !reflect.DeepEqual(m.Matrix, tt.matrix) {
+				t.Errorf("Matrix = %v, want %v", m.Matrix, tt.matrix)
+			}
+			if !reflect.DeepEqual(m.TopPairs, tt.topPairs) {
+				t.Errorf("TopPairs = %+v, want %+v", m.TopPairs, tt.topPairs)
+			}
+			if !reflect.DeepEqual(m.NeverPaired, tt.neverPaired) {
+				t.Errorf("NeverPaired = %+v, want %+v", m.NeverPaired, tt.neverPaired)
+			}
+		})
+	}
+}
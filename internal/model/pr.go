@@ -11,6 +11,7 @@ type PullRequest struct {
 	PRID              string
 	PRName            string
 	AuthorID          string
+	TeamName          string
 	Status            string
 	AssignedReviewers []string
 	CreatedAt         time.Time
@@ -21,4 +22,7 @@ type CreatePRRequest struct {
 	PRID     string
 	PRName   string
 	AuthorID string
+	// TeamName selects the team the PR is for. Empty means the author's
+	// primary team.
+	TeamName string
 }
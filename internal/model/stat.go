@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type ReviewerStats struct {
 	UserID        string `json:"user_id"`
 	Username      string `json:"username"`
@@ -23,3 +25,168 @@ type Statistics struct {
 	ReviewersStats []ReviewerStats `json:"reviewers_stats"`
 	PRStats        []PRStats       `json:"pr_stats"`
 }
+
+// StatisticsFilter narrows the overall statistics. Reviewers are limited to
+// members of TeamName, and their review counts only cover PRs that match
+// every filter.
+type StatisticsFilter struct {
+	TeamName *string
+	Range    TimeRange
+	Status   *string
+}
+
+// Page is an offset window into a list.
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// CounterCheck reports how many rows of the statistics counters a rebuild
+// found out of date.
+type CounterCheck struct {
+	TeamRows     int `json:"team_rows"`
+	ReviewerRows int `json:"reviewer_rows"`
+}
+
+// UnitTotals aggregates a team together with every unit below it.
+type UnitTotals struct {
+	Teams     int `json:"teams"`
+	Members   int `json:"members"`
+	TotalPRs  int `json:"total_prs"`
+	OpenPRs   int `json:"open_prs"`
+	MergedPRs int `json:"merged_prs"`
+}
+
+type UnitStats struct {
+	TeamName   string     `json:"team_name"`
+	ParentTeam *string    `json:"parent_team"`
+	Depth      int        `json:"depth"`
+	Members    int        `json:"members"`
+	TotalPRs   int        `json:"total_prs"`
+	OpenPRs    int        `json:"open_prs"`
+	MergedPRs  int        `json:"merged_prs"`
+	Subtree    UnitTotals `json:"subtree"`
+}
+
+type SubtreeStatistics struct {
+	RootTeam string      `json:"root_team"`
+	Units    []UnitStats `json:"units"`
+}
+
+// TimeRange selects pull requests by creation time, From inclusive and To
+// exclusive. Nil bounds are open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// Percentiles are durations in seconds.
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+type TimingSummary struct {
+	TotalPRs  int `json:"total_prs"`
+	MergedPRs int `json:"merged_prs"`
+	// UnassignedPRs never had a reviewer and are left out of
+	// TimeToFirstAssignment.
+	UnassignedPRs           int          `json:"unassigned_prs"`
+	TimeToFirstAssignment   *Percentiles `json:"time_to_first_assignment"`
+	TimeToMerge             *Percentiles `json:"time_to_merge"`
+	Reassignments           int          `json:"reassignments"`
+	ReassignmentsPerPR      float64      `json:"reassignments_per_pr"`
+	MergedUnderTwoReviewers int          `json:"merged_under_two_reviewers"`
+	// MergedUnderTwoReviewersShare is a fraction of MergedPRs, 0 when none
+	// were merged.
+	MergedUnderTwoReviewersShare float64 `json:"merged_under_two_reviewers_share"`
+}
+
+type TeamTiming struct {
+	TeamName              string       `json:"team_name"`
+	TotalPRs              int          `json:"total_prs"`
+	MergedPRs             int          `json:"merged_prs"`
+	TimeToFirstAssignment *Percentiles `json:"time_to_first_assignment"`
+	TimeToMerge           *Percentiles `json:"time_to_merge"`
+	Reassignments         int          `json:"reassignments"`
+}
+
+// ReviewerTiming measures merged PRs from the reviewer's assignment, so that
+// reviewers who joined through a reassignment are not charged for the wait
+// before it.
+type ReviewerTiming struct {
+	UserID        string       `json:"user_id"`
+	Username      string       `json:"username"`
+	MergedReviews int          `json:"merged_reviews"`
+	TimeToMerge   *Percentiles `json:"time_to_merge"`
+}
+
+type PRReassignments struct {
+	PullRequestID string `json:"pull_request_id"`
+	TeamName      string `json:"team_name"`
+	Reassignments int    `json:"reassignments"`
+}
+
+type TimingStatistics struct {
+	From         *time.Time        `json:"from,omitempty"`
+	To           *time.Time        `json:"to,omitempty"`
+	Summary      TimingSummary     `json:"summary"`
+	Teams        []TeamTiming      `json:"teams"`
+	Reviewers    []ReviewerTiming  `json:"reviewers"`
+	PullRequests []PRReassignments `json:"pull_requests"`
+}
+
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// HistoryQuery selects daily snapshots from From to To, both inclusive UTC
+// dates, grouped into periods of Granularity.
+type HistoryQuery struct {
+	TeamName    *string
+	From        time.Time
+	To          time.Time
+	Granularity string
+}
+
+// TeamHistoryPoint covers one period. OpenPRs is the count at the end of its
+// last day and OpenPRsAvg the daily average; the other counts sum over it.
+type TeamHistoryPoint struct {
+	PeriodStart string  `json:"period_start"`
+	OpenPRs     int     `json:"open_prs"`
+	OpenPRsAvg  float64 `json:"open_prs_avg"`
+	CreatedPRs  int     `json:"created_prs"`
+	MergedPRs   int     `json:"merged_prs"`
+}
+
+// ReviewerHistoryPoint is the reviewer's counterpart of TeamHistoryPoint:
+// MergedReviews counts PRs the reviewer was on when they were merged.
+type ReviewerHistoryPoint struct {
+	PeriodStart     string  `json:"period_start"`
+	OpenReviews     int     `json:"open_reviews"`
+	OpenReviewsAvg  float64 `json:"open_reviews_avg"`
+	AssignedReviews int     `json:"assigned_reviews"`
+	MergedReviews   int     `json:"merged_reviews"`
+}
+
+type TeamHistory struct {
+	TeamName string             `json:"team_name"`
+	Points   []TeamHistoryPoint `json:"points"`
+}
+
+type ReviewerHistory struct {
+	UserID string                 `json:"user_id"`
+	Points []ReviewerHistoryPoint `json:"points"`
+}
+
+type StatisticsHistory struct {
+	TeamName    *string           `json:"team_name,omitempty"`
+	Granularity string            `json:"granularity"`
+	From        string            `json:"from"`
+	To          string            `json:"to"`
+	Teams       []TeamHistory     `json:"teams"`
+	Reviewers   []ReviewerHistory `json:"reviewers"`
+}
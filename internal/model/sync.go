@@ -0,0 +1,349 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"sort"
+)
+
+type SyncTeamAction string
+
+const (
+	SyncCreateTeam  SyncTeamAction = "create"
+	SyncRestoreTeam SyncTeamAction = "restore"
+	SyncArchiveTeam SyncTeamAction = "archive"
+)
+
+type SyncUserAction string
+
+const (
+	SyncAddUser        SyncUserAction = "add"
+	SyncMoveUser       SyncUserAction = "move"
+	SyncUpdateUser     SyncUserAction = "update"
+	SyncDeactivateUser SyncUserAction = "deactivate"
+	SyncRemoveUser     SyncUserAction = "remove"
+)
+
+// MissingUsers selects what happens to users absent from the desired state.
+type MissingUsers string
+
+const (
+	MissingUsersDeactivate MissingUsers = "deactivate"
+	MissingUsersRemove     MissingUsers = "remove"
+)
+
+type SyncOptions struct {
+	MissingUsers MissingUsers
+}
+
+// OrgState is the part of the database an org sync compares against.
+type OrgState struct {
+	// Teams maps every team to whether it is archived.
+	Teams map[string]bool
+	Users map[string]*OrgUser
+	// OpenReviews lists the reviewer assignments on open pull requests.
+	OpenReviews []OpenReview
+}
+
+type OrgUser struct {
+	ID          string
+	Username    string
+	PrimaryTeam string
+	IsActive    bool
+	Memberships map[string]TeamMembership
+	// AuthorsOpenPRs users cannot be removed, only deactivated.
+	AuthorsOpenPRs bool
+	// HasHistory is set for users who authored any PR or reviewed a merged
+	// one. Removing them would take that history along, so they are
+	// deactivated instead, like DeleteUser refuses them.
+	HasHistory bool
+}
+
+type OpenReview struct {
+	PRID       string
+	TeamName   string
+	AuthorID   string
+	ReviewerID string
+}
+
+type SyncTeamChange struct {
+	TeamName string
+	Action   SyncTeamAction
+}
+
+type SyncUserChange struct {
+	UserID      string
+	Username    string
+	Action      SyncUserAction
+	PrimaryTeam string
+	FromTeams   []string
+	ToTeams     []string
+	Reason      string
+
+	// Memberships is the desired state of an added, moved or updated user,
+	// sorted by team; empty for deactivate and remove.
+	Memberships []DesiredMembership
+}
+
+type DesiredMembership struct {
+	TeamName string
+	Role     string
+	IsActive bool
+}
+
+type AffectedReview struct {
+	PRID       string
+	TeamName   string
+	ReviewerID string
+	AuthorID   string
+	// Reason is the user action that takes the reviewer off the PR's team.
+	Reason SyncUserAction
+}
+
+// SyncPlan is the difference between the database and a desired state. ID is
+// a digest of the changes: applying with the ID of a reviewed plan fails if
+// the database has drifted since.
+type SyncPlan struct {
+	ID              string
+	Teams           []SyncTeamChange
+	Users           []SyncUserChange
+	AffectedReviews []AffectedReview
+}
+
+func (p *SyncPlan) Empty() bool {
+	return len(p.Teams) == 0 && len(p.Users) == 0
+}
+
+// SyncReassignment records where a review affected by an applied plan went.
+// ReplacedBy is nil when the PR's team had no one available.
+type SyncReassignment struct {
+	PRID       string
+	ReviewerID string
+	ReplacedBy *string
+}
+
+type SyncResult struct {
+	Plan          *SyncPlan
+	Reassignments []SyncReassignment
+}
+
+// PlanSync computes the changes that turn current into desired. Teams missing
+// from desired are archived, users missing from it are handled according to
+// opts.MissingUsers. Reviews are affected when their reviewer changes and is
+// no longer an active member of the PR's team afterwards.
+func PlanSync(current *OrgState, desired []*Team, opts SyncOptions) *SyncPlan {
+	plan := &SyncPlan{
+		Teams:           []SyncTeamChange{},
+		Users:           []SyncUserChange{},
+		AffectedReviews: []AffectedReview{},
+	}
+
+	desiredTeams := make(map[string]bool, len(desired))
+	for _, team := range desired {
+		desiredTeams[team.TeamName] = true
+		archived, exists := current.Teams[team.TeamName]
+		switch {
+		case !exists:
+			plan.Teams = append(plan.Teams, SyncTeamChange{TeamName: team.TeamName, Action: SyncCreateTeam})
+		case archived:
+			plan.Teams = append(plan.Teams, SyncTeamChange{TeamName: team.TeamName, Action: SyncRestoreTeam})
+		}
+	}
+	for name, archived := range current.Teams {
+		if !archived && !desiredTeams[name] {
+			plan.Teams = append(plan.Teams, SyncTeamChange{TeamName: name, Action: SyncArchiveTeam})
+		}
+	}
+	sort.Slice(plan.Teams, func(i, j int) bool { return plan.Teams[i].TeamName < plan.Teams[j].TeamName })
+
+	// Desired memberships per user, in input order so that the first team a
+	// new user appears in becomes their primary one.
+	desiredUsers := make(map[string][]DesiredMembership)
+	usernames := make(map[string]string)
+	var userOrder []string
+	for _, team := range desired {
+		for _, m := range team.Members {
+			if _, ok := desiredUsers[m.UserID]; !ok {
+				userOrder = append(userOrder, m.UserID)
+				usernames[m.UserID] = m.Username
+			}
+			desiredUsers[m.UserID] = append(desiredUsers[m.UserID], DesiredMembership{
+				TeamName: team.TeamName,
+				Role:     m.Role,
+				IsActive: m.IsActive,
+			})
+		}
+	}
+
+	// activeAfter reports whether the user reviews for the team once the
+	// plan is applied; users missing from the map are left untouched.
+	activeAfter := make(map[string]map[string]bool)
+	changed := make(map[string]SyncUserAction)
+
+	for _, id := range userOrder {
+		members := desiredUsers[id]
+		toTeams := make([]string, len(members))
+		active := make(map[string]bool, len(members))
+		for i, m := range members {
+			toTeams[i] = m.TeamName
+			active[m.TeamName] = m.IsActive
+		}
+
+		change := SyncUserChange{
+			UserID:      id,
+			Username:    usernames[id],
+			ToTeams:     sortedCopy(toTeams),
+			FromTeams:   []string{},
+			Memberships: sortedMemberships(members),
+		}
+
+		cur, exists := current.Users[id]
+		if !exists {
+			change.Action = SyncAddUser
+			change.PrimaryTeam = toTeams[0]
+		} else {
+			change.FromTeams = sortedKeys(cur.Memberships)
+			change.PrimaryTeam = cur.PrimaryTeam
+			if !slices.Contains(toTeams, cur.PrimaryTeam) {
+				change.PrimaryTeam = toTeams[0]
+			}
+
+			switch {
+			case !slices.Equal(change.FromTeams, change.ToTeams) || change.PrimaryTeam != cur.PrimaryTeam:
+				change.Action = SyncMoveUser
+			case !cur.IsActive:
+				change.Action = SyncUpdateUser
+				change.Reason = "reactivated"
+			case cur.Username != change.Username:
+				change.Action = SyncUpdateUser
+				change.Reason = "renamed from " + cur.Username
+			case membershipsDiffer(cur.Memberships, members):
+				change.Action = SyncUpdateUser
+				change.Reason = "role or active flag changed"
+			default:
+				continue
+			}
+		}
+
+		plan.Users = append(plan.Users, change)
+		activeAfter[id] = active
+		changed[id] = change.Action
+	}
+
+	var missing []string
+	for id := range current.Users {
+		if _, ok := desiredUsers[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+
+	for _, id := range missing {
+		cur := current.Users[id]
+		change := SyncUserChange{
+			UserID:    id,
+			Username:  cur.Username,
+			FromTeams: sortedKeys(cur.Memberships),
+			ToTeams:   []string{},
+		}
+
+		switch {
+		case opts.MissingUsers == MissingUsersRemove && cur.AuthorsOpenPRs:
+			if !cur.IsActive {
+				continue
+			}
+			change.Action = SyncDeactivateUser
+			change.Reason = "authors open pull requests, cannot be removed"
+		case opts.MissingUsers == MissingUsersRemove && cur.HasHistory:
+			if !cur.IsActive {
+				continue
+			}
+			change.Action = SyncDeactivateUser
+			change.Reason = "has pull request history, cannot be removed"
+		case opts.MissingUsers == MissingUsersRemove:
+			change.Action = SyncRemoveUser
+		case cur.IsActive:
+			change.Action = SyncDeactivateUser
+		default:
+			continue
+		}
+
+		plan.Users = append(plan.Users, change)
+		activeAfter[id] = nil
+		changed[id] = change.Action
+	}
+
+	sort.SliceStable(plan.Users, func(i, j int) bool { return plan.Users[i].UserID < plan.Users[j].UserID })
+
+	for _, rv := range current.OpenReviews {
+		action, ok := changed[rv.ReviewerID]
+		if !ok || activeAfter[rv.ReviewerID][rv.TeamName] {
+			continue
+		}
+		plan.AffectedReviews = append(plan.AffectedReviews, AffectedReview{
+			PRID:       rv.PRID,
+			TeamName:   rv.TeamName,
+			ReviewerID: rv.ReviewerID,
+			AuthorID:   rv.AuthorID,
+			Reason:     action,
+		})
+	}
+	sort.Slice(plan.AffectedReviews, func(i, j int) bool {
+		a, b := plan.AffectedReviews[i], plan.AffectedReviews[j]
+		if a.PRID != b.PRID {
+			return a.PRID < b.PRID
+		}
+		return a.ReviewerID < b.ReviewerID
+	})
+
+	plan.ID = planID(plan)
+	return plan
+}
+
+// sortedMemberships orders memberships by team, so that listing the same
+// teams in another order yields the same plan ID.
+func sortedMemberships(members []DesiredMembership) []DesiredMembership {
+	out := slices.Clone(members)
+	sort.Slice(out, func(i, j int) bool { return out[i].TeamName < out[j].TeamName })
+	return out
+}
+
+func membershipsDiffer(cur map[string]TeamMembership, desired []DesiredMembership) bool {
+	for _, m := range desired {
+		c := cur[m.TeamName]
+		if c.Role != m.Role || c.IsActive != m.IsActive {
+			return true
+		}
+	}
+	return false
+}
+
+// planID digests everything a reviewer of the plan sees, plus the desired
+// roles and flags, so that editing either invalidates an approved plan.
+func planID(plan *SyncPlan) string {
+	data, _ := json.Marshal(struct {
+		Teams   []SyncTeamChange
+		Users   []SyncUserChange
+		Reviews []AffectedReview
+	}{plan.Teams, plan.Users, plan.AffectedReviews})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedCopy(s []string) []string {
+	out := slices.Clone(s)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,373 @@
+package model
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func syncTeam(name string, members ...*TeamMember) *Team {
+	return &Team{TeamName: name, Members: members}
+}
+
+func syncMember(id string, role string, active bool) *TeamMember {
+	return &TeamMember{UserID: id, Username: id, Role: role, IsActive: active}
+}
+
+// orgUser is an active user named after their ID with active member
+// memberships in teams, the first of which is the primary one.
+func orgUser(id string, teams ...string) *OrgUser {
+	u := &OrgUser{ID: id, Username: id, PrimaryTeam: teams[0], IsActive: true, Memberships: map[string]TeamMembership{}}
+	for _, t := range teams {
+		u.Memberships[t] = TeamMembership{TeamName: t, Role: RoleMember, IsActive: true}
+	}
+	return u
+}
+
+func orgState(users ...*OrgUser) *OrgState {
+	state := &OrgState{Teams: map[string]bool{"backend": false, "payments": false}, Users: map[string]*OrgUser{}}
+	for _, u := range users {
+		state.Users[u.ID] = u
+	}
+	return state
+}
+
+func TestPlanSyncTeams(t *testing.T) {
+	state := orgState()
+	state.Teams["legacy"] = true
+	state.Teams["old"] = true
+
+	desired := []*Team{syncTeam("backend"), syncTeam("legacy"), syncTeam("mobile")}
+	got := PlanSync(state, desired, SyncOptions{MissingUsers: MissingUsersDeactivate}).Teams
+
+	want := []SyncTeamChange{
+		{TeamName: "legacy", Action: SyncRestoreTeam},
+		{TeamName: "mobile", Action: SyncCreateTeam},
+		{TeamName: "payments", Action: SyncArchiveTeam},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PlanSync().Teams = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlanSyncUsers(t *testing.T) {
+	inactive := orgUser("u1", "backend")
+	inactive.IsActive = false
+
+	renamed := orgUser("u1", "backend")
+	renamed.Username = "old-name"
+
+	withOpenPRs := orgUser("u1", "backend")
+	withOpenPRs.AuthorsOpenPRs = true
+
+	withHistory := orgUser("u1", "backend")
+	withHistory.HasHistory = true
+
+	inactiveWithHistory := orgUser("u1", "backend")
+	inactiveWithHistory.IsActive = false
+	inactiveWithHistory.HasHistory = true
+
+	// u2 is kept unchanged in every case so that only u1 shows up.
+	u2 := orgUser("u2", "backend")
+	desiredU2 := syncMember("u2", RoleMember, true)
+
+	tests := []struct {
+		name    string
+		current *OrgState
+		desired []*Team
+		missing MissingUsers
+		want    []SyncUserChange
+	}{
+		{
+			name:    "add takes the first listed team as primary",
+			current: orgState(u2),
+			desired: []*Team{
+				syncTeam("payments", syncMember("u1", RoleLead, true)),
+				syncTeam("backend", syncMember("u1", RoleMember, true), desiredU2),
+			},
+			want: []SyncUserChange{{
+				UserID:      "u1",
+				Username:    "u1",
+				Action:      SyncAddUser,
+				PrimaryTeam: "payments",
+				FromTeams:   []string{},
+				ToTeams:     []string{"backend", "payments"},
+				Memberships: []DesiredMembership{
+					{TeamName: "backend", Role: RoleMember, IsActive: true},
+					{TeamName: "payments", Role: RoleLead, IsActive: true},
+				},
+			}},
+		},
+		{
+			name:    "move keeps the primary team it still belongs to",
+			current: orgState(orgUser("u1", "backend"), u2),
+			desired: []*Team{
+				syncTeam("backend", syncMember("u1", RoleMember, true), desiredU2),
+				syncTeam("payments", syncMember("u1", RoleMember, true)),
+			},
+			want: []SyncUserChange{{
+				UserID:      "u1",
+				Username:    "u1",
+				Action:      SyncMoveUser,
+				PrimaryTeam: "backend",
+				FromTeams:   []string{"backend"},
+				ToTeams:     []string{"backend", "payments"},
+				Memberships: []DesiredMembership{
+					{TeamName: "backend", Role: RoleMember, IsActive: true},
+					{TeamName: "payments", Role: RoleMember, IsActive: true},
+				},
+			}},
+		},
+		{
+			name:    "primary team changes when the user leaves it",
+			current: orgState(orgUser("u1", "backend", "payments"), u2),
+			desired: []*Team{
+				syncTeam("backend", desiredU2),
+				syncTeam("payments", syncMember("u1", RoleMember, true)),
+			},
+			want: []SyncUserChange{{
+				UserID:      "u1",
+				Username:    "u1",
+				Action:      SyncMoveUser,
+				PrimaryTeam: "payments",
+				FromTeams:   []string{"backend", "payments"},
+				ToTeams:     []string{"payments"},
+				Memberships: []DesiredMembership{{TeamName: "payments", Role: RoleMember, IsActive: true}},
+			}},
+		},
+		{
+			name:    "reactivation",
+			current: orgState(inactive, u2),
+			desired: []*Team{syncTeam("backend", syncMember("u1", RoleMember, true), desiredU2)},
+			want: []SyncUserChange{{
+				UserID:      "u1",
+				Username:    "u1",
+				Action:      SyncUpdateUser,
+				PrimaryTeam: "backend",
+				FromTeams:   []string{"backend"},
+				ToTeams:     []string{"backend"},
+				Reason:      "reactivated",
+				Memberships: []DesiredMembership{{TeamName: "backend", Role: RoleMember, IsActive: true}},
+			}},
+		},
+		{
+			name:    "rename",
+			current: orgState(renamed, u2),
+			desired: []*Team{syncTeam("backend", syncMember("u1", RoleMember, true), desiredU2)},
+			want: []SyncUserChange{{
+				UserID:      "u1",
+				Username:    "u1",
+				Action:      SyncUpdateUser,
+				PrimaryTeam: "backend",
+				FromTeams:   []string{"backend"},
+				ToTeams:     []string{"backend"},
+				Reason:      "renamed from old-name",
+				Memberships: []DesiredMembership{{TeamName: "backend", Role: RoleMember, IsActive: true}},
+			}},
+		},
+		{
+			name:    "role change",
+			current: orgState(orgUser("u1", "backend"), u2),
+			desired: []*Team{syncTeam("backend", syncMember("u1", RoleLead, true), desiredU2)},
+			want: []SyncUserChange{{
+				UserID:      "u1",
+				Username:    "u1",
+				Action:      SyncUpdateUser,
+				PrimaryTeam: "backend",
+				FromTeams:   []string{"backend"},
+				ToTeams:     []string{"backend"},
+				Reason:      "role or active flag changed",
+				Memberships: []DesiredMembership{{TeamName: "backend", Role: RoleLead, IsActive: true}},
+			}},
+		},
+		{
+			name:    "unchanged user",
+			current: orgState(orgUser("u1", "backend"), u2),
+			desired: []*Team{syncTeam("backend", syncMember("u1", RoleMember, true), desiredU2)},
+			want:    []SyncUserChange{},
+		},
+		{
+			name:    "missing user is deactivated",
+			current: orgState(orgUser("u1", "backend"), u2),
+			desired: []*Team{syncTeam("backend", desiredU2)},
+			missing: MissingUsersDeactivate,
+			want: []SyncUserChange{{
+				UserID:    "u1",
+				Username:  "u1",
+				Action:    SyncDeactivateUser,
+				FromTeams: []string{"backend"},
+				ToTeams:   []string{},
+			}},
+		},
+		{
+			name:    "missing inactive user is left alone",
+			current: orgState(inactive, u2),
+			desired: []*Team{syncTeam("backend", desiredU2)},
+			missing: MissingUsersDeactivate,
+			want:    []SyncUserChange{},
+		},
+		{
+			name:    "missing user is removed",
+			current: orgState(orgUser("u1", "backend"), u2),
+			desired: []*Team{syncTeam("backend", desiredU2)},
+			missing: MissingUsersRemove,
+			want: []SyncUserChange{{
+				UserID:    "u1",
+				Username:  "u1",
+				Action:    SyncRemoveUser,
+				FromTeams: []string{"backend"},
+				ToTeams:   []string{},
+			}},
+		},
+		{
+			name:    "author of open pull requests is deactivated instead of removed",
+			current: orgState(withOpenPRs, u2),
+			desired: []*Team{syncTeam("backend", desiredU2)},
+			missing: MissingUsersRemove,
+			want: []SyncUserChange{{
+				UserID:    "u1",
+				Username:  "u1",
+				Action:    SyncDeactivateUser,
+				FromTeams: []string{"backend"},
+				ToTeams:   []string{},
+				Reason:    "authors open pull requests, cannot be removed",
+			}},
+		},
+		{
+			name:    "user with history is deactivated instead of removed",
+			current: orgState(withHistory, u2),
+			desired: []*Team{syncTeam("backend", desiredU2)},
+			missing: MissingUsersRemove,
+			want: []SyncUserChange{{
+				UserID:    "u1",
+				Username:  "u1",
+				Action:    SyncDeactivateUser,
+				FromTeams: []string{"backend"},
+				ToTeams:   []string{},
+				Reason:    "has pull request history, cannot be removed",
+			}},
+		},
+		{
+			name:    "inactive user with history is left alone",
+			current: orgState(inactiveWithHistory, u2),
+			desired: []*Team{syncTeam("backend", desiredU2)},
+			missing: MissingUsersRemove,
+			want:    []SyncUserChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanSync(tt.current, tt.desired, SyncOptions{MissingUsers: tt.missing}).Users
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanSync().Users = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanSyncAffectedReviews(t *testing.T) {
+	reviews := func(reviewers ...string) []OpenReview {
+		var out []OpenReview
+		for _, r := range reviewers {
+			out = append(out, OpenReview{PRID: "pr-" + r, TeamName: "backend", AuthorID: "author", ReviewerID: r})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		current *OrgState
+		desired []*Team
+		missing MissingUsers
+		want    []AffectedReview
+	}{
+		{
+			name:    "reviewer moved to another team",
+			current: orgState(orgUser("u1", "backend")),
+			desired: []*Team{syncTeam("backend"), syncTeam("payments", syncMember("u1", RoleMember, true))},
+			want:    []AffectedReview{{PRID: "pr-u1", TeamName: "backend", ReviewerID: "u1", AuthorID: "author", Reason: SyncMoveUser}},
+		},
+		{
+			name:    "reviewer deactivated in the pr's team",
+			current: orgState(orgUser("u1", "backend")),
+			desired: []*Team{syncTeam("backend", syncMember("u1", RoleMember, false))},
+			want:    []AffectedReview{{PRID: "pr-u1", TeamName: "backend", ReviewerID: "u1", AuthorID: "author", Reason: SyncUpdateUser}},
+		},
+		{
+			name:    "missing reviewer removed",
+			current: orgState(orgUser("u1", "backend")),
+			desired: []*Team{syncTeam("backend")},
+			missing: MissingUsersRemove,
+			want:    []AffectedReview{{PRID: "pr-u1", TeamName: "backend", ReviewerID: "u1", AuthorID: "author", Reason: SyncRemoveUser}},
+		},
+		{
+			name:    "reviewer joins another team and stays active in the pr's team",
+			current: orgState(orgUser("u1", "backend")),
+			desired: []*Team{
+				syncTeam("backend", syncMember("u1", RoleMember, true)),
+				syncTeam("payments", syncMember("u1", RoleMember, true)),
+			},
+			want: []AffectedReview{},
+		},
+		{
+			name:    "reviewer promoted in the pr's team",
+			current: orgState(orgUser("u1", "backend")),
+			desired: []*Team{syncTeam("backend", syncMember("u1", RoleLead, true))},
+			want:    []AffectedReview{},
+		},
+		{
+			name:    "unchanged reviewer",
+			current: orgState(orgUser("u1", "backend")),
+			desired: []*Team{syncTeam("backend", syncMember("u1", RoleMember, true))},
+			want:    []AffectedReview{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.current.OpenReviews = reviews("u1")
+			got := PlanSync(tt.current, tt.desired, SyncOptions{MissingUsers: tt.missing}).AffectedReviews
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanSync().AffectedReviews = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanSyncIDIgnoresInputOrder(t *testing.T) {
+	current := func() *OrgState {
+		state := orgState(orgUser("u1", "backend"), orgUser("u2", "backend", "payments"), orgUser("u3", "payments"))
+		state.OpenReviews = []OpenReview{
+			{PRID: "pr-1", TeamName: "backend", AuthorID: "u2", ReviewerID: "u1"},
+			{PRID: "pr-2", TeamName: "payments", AuthorID: "u2", ReviewerID: "u3"},
+		}
+		return state
+	}
+	desired := []*Team{
+		syncTeam("backend", syncMember("u2", RoleLead, true), syncMember("u4", RoleMember, true)),
+		syncTeam("payments", syncMember("u1", RoleMember, true), syncMember("u2", RoleMember, true)),
+	}
+	opts := SyncOptions{MissingUsers: MissingUsersRemove}
+	want := PlanSync(current(), desired, opts).ID
+
+	// Same teams and members listed backwards; the only new user, u4, is in
+	// a single team, so their primary team does not depend on the order.
+	reversed := make([]*Team, len(desired))
+	for i, team := range desired {
+		members := slices.Clone(team.Members)
+		slices.Reverse(members)
+		reversed[len(desired)-1-i] = syncTeam(team.TeamName, members...)
+	}
+	state := current()
+	slices.Reverse(state.OpenReviews)
+
+	if got := PlanSync(state, reversed, opts).ID; got != want {
+		t.Errorf("PlanSync().ID on reversed input = %s, want %s", got, want)
+	}
+
+	desired[1].Members[0].Role = RoleLead
+	if got := PlanSync(current(), desired, opts).ID; got == want {
+		t.Error("PlanSync().ID did not change with the desired roles")
+	}
+}
@@ -1,6 +1,113 @@
 package model
 
+import "slices"
+
+const (
+	RoleMember string = "member"
+	RoleLead   string = "lead"
+	RoleAdmin  string = "admin"
+)
+
 type Team struct {
 	TeamName string
-	Members  []*User
+	Members  []*TeamMember
+}
+
+// TeamNode is a team together with its organisation units below it.
+type TeamNode struct {
+	TeamName   string
+	ParentTeam *string
+	Children   []*TeamNode
+}
+
+// Reviewer selection strategies of a team.
+const (
+	StrategyRandom     string = "random"
+	StrategyRoundRobin string = "round_robin"
+)
+
+// ReviewSettings controls how reviewers are picked in a team. With
+// PairingDiversity on, candidates who reviewed the author within the look-back
+// are passed over for those who did not: either the last LookbackDays days or
+// the author's last LookbackPRs PRs, whichever is set. It only applies to the
+// random strategy; round-robin follows its rotation.
+type ReviewSettings struct {
+	TeamName         string
+	Strategy         string
+	PairingDiversity bool
+	LookbackDays     *int
+	LookbackPRs      *int
+}
+
+type TeamMember struct {
+	UserID   string
+	Username string
+	Role     string
+	IsActive bool
+}
+
+// TeamMembership describes one team a user belongs to. IsActive is the
+// membership's own flag: a user deactivated in one team may still review for
+// the others.
+type TeamMembership struct {
+	TeamName string
+	Role     string
+	IsActive bool
+}
+
+// ImportResult summarises a bulk import. Created counts refer to rows that
+// did not exist before; the remaining teams and members were updated in place.
+type ImportResult struct {
+	DryRun       bool
+	Teams        int
+	Members      int
+	TeamsCreated int
+	UsersCreated int
+}
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleMember, RoleLead, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// CanManageTeam reports whether the role allows managing the team's members
+// and reassigning reviewers on the team's pull requests.
+func CanManageTeam(role string) bool {
+	return role == RoleLead || role == RoleAdmin
+}
+
+// MemberPatch is a change to a team's member list. Members in Replace (or the
+// current members when it is nil) plus Add, minus Remove, are kept.
+type MemberPatch struct {
+	Replace *[]string
+	Add     []string
+	Remove  []string
+}
+
+// Apply returns the member list that results from the patch, in order of
+// first appearance.
+func (p MemberPatch) Apply(current []string) []string {
+	base := current
+	if p.Replace != nil {
+		base = *p.Replace
+	}
+
+	removed := make(map[string]bool, len(p.Remove))
+	for _, id := range p.Remove {
+		removed[id] = true
+	}
+
+	seen := make(map[string]bool, len(base)+len(p.Add))
+	out := []string{}
+	for _, id := range append(slices.Clone(base), p.Add...) {
+		if seen[id] || removed[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
 }
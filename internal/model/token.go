@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	ScopePRWrite   string = "pr:write"
+	ScopeTeamAdmin string = "team:admin"
+	ScopeStatsRead string = "stats:read"
+)
+
+type APIToken struct {
+	ID   string
+	Name string
+	// UserID binds the token to a user whose team roles then limit what it
+	// may do. Tokens without a user are only limited by their scopes.
+	UserID    *string
+	Scopes    []string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+func (t *APIToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopePRWrite, ScopeTeamAdmin, ScopeStatsRead:
+		return true
+	}
+	return false
+}
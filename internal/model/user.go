@@ -1,10 +1,13 @@
 package model
 
+// User is a reviewer candidate. TeamName is the user's primary team; the full
+// list of teams the user belongs to is in Memberships.
 type User struct {
-	ID       string
-	Username string
-	TeamName string
-	IsActive bool
+	ID          string
+	Username    string
+	TeamName    string
+	IsActive    bool
+	Memberships []TeamMembership
 }
 
 func NewUser(id, username, team string, active bool) *User {
@@ -15,3 +18,21 @@ func NewUser(id, username, team string, active bool) *User {
 		IsActive: active,
 	}
 }
+
+type UpdateUserRequest struct {
+	ID       string
+	Username *string
+	TeamName *string
+}
+
+type ReviewReassignment struct {
+	PRID       string
+	ReplacedBy *string
+}
+
+// UserFilter narrows a user listing; nil fields match everything.
+type UserFilter struct {
+	UserID   *string
+	Username *string
+	IsActive *bool
+}
@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit describes a token bucket: it refills at Rate tokens per second up to
+// Burst tokens, and every request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left after this request.
+	Remaining int
+	// RetryAfter is how long a rejected client should wait for one token.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// NewResult derives the response details from the bucket level left after a
+// request was let through or rejected.
+func NewResult(allowed bool, tokens float64, limit Limit) Result {
+	res := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+	}
+	if limit.Rate > 0 {
+		res.ResetAfter = secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate)
+		if !allowed {
+			res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+		}
+	}
+	return res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// RefillTime is how long an empty bucket takes to fill up.
+func (l Limit) RefillTime() time.Duration {
+	if l.Rate <= 0 {
+		return 0
+	}
+	return secondsToDuration(float64(l.Burst) / l.Rate)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// refill is the RefillTime of the limit the bucket was last used with.
+	refill time.Duration
+}
+
+// MemoryLimiter keeps buckets in process memory. Each replica counts on its
+// own; use the Postgres limiter to share limits between replicas.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	idleTTL   time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLimiter(idleTTL time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		idleTTL: idleTTL,
+		now:     time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.last = now
+	b.refill = limit.RefillTime()
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return NewResult(allowed, b.tokens, limit), nil
+}
+
+// sweep drops buckets that have not been touched for idleTTL and have had
+// time to refill completely. Such a bucket is full, so forgetting it changes
+// nothing; a bucket of a slow limit is kept until then even past idleTTL.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if l.idleTTL <= 0 || now.Sub(l.lastSweep) < l.idleTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if idle := now.Sub(b.last); idle > l.idleTTL && idle >= b.refill {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
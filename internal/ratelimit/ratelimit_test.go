@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// step is one request made after advancing the clock by wait.
+type step struct {
+	wait time.Duration
+	key  string
+	want Result
+}
+
+func TestMemoryLimiterAllow(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 3}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst then reject",
+			steps: []step{
+				{want: Result{Allowed: true, Remaining: 2, ResetAfter: 500 * time.Millisecond}},
+				{want: Result{Allowed: true, Remaining: 1, ResetAfter: time.Second}},
+				{want: Result{Allowed: true, Remaining: 0, ResetAfter: 1500 * time.Millisecond}},
+				{want: Result{Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond, ResetAfter: 1500 * time.Millisecond}},
+			},
+		},
+		{
+			name: "refill at rate",
+			steps: []step{
+				{want: Result{Allowed: true, Remaining: 2, ResetAfter: 500 * time.Millisecond}},
+				{want: Result{Allowed: true, Remaining: 1, ResetAfter: time.Second}},
+				{want: Result{Allowed: true, Remaining: 0, ResetAfter: 1500 * time.Millisecond}},
+				// Half a second brings back one token, which is taken at once.
+				{wait: 500 * time.Millisecond, want: Result{Allowed: true, Remaining: 0, ResetAfter: 1500 * time.Millisecond}},
+				// A quarter of a second is half a token: not enough.
+				{
+					wait: 250 * time.Millisecond,
+					want: Result{Allowed: false, Remaining: 0, RetryAfter: 250 * time.Millisecond, ResetAfter: 1250 * time.Millisecond},
+				},
+			},
+		},
+		{
+			name: "refill stops at burst",
+			steps: []step{
+				{want: Result{Allowed: true, Remaining: 2, ResetAfter: 500 * time.Millisecond}},
+				{wait: time.Hour, want: Result{Allowed: true, Remaining: 2, ResetAfter: 500 * time.Millisecond}},
+			},
+		},
+		{
+			name: "keys have separate buckets",
+			steps: []step{
+				{key: "a", want: Result{Allowed: true, Remaining: 2, ResetAfter: 500 * time.Millisecond}},
+				{key: "a", want: Result{Allowed: true, Remaining: 1, ResetAfter: time.Second}},
+				{key: "b", want: Result{Allowed: true, Remaining: 2, ResetAfter: 500 * time.Millisecond}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			l := NewMemoryLimiter(10 * time.Minute)
+			l.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				now = now.Add(s.wait)
+				key := s.key
+				if key == "" {
+					key = "client"
+				}
+
+				got, err := l.Allow(context.Background(), key, limit)
+				if err != nil {
+					t.Fatalf("step %d: Allow() error = %v", i, err)
+				}
+				if got != s.want {
+					t.Errorf("step %d: Allow() = %+v, want %+v", i, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	const idleTTL = 10 * time.Minute
+
+	tests := []struct {
+		name  string
+		limit Limit
+		// idle is how long the bucket goes untouched before another key
+		// triggers the sweep.
+		idle time.Duration
+		kept bool
+	}{
+		{name: "touched within idle ttl", limit: Limit{Rate: 1, Burst: 10}, idle: 5 * time.Minute, kept: true},
+		{name: "idle and full", limit: Limit{Rate: 1, Burst: 10}, idle: 11 * time.Minute},
+		{
+			// One token a minute with a burst of 30 takes half an hour to
+			// refill, longer than idle ttl.
+			name:  "idle but still refilling",
+			limit: Limit{Rate: 1.0 / 60, Burst: 30},
+			idle:  11 * time.Minute,
+			kept:  true,
+		},
+		{name: "idle past the refill time", limit: Limit{Rate: 1.0 / 60, Burst: 30}, idle: 31 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			l := NewMemoryLimiter(idleTTL)
+			l.now = func() time.Time { return now }
+			ctx := context.Background()
+
+			// Drain the bucket so dropping it too early would be visible.
+			for range tt.limit.Burst {
+				if _, err := l.Allow(ctx, "client", tt.limit); err != nil {
+					t.Fatalf("Allow() error = %v", err)
+				}
+			}
+
+			now = now.Add(tt.idle)
+			if _, err := l.Allow(ctx, "other", tt.limit); err != nil {
+				t.Fatalf("Allow() error = %v", err)
+			}
+
+			if _, kept := l.buckets["client"]; kept != tt.kept {
+				t.Errorf("bucket kept = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
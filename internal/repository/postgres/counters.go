@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// counterKey addresses a row of team_counters, or of reviewer_counters when
+// reviewerID is set. PRs are counted under their team, or their author's
+// primary team if the PR's team is gone, as /statistics attributes them.
+type counterKey struct {
+	reviewerID string
+	teamName   string
+}
+
+type counterValue struct {
+	open   int
+	merged int
+}
+
+// counterDelta collects changes to the statistics counters within a
+// transaction. Mutations count the PRs they touch with -1 before changing
+// them and with +1 afterwards, then apply the difference.
+type counterDelta map[counterKey]counterValue
+
+// count adds sign times the current contribution of the PRs: the PR itself to
+// its team and one review to each reviewer. The PRs are locked so that their
+// state cannot change before the transaction ends.
+func (d counterDelta) count(ctx context.Context, q querier, prIDs []string, sign int) error {
+	if len(prIDs) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT COALESCE(pr.team_name, a.team_name), pr.status,
+		       ARRAY(SELECT reviewer_id FROM pr_reviewers prr WHERE prr.pull_request_id = pr.pull_request_id)
+		FROM pull_requests pr
+		JOIN users a ON a.user_id = pr.author_id
+		WHERE pr.pull_request_id = ANY($1)
+		ORDER BY pr.pull_request_id
+		FOR UPDATE OF pr
+	`, prIDs)
+	if err != nil {
+		return fmt.Errorf("count prs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			teamName, status string
+			reviewerIDs      []string
+		)
+		if err := rows.Scan(&teamName, &status, &reviewerIDs); err != nil {
+			return fmt.Errorf("scan counted pr: %w", err)
+		}
+		d.add(counterKey{teamName: teamName}, status, sign)
+		for _, id := range reviewerIDs {
+			d.add(counterKey{reviewerID: id, teamName: teamName}, status, sign)
+		}
+	}
+	return rows.Err()
+}
+
+func (d counterDelta) add(key counterKey, status string, n int) {
+	v := d[key]
+	if status == "MERGED" {
+		v.merged += n
+	} else {
+		v.open += n
+	}
+	d[key] = v
+}
+
+// apply writes the non-zero changes in key order. Both tables are locked
+// upfront, in the order RebuildCounters takes them, so that concurrent
+// mutations and rebuilds cannot deadlock on the counters.
+func (d counterDelta) apply(ctx context.Context, q querier) error {
+	keys := make([]counterKey, 0, len(d))
+	for k, v := range d {
+		if v != (counterValue{}) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	slices.SortFunc(keys, func(a, b counterKey) int {
+		return cmp.Or(cmp.Compare(a.reviewerID, b.reviewerID), cmp.Compare(a.teamName, b.teamName))
+	})
+
+	if _, err := q.Exec(ctx, `LOCK TABLE team_counters, reviewer_counters IN ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("lock counters: %w", err)
+	}
+
+	var teams, reviewerTeams, reviewers []string
+	var teamOpen, teamMerged, reviewerOpen, reviewerMerged []int
+	for _, k := range keys {
+		v := d[k]
+		if k.reviewerID == "" {
+			teams = append(teams, k.teamName)
+			teamOpen = append(teamOpen, v.open)
+			teamMerged = append(teamMerged, v.merged)
+			continue
+		}
+		reviewers = append(reviewers, k.reviewerID)
+		reviewerTeams = append(reviewerTeams, k.teamName)
+		reviewerOpen = append(reviewerOpen, v.open)
+		reviewerMerged = append(reviewerMerged, v.merged)
+	}
+
+	if len(teams) > 0 {
+		_, err := q.Exec(ctx, `
+			INSERT INTO team_counters (team_name, open_prs, merged_prs)
+			SELECT * FROM unnest($1::text[], $2::int[], $3::int[])
+			ON CONFLICT (team_name) DO UPDATE
+			SET open_prs = team_counters.open_prs + EXCLUDED.open_prs,
+			    merged_prs = team_counters.merged_prs + EXCLUDED.merged_prs
+		`, teams, teamOpen, teamMerged)
+		if err != nil {
+			return fmt.Errorf("update team counters: %w", err)
+		}
+	}
+
+	if len(reviewers) > 0 {
+		_, err := q.Exec(ctx, `
+			INSERT INTO reviewer_counters (reviewer_id, team_name, open_reviews, merged_reviews)
+			SELECT * FROM unnest($1::text[], $2::text[], $3::int[], $4::int[])
+			ON CONFLICT (reviewer_id, team_name) DO UPDATE
+			SET open_reviews = reviewer_counters.open_reviews + EXCLUDED.open_reviews,
+			    merged_reviews = reviewer_counters.merged_reviews + EXCLUDED.merged_reviews
+		`, reviewers, reviewerTeams, reviewerOpen, reviewerMerged)
+		if err != nil {
+			return fmt.Errorf("update reviewer counters: %w", err)
+		}
+	}
+
+	return nil
+}
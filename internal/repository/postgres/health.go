@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HealthRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewHealthRepository(pool *pgxpool.Pool) *HealthRepository {
+	return &HealthRepository{pool: pool}
+}
+
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
+func (r *HealthRepository) PoolUsage() (int32, int32) {
+	stat := r.pool.Stat()
+	return stat.AcquiredConns(), stat.MaxConns()
+}
+
+func (r *HealthRepository) MigrationVersion(ctx context.Context) (int64, bool, error) {
+	const op = "HealthRepository.MigrationVersion"
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err := r.pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return version, dirty, nil
+}
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"avito-pr-service/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// snapshotChunkDays bounds the days written per transaction, so that the
+// first backfill over a long history does not hold one huge transaction.
+const snapshotChunkDays = 31
+
+// writeSnapshotDays computes the snapshots of the days $1 to $2 from the PRs
+// and their current reviewers, in UTC. A PR counts as open at the end of
+// every day from its creation until the day before its merge. Reviewers are
+// counted from their assignment, so reviewers replaced before the snapshots
+// were first taken do not show up in the backfilled days.
+const writeSnapshotDays = `
+        WITH days AS (
+            SELECT d::date AS day
+            FROM generate_series($1::date::timestamp, $2::date::timestamp, interval '1 day') d
+        ),
+        prs AS (
+            SELECT pr.pull_request_id,
+                   COALESCE(pr.team_name, a.team_name) AS team_name,
+                   (pr.created_at AT TIME ZONE 'UTC')::date AS created_day,
+                   (pr.merged_at AT TIME ZONE 'UTC')::date AS merged_day
+            FROM pull_requests pr
+            JOIN users a ON a.user_id = pr.author_id
+            WHERE pr.created_at < ($2::date + 1)::timestamp AT TIME ZONE 'UTC'
+              AND (pr.merged_at IS NULL OR pr.merged_at >= $1::date::timestamp AT TIME ZONE 'UTC')
+        ),
+        teams AS (
+            SELECT d.day, p.team_name, '' AS reviewer_id,
+                   COUNT(*) FILTER (WHERE p.merged_day IS NULL OR p.merged_day > d.day) AS open_count,
+                   COUNT(*) FILTER (WHERE p.created_day = d.day) AS added_count,
+                   COUNT(*) FILTER (WHERE p.merged_day = d.day) AS merged_count
+            FROM days d
+            JOIN prs p ON p.created_day <= d.day AND (p.merged_day IS NULL OR p.merged_day >= d.day)
+            GROUP BY d.day, p.team_name
+        ),
+        reviewers AS (
+            SELECT d.day, p.team_name, prr.reviewer_id,
+                   COUNT(*) FILTER (WHERE p.merged_day IS NULL OR p.merged_day > d.day) AS open_count,
+                   COUNT(*) FILTER (WHERE (prr.assigned_at AT TIME ZONE 'UTC')::date = d.day) AS added_count,
+                   COUNT(*) FILTER (WHERE p.merged_day = d.day) AS merged_count
+            FROM days d
+            JOIN prs p ON p.merged_day IS NULL OR p.merged_day >= d.day
+            JOIN pr_reviewers prr ON prr.pull_request_id = p.pull_request_id
+                                 AND (prr.assigned_at AT TIME ZONE 'UTC')::date <= d.day
+            GROUP BY d.day, p.team_name, prr.reviewer_id
+        )
+        INSERT INTO stats_snapshots (snapshot_date, team_name, reviewer_id, open_count, added_count, merged_count)
+        SELECT * FROM teams
+        UNION ALL
+        SELECT * FROM reviewers
+        ON CONFLICT (snapshot_date, team_name, reviewer_id) DO UPDATE
+        SET open_count = EXCLUDED.open_count,
+            added_count = EXCLUDED.added_count,
+            merged_count = EXCLUDED.merged_count`
+
+// WriteSnapshots writes the daily snapshots missing up to through, a UTC
+// date, and returns the number of days written. Written days are recorded in
+// stats_snapshot_runs, since days without any PR leave no snapshot rows.
+// Without any runs it starts from the day of the first PR, which backfills
+// the history. Replicas running it at once take turns on an advisory lock
+// and skip the days already written.
+func (r *StatisticsRepository) WriteSnapshots(ctx context.Context, through time.Time) (int, error) {
+	const op = "StatisticsRepository.WriteSnapshots"
+
+	written := 0
+	for {
+		n, err := r.writeSnapshotChunk(ctx, through)
+		if err != nil {
+			return written, fmt.Errorf("%s: %w", op, err)
+		}
+		if n == 0 {
+			return written, nil
+		}
+		written += n
+	}
+}
+
+func (r *StatisticsRepository) writeSnapshotChunk(ctx context.Context, through time.Time) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('stats_snapshots'))`); err != nil {
+		return 0, fmt.Errorf("lock: %w", err)
+	}
+
+	var from *time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(
+			(SELECT MAX(snapshot_date) + 1 FROM stats_snapshot_runs),
+			(SELECT MIN(created_at AT TIME ZONE 'UTC')::date FROM pull_requests)
+		)
+	`).Scan(&from)
+	if err != nil {
+		return 0, fmt.Errorf("select first missing day: %w", err)
+	}
+	if from == nil || from.After(through) {
+		return 0, nil
+	}
+
+	to := from.AddDate(0, 0, snapshotChunkDays-1)
+	if to.After(through) {
+		to = through
+	}
+	if _, err := tx.Exec(ctx, writeSnapshotDays, *from, to); err != nil {
+		return 0, fmt.Errorf("write snapshots from %s to %s: %w", from.Format(time.DateOnly), to.Format(time.DateOnly), err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO stats_snapshot_runs (snapshot_date)
+		SELECT d::date FROM generate_series($1::date::timestamp, $2::date::timestamp, interval '1 day') d
+	`, *from, to)
+	if err != nil {
+		return 0, fmt.Errorf("record snapshot runs: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return int(to.Sub(*from).Hours()/24) + 1, nil
+}
+
+// historySeries groups the daily snapshots of one kind, team rows or
+// reviewer rows, by key and period. It takes from, to, granularity and team
+// as $1-$4 and yields a point for every period of every key, zeros included,
+// so that the series chart without gaps.
+func historySeries(key, rows string) string {
+	return `
+        WITH periods AS (
+            SELECT p::date AS period_start,
+                   GREATEST(p::date, $1::date) AS first_day,
+                   LEAST((p + ('1 ' || $3::text)::interval)::date - 1, $2::date) AS last_day
+            FROM generate_series(date_trunc($3, $1::date::timestamp), $2::date::timestamp, ('1 ' || $3)::interval) p
+        ),
+        daily AS (
+            SELECT ` + key + ` AS key, snapshot_date,
+                   SUM(open_count) AS open_count,
+                   SUM(added_count) AS added_count,
+                   SUM(merged_count) AS merged_count
+            FROM stats_snapshots
+            WHERE ` + rows + `
+              AND snapshot_date BETWEEN $1 AND $2
+              AND ($4::text IS NULL OR team_name = $4)
+            GROUP BY 1, 2
+        ),
+        keys AS (
+            SELECT DISTINCT key FROM daily
+        )
+        SELECT
+            k.key,
+            pe.period_start,
+            COALESCE(SUM(d.open_count) FILTER (WHERE d.snapshot_date = pe.last_day), 0)::bigint,
+            COALESCE(SUM(d.open_count), 0)::float8 / (pe.last_day - pe.first_day + 1),
+            COALESCE(SUM(d.added_count), 0)::bigint,
+            COALESCE(SUM(d.merged_count), 0)::bigint
+        FROM keys k
+        CROSS JOIN periods pe
+        LEFT JOIN daily d ON d.key = k.key AND d.snapshot_date BETWEEN pe.first_day AND pe.last_day
+        GROUP BY k.key, pe.period_start, pe.first_day, pe.last_day
+        ORDER BY k.key, pe.period_start
+    `
+}
+
+// GetStatisticsHistory returns per-team throughput and per-reviewer load as
+// time series built from the daily snapshots. With a team set, reviewers are
+// measured on that team's PRs only.
+func (r *StatisticsRepository) GetStatisticsHistory(ctx context.Context, q model.HistoryQuery) (*model.StatisticsHistory, error) {
+	const op = "StatisticsRepository.GetStatisticsHistory"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if q.TeamName != nil {
+		if err := teamExists(ctx, tx, *q.TeamName); err != nil {
+			return nil, err
+		}
+	}
+
+	history := &model.StatisticsHistory{
+		TeamName:    q.TeamName,
+		Granularity: q.Granularity,
+		From:        q.From.Format(time.DateOnly),
+		To:          q.To.Format(time.DateOnly),
+		Teams:       []model.TeamHistory{},
+		Reviewers:   []model.ReviewerHistory{},
+	}
+
+	teamRows, err := tx.Query(ctx, historySeries("team_name", "reviewer_id = ''"), q.From, q.To, q.Granularity, q.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query team history: %w", op, err)
+	}
+	defer teamRows.Close()
+
+	for teamRows.Next() {
+		var (
+			teamName    string
+			periodStart time.Time
+			p           model.TeamHistoryPoint
+		)
+		if err := teamRows.Scan(&teamName, &periodStart, &p.OpenPRs, &p.OpenPRsAvg, &p.CreatedPRs, &p.MergedPRs); err != nil {
+			return nil, fmt.Errorf("%s: scan team history: %w", op, err)
+		}
+		p.PeriodStart = periodStart.Format(time.DateOnly)
+
+		if n := len(history.Teams); n == 0 || history.Teams[n-1].TeamName != teamName {
+			history.Teams = append(history.Teams, model.TeamHistory{TeamName: teamName})
+		}
+		last := &history.Teams[len(history.Teams)-1]
+		last.Points = append(last.Points, p)
+	}
+	if err := teamRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	reviewerRows, err := tx.Query(ctx, historySeries("reviewer_id", "reviewer_id <> ''"), q.From, q.To, q.Granularity, q.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query reviewer history: %w", op, err)
+	}
+	defer reviewerRows.Close()
+
+	for reviewerRows.Next() {
+		var (
+			userID      string
+			periodStart time.Time
+			p           model.ReviewerHistoryPoint
+		)
+		if err := reviewerRows.Scan(&userID, &periodStart, &p.OpenReviews, &p.OpenReviewsAvg, &p.AssignedReviews, &p.MergedReviews); err != nil {
+			return nil, fmt.Errorf("%s: scan reviewer history: %w", op, err)
+		}
+		p.PeriodStart = periodStart.Format(time.DateOnly)
+
+		if n := len(history.Reviewers); n == 0 || history.Reviewers[n-1].UserID != userID {
+			history.Reviewers = append(history.Reviewers, model.ReviewerHistory{UserID: userID})
+		}
+		last := &history.Reviewers[len(history.Reviewers)-1]
+		last.Points = append(last.Points, p)
+	}
+	if err := reviewerRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	return history, nil
+}
@@ -2,13 +2,17 @@ package postgres
 
 import (
 	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
 	"avito-pr-service/internal/model"
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/exaring/otelpgx"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,7 +23,29 @@ type Storage struct {
 func (s *Storage) Pool() *pgxpool.Pool { return s.pool }
 func (s *Storage) Close()              { s.pool.Close() }
 
-func New(ctx context.Context, connStr string) (*Storage, error) {
+// SQLSTATE codes the repositories translate into domain errors.
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
+// querier is the subset of pgx shared by *pgxpool.Pool and pgx.Tx, so that
+// helpers can run either inside or outside a transaction.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+}
+
+func New(ctx context.Context, connStr string, poolCfg PoolConfig) (*Storage, error) {
 	const op = "storage.postgres.New"
 
 	config, err := pgxpool.ParseConfig(connStr)
@@ -27,10 +53,14 @@ func New(ctx context.Context, connStr string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	config.MaxConns = 25
-	config.MinConns = 2
-	config.MaxConnLifetime = time.Hour
-	config.HealthCheckPeriod = time.Minute
+	config.MaxConns = poolCfg.MaxConns
+	config.MinConns = poolCfg.MinConns
+	config.MaxConnLifetime = poolCfg.MaxConnLifetime
+	config.MaxConnIdleTime = poolCfg.MaxConnIdleTime
+	config.HealthCheckPeriod = poolCfg.HealthCheckPeriod
+	// Every statement becomes a child span of the request; without a
+	// configured tracer provider this is a no-op.
+	config.ConnConfig.Tracer = otelpgx.NewTracer(otelpgx.WithTrimSQLInSpanName())
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -53,7 +83,7 @@ func NewPRRepository(pool *pgxpool.Pool) *PRRepository {
 	return &PRRepository{pool: pool}
 }
 
-func (r *PRRepository) CreatePR(ctx context.Context, prID, pullRequestName, authorID string) (*model.PullRequest, error) {
+func (r *PRRepository) CreatePR(ctx context.Context, prID, pullRequestName, authorID, teamName string) (*model.PullRequest, error) {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return nil, err
@@ -69,8 +99,8 @@ func (r *PRRepository) CreatePR(ctx context.Context, prID, pullRequestName, auth
 		return nil, int_errors.ErrPRExists
 	}
 
-	var teamName string
-	err = tx.QueryRow(ctx, "SELECT team_name FROM users WHERE user_id = $1", authorID).Scan(&teamName)
+	var primaryTeam string
+	err = tx.QueryRow(ctx, "SELECT team_name FROM users WHERE user_id = $1", authorID).Scan(&primaryTeam)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, int_errors.ErrUserNotFound
@@ -78,37 +108,39 @@ func (r *PRRepository) CreatePR(ctx context.Context, prID, pullRequestName, auth
 		return nil, err
 	}
 
+	if teamName == "" {
+		teamName = primaryTeam
+	} else if teamName != primaryTeam {
+		if err := teamExists(ctx, tx, teamName); err != nil {
+			return nil, err
+		}
+
+		var isMember bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS(SELECT 1 FROM team_memberships WHERE user_id = $1 AND team_name = $2)
+		`, authorID, teamName).Scan(&isMember)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, int_errors.ErrAuthorNotInTeam
+		}
+	}
+
 	createdAt := time.Now()
 	_, err = tx.Exec(ctx, `
-		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at)
-		VALUES ($1, $2, $3, 'OPEN', $4)
-	`, prID, pullRequestName, authorID, createdAt)
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, team_name, status, created_at)
+		VALUES ($1, $2, $3, $4, 'OPEN', $5)
+	`, prID, pullRequestName, authorID, teamName, createdAt)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := tx.Query(ctx, `
-		SELECT user_id FROM users 
-		WHERE team_name = $1 
-		  AND is_active = true 
-		  AND user_id != $2
-		ORDER BY RANDOM() 
-		LIMIT 2
-	`, teamName, authorID)
+	reviewers, err := selectTeamReviewers(ctx, tx, teamName, prID, authorID, []string{authorID}, 2)
 	if err != nil {
 		return nil, err
 	}
 
-	var reviewers []string
-	for rows.Next() {
-		var rID string
-		if err := rows.Scan(&rID); err != nil {
-			return nil, err
-		}
-		reviewers = append(reviewers, rID)
-	}
-	rows.Close()
-
 	for _, revID := range reviewers {
 		_, err := tx.Exec(ctx, `INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`, prID, revID)
 		if err != nil {
@@ -116,6 +148,14 @@ func (r *PRRepository) CreatePR(ctx context.Context, prID, pullRequestName, auth
 		}
 	}
 
+	counters := counterDelta{}
+	if err := counters.count(ctx, tx, []string{prID}, 1); err != nil {
+		return nil, err
+	}
+	if err := counters.apply(ctx, tx); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -124,39 +164,97 @@ func (r *PRRepository) CreatePR(ctx context.Context, prID, pullRequestName, auth
 		PRID:              prID,
 		PRName:            pullRequestName,
 		AuthorID:          authorID,
+		TeamName:          teamName,
 		Status:            "OPEN",
 		AssignedReviewers: reviewers,
 		CreatedAt:         createdAt,
 	}, nil
 }
 
-func (r *PRRepository) MergePR(ctx context.Context, prID string) (*model.PullRequest, error) {
-	row := r.pool.QueryRow(ctx, `
+func (r *PRRepository) GetPR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	const op = "PRRepository.GetPR"
+
+	var pr model.PullRequest
+	err := r.pool.QueryRow(ctx, `
+		SELECT pull_request_id, pull_request_name, author_id, COALESCE(team_name, ''), status, created_at, merged_at
+		FROM pull_requests
+		WHERE pull_request_id = $1
+	`, prID).Scan(&pr.PRID, &pr.PRName, &pr.AuthorID, &pr.TeamName, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrPRNotFound
+		}
+		return nil, fmt.Errorf("%s: select pr: %w", op, err)
+	}
+
+	pr.AssignedReviewers, err = selectReviewerIDs(ctx, r.pool, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: get reviewers: %w", op, err)
+	}
+
+	return &pr, nil
+}
+
+func (r *PRRepository) MergePR(ctx context.Context, prID string) (*model.PullRequest, bool, error) {
+	const op = "PRRepository.MergePR"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	counters := counterDelta{}
+	if err := counters.count(ctx, tx, []string{prID}, -1); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// merged_at only equals NOW() when this statement set it: NOW() is the
+	// transaction start time, so an earlier merge always compares older.
+	row := tx.QueryRow(ctx, `
 		UPDATE pull_requests
 		SET status = 'MERGED',
 		    merged_at = COALESCE(merged_at, NOW()) 
 		WHERE pull_request_id = $1
-		RETURNING pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		RETURNING pull_request_id, pull_request_name, author_id, COALESCE(team_name, ''), status, created_at, merged_at,
+		          merged_at = NOW()
 	`, prID)
 
 	var pr model.PullRequest
-	err := row.Scan(&pr.PRID, &pr.PRName, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+	var merged bool
+	err = row.Scan(&pr.PRID, &pr.PRName, &pr.AuthorID, &pr.TeamName, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &merged)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, int_errors.ErrPRNotFound
+			return nil, false, int_errors.ErrPRNotFound
 		}
-		return nil, err
+		return nil, false, err
 	}
 
-	pr.AssignedReviewers, err = r.getReviewers(ctx, prID)
+	pr.AssignedReviewers, err = selectReviewerIDs(ctx, tx, prID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return &pr, nil
+	if merged {
+		if err := counters.count(ctx, tx, []string{prID}, 1); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := counters.apply(ctx, tx); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return &pr, merged, nil
 }
 
-func (r *PRRepository) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*model.PullRequest, string, error) {
+// ReassignReviewer replaces oldUserID on the PR with another active member of
+// the PR's team. With escalate set, an empty team is not a dead end: the
+// candidate is taken from the nearest parent unit that has one.
+func (r *PRRepository) ReassignReviewer(ctx context.Context, prID, oldUserID string, escalate bool) (*model.PullRequest, string, error) {
 	const op = "PRRepository.ReassignReviewer"
 
 	tx, err := r.pool.Begin(ctx)
@@ -166,9 +264,10 @@ func (r *PRRepository) ReassignReviewer(ctx context.Context, prID, oldUserID str
 	defer tx.Rollback(ctx)
 
 	var status, authorID, prName string
+	var prTeam *string
 	var createdAt time.Time
 	var mergedAt *time.Time
-	err = tx.QueryRow(ctx, `SELECT status, author_id, pull_request_name, created_at, merged_at FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`, prID).Scan(&status, &authorID, &prName, &createdAt, &mergedAt)
+	err = tx.QueryRow(ctx, `SELECT status, author_id, pull_request_name, team_name, created_at, merged_at FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`, prID).Scan(&status, &authorID, &prName, &prTeam, &createdAt, &mergedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, "", int_errors.ErrPRNotFound
@@ -180,6 +279,11 @@ func (r *PRRepository) ReassignReviewer(ctx context.Context, prID, oldUserID str
 		return nil, "", int_errors.ErrPRMerged
 	}
 
+	counters := counterDelta{}
+	if err := counters.count(ctx, tx, []string{prID}, -1); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	res, err := tx.Exec(ctx, "DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2", prID, oldUserID)
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: delete old reviewer: %w", op, err)
@@ -188,16 +292,21 @@ func (r *PRRepository) ReassignReviewer(ctx context.Context, prID, oldUserID str
 		return nil, "", int_errors.ErrReviewerNotAssigned
 	}
 
+	// PRs whose team has been deleted fall back to the old reviewer's primary team.
 	var teamName string
-	err = tx.QueryRow(ctx, "SELECT team_name FROM users WHERE user_id = $1", oldUserID).Scan(&teamName)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, "", int_errors.ErrUserNotFound
+	if prTeam != nil {
+		teamName = *prTeam
+	} else {
+		err = tx.QueryRow(ctx, "SELECT team_name FROM users WHERE user_id = $1", oldUserID).Scan(&teamName)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, "", int_errors.ErrUserNotFound
+			}
+			return nil, "", fmt.Errorf("%s: select team name: %w", op, err)
 		}
-		return nil, "", fmt.Errorf("%s: select team name: %w", op, err)
 	}
 
-	currentReviewers, err := r.getReviewersTx(ctx, tx, prID)
+	currentReviewers, err := selectReviewerIDs(ctx, tx, prID)
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: get current reviewers: %w", op, err)
 	}
@@ -205,28 +314,44 @@ func (r *PRRepository) ReassignReviewer(ctx context.Context, prID, oldUserID str
 	excludeIDs := []string{authorID, oldUserID}
 	excludeIDs = append(excludeIDs, currentReviewers...)
 
-	var newReviewerID string
-	err = tx.QueryRow(ctx, `
-		SELECT user_id FROM users
-		WHERE team_name = $1
-		  AND is_active = true
-		  AND user_id != ALL($2)
-		ORDER BY RANDOM()
-		LIMIT 1
-	`, teamName, excludeIDs).Scan(&newReviewerID)
-
+	candidates, err := selectTeamReviewers(ctx, tx, teamName, prID, authorID, excludeIDs, 1)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, "", int_errors.ErrNoReplacementCandidate
-		}
 		return nil, "", fmt.Errorf("%s: select replacement: %w", op, err)
 	}
+	if len(candidates) == 0 && escalate {
+		candidates, err = selectAncestorCandidates(ctx, tx, teamName, excludeIDs, 1)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: select escalated replacement: %w", op, err)
+		}
+		if len(candidates) > 0 {
+			logger.FromContext(ctx).Info("reviewer taken from a parent unit",
+				slog.String("pr_id", prID),
+				slog.String("team", teamName),
+				slog.String("reviewer_id", candidates[0]),
+			)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, "", int_errors.ErrNoReplacementCandidate
+	}
+	newReviewerID := candidates[0]
 
 	_, err = tx.Exec(ctx, "INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)", prID, newReviewerID)
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: insert new reviewer: %w", op, err)
 	}
 
+	if err := logReassignment(ctx, tx, prID, oldUserID, &newReviewerID); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := counters.count(ctx, tx, []string{prID}, 1); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err := counters.apply(ctx, tx); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, "", fmt.Errorf("%s: commit: %w", op, err)
 	}
@@ -242,12 +367,41 @@ func (r *PRRepository) ReassignReviewer(ctx context.Context, prID, oldUserID str
 		CreatedAt:         createdAt,
 		MergedAt:          mergedAt,
 	}
+	if prTeam != nil {
+		pr.TeamName = *prTeam
+	}
 
 	return pr, newReviewerID, nil
 }
 
-func (r *PRRepository) getReviewers(ctx context.Context, prID string) ([]string, error) {
-	rows, err := r.pool.Query(ctx, "SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id = $1", prID)
+// logReassignment records that oldReviewerID was taken off the PR, in favour
+// of newReviewerID or of no one. The log feeds /statistics/timing.
+func logReassignment(ctx context.Context, q querier, prID, oldReviewerID string, newReviewerID *string) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO pr_reassignments (pull_request_id, old_reviewer_id, new_reviewer_id)
+		VALUES ($1, $2, $3)
+	`, prID, oldReviewerID, newReviewerID)
+	if err != nil {
+		return fmt.Errorf("log reassignment of %s: %w", prID, err)
+	}
+	return nil
+}
+
+// selectCandidates picks up to limit random reviewers among the members of
+// teamName who are active both globally and within the team, skipping the
+// excluded user IDs.
+func selectCandidates(ctx context.Context, q querier, teamName string, excludeIDs []string, limit int) ([]string, error) {
+	rows, err := q.Query(ctx, `
+		SELECT m.user_id
+		FROM team_memberships m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.team_name = $1
+		  AND m.is_active = true
+		  AND u.is_active = true
+		  AND m.user_id != ALL($2)
+		ORDER BY RANDOM()
+		LIMIT $3
+	`, teamName, excludeIDs, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -256,14 +410,134 @@ func (r *PRRepository) getReviewers(ctx context.Context, prID string) ([]string,
 	var ids []string
 	for rows.Next() {
 		var id string
-		rows.Scan(&id)
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
 		ids = append(ids, id)
 	}
-	return ids, nil
+	return ids, rows.Err()
 }
 
-func (r *PRRepository) getReviewersTx(ctx context.Context, tx pgx.Tx, prID string) ([]string, error) {
-	rows, err := tx.Query(ctx, "SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id = $1", prID)
+// selectTeamReviewers picks up to limit reviewers of the author's PR prID
+// from teamName, following the team's review settings. With the random
+// strategy and no pairing diversity it is selectCandidates.
+func selectTeamReviewers(ctx context.Context, q querier, teamName, prID, authorID string, excludeIDs []string, limit int) ([]string, error) {
+	var (
+		strategy     = model.StrategyRandom
+		diversity    bool
+		lookbackDays *int
+		lookbackPRs  *int
+	)
+	err := q.QueryRow(ctx, `
+		SELECT review_strategy, pairing_diversity, diversity_lookback_days, diversity_lookback_prs
+		FROM teams
+		WHERE team_name = $1
+	`, teamName).Scan(&strategy, &diversity, &lookbackDays, &lookbackPRs)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("select review settings: %w", err)
+	}
+
+	switch {
+	case strategy == model.StrategyRoundRobin:
+		return selectRoundRobinCandidates(ctx, q, teamName, excludeIDs, limit)
+	case diversity:
+		return selectDiverseCandidates(ctx, q, teamName, prID, authorID, excludeIDs, limit, lookbackDays, lookbackPRs)
+	default:
+		return selectCandidates(ctx, q, teamName, excludeIDs, limit)
+	}
+}
+
+// selectDiverseCandidates counts, for every candidate of selectCandidates, the
+// reviews of the author's other PRs they were assigned within the look-back,
+// and leaves the choice to model.RankByDiversity.
+func selectDiverseCandidates(ctx context.Context, q querier, teamName, prID, authorID string, excludeIDs []string, limit int, lookbackDays, lookbackPRs *int) ([]string, error) {
+	rows, err := q.Query(ctx, `
+		WITH recent AS (
+			SELECT pull_request_id
+			FROM pull_requests
+			WHERE author_id = $2 AND pull_request_id <> $3
+			ORDER BY created_at DESC
+			LIMIT $5
+		),
+		reviewed AS (
+			SELECT prr.reviewer_id, COUNT(*) AS n
+			FROM pr_reviewers prr
+			JOIN recent USING (pull_request_id)
+			WHERE $4::int IS NULL OR prr.assigned_at >= NOW() - make_interval(days => $4::int)
+			GROUP BY prr.reviewer_id
+		)
+		SELECT m.user_id, COALESCE(rv.n, 0)
+		FROM team_memberships m
+		JOIN users u ON u.user_id = m.user_id
+		LEFT JOIN reviewed rv ON rv.reviewer_id = m.user_id
+		WHERE m.team_name = $1
+		  AND m.is_active = true
+		  AND u.is_active = true
+		  AND m.user_id != ALL($6)
+	`, teamName, authorID, prID, lookbackDays, lookbackPRs, excludeIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.ReviewCandidate
+	for rows.Next() {
+		var c model.ReviewCandidate
+		if err := rows.Scan(&c.UserID, &c.RecentReviews); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return model.RankByDiversity(prID, candidates, limit), nil
+}
+
+// selectAncestorCandidates works like selectCandidates but draws from the
+// units above teamName, preferring the closest ancestor that has anyone
+// available.
+func selectAncestorCandidates(ctx context.Context, q querier, teamName string, excludeIDs []string, limit int) ([]string, error) {
+	rows, err := q.Query(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_team AS team_name, 1 AS depth
+			FROM teams
+			WHERE team_name = $1 AND parent_team IS NOT NULL
+			UNION ALL
+			SELECT t.parent_team, a.depth + 1
+			FROM teams t
+			JOIN ancestors a ON t.team_name = a.team_name
+			WHERE t.parent_team IS NOT NULL
+		)
+		SELECT m.user_id
+		FROM ancestors a
+		JOIN team_memberships m ON m.team_name = a.team_name
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.is_active = true
+		  AND u.is_active = true
+		  AND m.user_id != ALL($2)
+		GROUP BY m.user_id
+		ORDER BY MIN(a.depth), RANDOM()
+		LIMIT $3
+	`, teamName, excludeIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func selectReviewerIDs(ctx context.Context, q querier, prID string) ([]string, error) {
+	rows, err := q.Query(ctx, "SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id = $1", prID)
 	if err != nil {
 		return nil, err
 	}
@@ -272,8 +546,10 @@ func (r *PRRepository) getReviewersTx(ctx context.Context, tx pgx.Tx, prID strin
 	var ids []string
 	for rows.Next() {
 		var id string
-		rows.Scan(&id)
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
 		ids = append(ids, id)
 	}
-	return ids, nil
+	return ids, rows.Err()
 }
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"avito-pr-service/internal/ratelimit"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimiter is a token bucket limiter shared by all replicas. Each call is
+// a single upsert, so concurrent requests for the same key are serialised by
+// the row lock.
+type RateLimiter struct {
+	pool *pgxpool.Pool
+}
+
+func NewRateLimiter(pool *pgxpool.Pool) *RateLimiter {
+	return &RateLimiter{pool: pool}
+}
+
+func (r *RateLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	const op = "RateLimiter.Allow"
+
+	// SET expressions all see the old row, so refill is computed once per
+	// column from the same state; rejected requests do not take a token.
+	var (
+		tokens  float64
+		allowed bool
+	)
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, last_allowed, updated_at, refill_secs)
+		VALUES ($1, $3::float8 - 1, TRUE, clock_timestamp(), $4)
+		ON CONFLICT (bucket_key) DO UPDATE SET
+			tokens = CASE
+				WHEN LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $2::float8) >= 1
+				THEN LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $2::float8) - 1
+				ELSE LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $2::float8)
+			END,
+			last_allowed = LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $2::float8) >= 1,
+			updated_at = clock_timestamp(),
+			refill_secs = $4
+		RETURNING tokens, last_allowed
+	`, key, limit.Rate, limit.Burst, limit.RefillTime().Seconds()).Scan(&tokens, &allowed)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ratelimit.NewResult(allowed, tokens, limit), nil
+}
+
+// PurgeIdle deletes buckets untouched for longer than idle that have also had
+// time to refill completely, so a purged bucket was full anyway.
+func (r *RateLimiter) PurgeIdle(ctx context.Context, idle time.Duration) (int64, error) {
+	const op = "RateLimiter.PurgeIdle"
+
+	res, err := r.pool.Exec(ctx, `
+		DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - make_interval(secs => GREATEST($1, refill_secs))
+	`, idle.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.RowsAffected(), nil
+}
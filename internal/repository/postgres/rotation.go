@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// lockReviewCursors locks the rotation cursors of those teamNames that use
+// round-robin, creating missing ones, until the transaction ends. Callers
+// that pick reviewers from several teams lock them all upfront: the cursors
+// are taken in team order, so concurrent transactions cannot deadlock on them.
+func lockReviewCursors(ctx context.Context, q querier, teamNames []string) error {
+	teams := slices.Clone(teamNames)
+	slices.Sort(teams)
+	teams = slices.Compact(teams)
+	if len(teams) == 0 {
+		return nil
+	}
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO review_cursors (team_name)
+		SELECT team_name FROM teams
+		WHERE team_name = ANY($1) AND review_strategy = 'round_robin'
+		ORDER BY team_name
+		ON CONFLICT (team_name) DO NOTHING
+	`, teams)
+	if err != nil {
+		return fmt.Errorf("create review cursors: %w", err)
+	}
+
+	_, err = q.Exec(ctx, `
+		SELECT team_name FROM review_cursors
+		WHERE team_name = ANY($1)
+		ORDER BY team_name
+		FOR UPDATE
+	`, teams)
+	if err != nil {
+		return fmt.Errorf("lock review cursors: %w", err)
+	}
+	return nil
+}
+
+// selectRoundRobinCandidates walks the same members as selectCandidates in
+// user_id order, starting after the team's cursor and wrapping around, and
+// moves the cursor to the last one picked. A member skipped because they
+// authored the PR, were inactive or were already assigned loses that turn:
+// the cursor moves past them, so they come up again only after the rotation
+// wraps around.
+func selectRoundRobinCandidates(ctx context.Context, q querier, teamName string, excludeIDs []string, limit int) ([]string, error) {
+	if err := lockReviewCursors(ctx, q, []string{teamName}); err != nil {
+		return nil, err
+	}
+
+	var cursor *string
+	err := q.QueryRow(ctx, `SELECT last_user_id FROM review_cursors WHERE team_name = $1`, teamName).Scan(&cursor)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("select review cursor: %w", err)
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT m.user_id
+		FROM team_memberships m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.team_name = $1
+		  AND m.is_active = true
+		  AND u.is_active = true
+		  AND m.user_id != ALL($2)
+		ORDER BY COALESCE(m.user_id <= $3, false), m.user_id
+		LIMIT $4
+	`, teamName, excludeIDs, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	_, err = q.Exec(ctx, `
+		UPDATE review_cursors SET last_user_id = $2, updated_at = NOW()
+		WHERE team_name = $1
+	`, teamName, ids[len(ids)-1])
+	if err != nil {
+		return nil, fmt.Errorf("move review cursor: %w", err)
+	}
+	return ids, nil
+}
@@ -4,49 +4,203 @@ import (
 	"context"
 	"fmt"
 
+	"avito-pr-service/internal/int_errors"
 	"avito-pr-service/internal/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type StatisticsRepository struct {
 	db *pgxpool.Pool
+	// useCounters serves GetStatistics without a time range from
+	// team_counters and reviewer_counters instead of aggregating reviews.
+	useCounters bool
 }
 
-func NewStatisticsRepository(db *pgxpool.Pool) *StatisticsRepository {
-	return &StatisticsRepository{db: db}
+func NewStatisticsRepository(db *pgxpool.Pool, useCounters bool) *StatisticsRepository {
+	return &StatisticsRepository{db: db, useCounters: useCounters}
 }
 
-func (r *StatisticsRepository) GetStatistics(ctx context.Context) (*model.Statistics, error) {
+// statisticsPRs selects the PRs matching a StatisticsFilter, taking team,
+// from, to and status as $1-$4. statisticsReviewers selects the reviewers
+// the filter lets through, taking the team as $1.
+const (
+	statisticsPRs = `
+        prs AS (
+            SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+            FROM pull_requests pr
+            JOIN users a ON a.user_id = pr.author_id
+            WHERE ($1::text IS NULL OR COALESCE(pr.team_name, a.team_name) = $1)
+              AND ($2::timestamptz IS NULL OR pr.created_at >= $2)
+              AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+              AND ($4::text IS NULL OR pr.status = $4)
+        )`
+	statisticsReviewers = `
+        reviewers AS (
+            SELECT u.user_id, u.username, u.team_name
+            FROM users u
+            WHERE $1::text IS NULL
+               OR EXISTS (SELECT 1 FROM team_memberships m WHERE m.user_id = u.user_id AND m.team_name = $1)
+        )`
+)
+
+// GetStatistics returns one page of reviewer and one page of PR statistics
+// along with the filtered totals. All queries read the same snapshot, so the
+// totals agree with the pages.
+func (r *StatisticsRepository) GetStatistics(ctx context.Context, filter model.StatisticsFilter, prPage, reviewerPage model.Page) (*model.Statistics, error) {
 	const op = "StatisticsRepository.GetStatistics"
 
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if filter.TeamName != nil {
+		if err := teamExists(ctx, tx, *filter.TeamName); err != nil {
+			return nil, err
+		}
+	}
+
 	stats := &model.Statistics{
 		ReviewersStats: []model.ReviewerStats{},
 		PRStats:        []model.PRStats{},
 	}
 
-	reviewersStatsRows, err := r.db.Query(ctx, `
-        SELECT 
-            u.user_id,
-            u.username,
-            u.team_name,
-            COUNT(DISTINCT pr.pull_request_id) as total_reviews,
-            COUNT(DISTINCT CASE WHEN pr.status = 'OPEN' THEN pr.pull_request_id END) as open_reviews,
-            COUNT(DISTINCT CASE WHEN pr.status = 'MERGED' THEN pr.pull_request_id END) as merged_reviews
-        FROM users u
-        LEFT JOIN pr_reviewers prr ON u.user_id = prr.reviewer_id
-        LEFT JOIN pull_requests pr ON prr.pull_request_id = pr.pull_request_id
-        GROUP BY u.user_id, u.username, u.team_name
-        ORDER BY total_reviews DESC
-    `)
-	if err != nil {
-		return nil, fmt.Errorf("%s: query reviewers stats: %w", op, err)
-	}
-	defer reviewersStatsRows.Close()
-
-	for reviewersStatsRows.Next() {
+	err = tx.QueryRow(ctx, `WITH`+statisticsReviewers+` SELECT COUNT(*) FROM reviewers`, filter.TeamName).Scan(&stats.TotalReviewers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query total reviewers: %w", op, err)
+	}
+
+	if r.useCounters && filter.Range.From == nil && filter.Range.To == nil {
+		err = readCountedStatistics(ctx, tx, filter, reviewerPage, stats)
+	} else {
+		err = readAggregatedStatistics(ctx, tx, filter, reviewerPage, stats)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The page is cut before reviewers are counted, so only its PRs are
+	// joined with pr_reviewers.
+	prStatsRows, err := tx.Query(ctx, `WITH`+statisticsPRs+`
+        SELECT
+            p.pull_request_id,
+            p.pull_request_name,
+            p.author_id,
+            p.status,
+            (SELECT COUNT(*) FROM pr_reviewers prr WHERE prr.pull_request_id = p.pull_request_id) AS reviewers_count
+        FROM prs p
+        ORDER BY p.created_at DESC, p.pull_request_id
+        OFFSET $5 LIMIT $6
+    `, filter.TeamName, filter.Range.From, filter.Range.To, filter.Status, prPage.Offset, prPage.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query pr stats: %w", op, err)
+	}
+	defer prStatsRows.Close()
+
+	for prStatsRows.Next() {
+		var ps model.PRStats
+		err := prStatsRows.Scan(
+			&ps.PullRequestID,
+			&ps.PullRequestName,
+			&ps.AuthorID,
+			&ps.Status,
+			&ps.ReviewersCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan pr stats: %w", op, err)
+		}
+		stats.PRStats = append(stats.PRStats, ps)
+	}
+	if err := prStatsRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// readCountedStatistics fills the PR total and the reviewer page from the
+// counters, which cover every PR regardless of creation time.
+func readCountedStatistics(ctx context.Context, q querier, filter model.StatisticsFilter, page model.Page, stats *model.Statistics) error {
+	err := q.QueryRow(ctx, `
+        SELECT COALESCE(SUM(
+            CASE WHEN $2::text IS NULL OR $2 = 'OPEN' THEN open_prs ELSE 0 END +
+            CASE WHEN $2::text IS NULL OR $2 = 'MERGED' THEN merged_prs ELSE 0 END
+        ), 0)
+        FROM team_counters
+        WHERE $1::text IS NULL OR team_name = $1
+    `, filter.TeamName, filter.Status).Scan(&stats.TotalPRs)
+	if err != nil {
+		return fmt.Errorf("query total prs: %w", err)
+	}
+
+	rows, err := q.Query(ctx, `WITH`+statisticsReviewers+`,
+        counts AS (
+            SELECT
+                reviewer_id,
+                SUM(open_reviews) FILTER (WHERE $2::text IS NULL OR $2 = 'OPEN') AS open_reviews,
+                SUM(merged_reviews) FILTER (WHERE $2::text IS NULL OR $2 = 'MERGED') AS merged_reviews
+            FROM reviewer_counters
+            WHERE $1::text IS NULL OR team_name = $1
+            GROUP BY reviewer_id
+        )
+        SELECT
+            r.user_id,
+            r.username,
+            r.team_name,
+            COALESCE(c.open_reviews, 0) + COALESCE(c.merged_reviews, 0) AS total_reviews,
+            COALESCE(c.open_reviews, 0),
+            COALESCE(c.merged_reviews, 0)
+        FROM reviewers r
+        LEFT JOIN counts c ON c.reviewer_id = r.user_id
+        ORDER BY total_reviews DESC, r.user_id
+        OFFSET $3 LIMIT $4
+    `, filter.TeamName, filter.Status, page.Offset, page.Limit)
+	if err != nil {
+		return fmt.Errorf("query reviewers stats: %w", err)
+	}
+	return scanReviewerStats(rows, stats)
+}
+
+// readAggregatedStatistics fills the PR total and the reviewer page by
+// aggregating the reviews of the filtered PRs.
+func readAggregatedStatistics(ctx context.Context, q querier, filter model.StatisticsFilter, page model.Page, stats *model.Statistics) error {
+	args := []any{filter.TeamName, filter.Range.From, filter.Range.To, filter.Status}
+
+	err := q.QueryRow(ctx, `WITH`+statisticsPRs+` SELECT COUNT(*) FROM prs`, args...).Scan(&stats.TotalPRs)
+	if err != nil {
+		return fmt.Errorf("query total prs: %w", err)
+	}
+
+	rows, err := q.Query(ctx, `WITH`+statisticsPRs+`,`+statisticsReviewers+`
+        SELECT
+            r.user_id,
+            r.username,
+            r.team_name,
+            COUNT(p.pull_request_id) AS total_reviews,
+            COUNT(p.pull_request_id) FILTER (WHERE p.status = 'OPEN') AS open_reviews,
+            COUNT(p.pull_request_id) FILTER (WHERE p.status = 'MERGED') AS merged_reviews
+        FROM reviewers r
+        LEFT JOIN pr_reviewers prr ON prr.reviewer_id = r.user_id
+        LEFT JOIN prs p ON p.pull_request_id = prr.pull_request_id
+        GROUP BY r.user_id, r.username, r.team_name
+        ORDER BY total_reviews DESC, r.user_id
+        OFFSET $5 LIMIT $6
+    `, append(args, page.Offset, page.Limit)...)
+	if err != nil {
+		return fmt.Errorf("query reviewers stats: %w", err)
+	}
+	return scanReviewerStats(rows, stats)
+}
+
+func scanReviewerStats(rows pgx.Rows, stats *model.Statistics) error {
+	defer rows.Close()
+
+	for rows.Next() {
 		var rs model.ReviewerStats
-		err := reviewersStatsRows.Scan(
+		err := rows.Scan(
 			&rs.UserID,
 			&rs.Username,
 			&rs.TeamName,
@@ -55,56 +209,560 @@ func (r *StatisticsRepository) GetStatistics(ctx context.Context) (*model.Statis
 			&rs.MergedReviews,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("%s: scan reviewers stats: %w", op, err)
+			return fmt.Errorf("scan reviewers stats: %w", err)
 		}
 		stats.ReviewersStats = append(stats.ReviewersStats, rs)
 	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iteration error: %w", err)
+	}
+	return nil
+}
 
-	prStatsRows, err := r.db.Query(ctx, `
-        SELECT 
+// expectedTeamCounters and expectedReviewerCounters compute the counters
+// from scratch; migration 00011 fills the tables with the same queries.
+const (
+	expectedTeamCounters = `
+        SELECT COALESCE(pr.team_name, a.team_name) AS team_name,
+               COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open_prs,
+               COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS merged_prs
+        FROM pull_requests pr
+        JOIN users a ON a.user_id = pr.author_id
+        GROUP BY 1`
+	expectedReviewerCounters = `
+        SELECT prr.reviewer_id, COALESCE(pr.team_name, a.team_name) AS team_name,
+               COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open_reviews,
+               COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS merged_reviews
+        FROM pr_reviewers prr
+        JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+        JOIN users a ON a.user_id = pr.author_id
+        GROUP BY 1, 2`
+)
+
+// RebuildCounters compares the statistics counters with values computed from
+// scratch and rewrites the tables if any row differs. Mutations wait for it
+// on the table locks; readers do not.
+func (r *StatisticsRepository) RebuildCounters(ctx context.Context) (*model.CounterCheck, error) {
+	const op = "StatisticsRepository.RebuildCounters"
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE team_counters, reviewer_counters IN EXCLUSIVE MODE`); err != nil {
+		return nil, fmt.Errorf("%s: lock counters: %w", op, err)
+	}
+
+	check := &model.CounterCheck{}
+	err = tx.QueryRow(ctx, `
+        WITH expected AS (`+expectedTeamCounters+`)
+        SELECT COUNT(*)
+        FROM expected e
+        FULL JOIN team_counters c ON c.team_name = e.team_name
+        WHERE COALESCE(e.open_prs, 0) <> COALESCE(c.open_prs, 0)
+           OR COALESCE(e.merged_prs, 0) <> COALESCE(c.merged_prs, 0)
+    `).Scan(&check.TeamRows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: check team counters: %w", op, err)
+	}
+
+	err = tx.QueryRow(ctx, `
+        WITH expected AS (`+expectedReviewerCounters+`)
+        SELECT COUNT(*)
+        FROM expected e
+        FULL JOIN reviewer_counters c ON c.reviewer_id = e.reviewer_id AND c.team_name = e.team_name
+        WHERE COALESCE(e.open_reviews, 0) <> COALESCE(c.open_reviews, 0)
+           OR COALESCE(e.merged_reviews, 0) <> COALESCE(c.merged_reviews, 0)
+    `).Scan(&check.ReviewerRows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: check reviewer counters: %w", op, err)
+	}
+
+	if check.TeamRows > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM team_counters`); err != nil {
+			return nil, fmt.Errorf("%s: clear team counters: %w", op, err)
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO team_counters (team_name, open_prs, merged_prs)`+expectedTeamCounters)
+		if err != nil {
+			return nil, fmt.Errorf("%s: rebuild team counters: %w", op, err)
+		}
+	}
+	if check.ReviewerRows > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM reviewer_counters`); err != nil {
+			return nil, fmt.Errorf("%s: clear reviewer counters: %w", op, err)
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO reviewer_counters (reviewer_id, team_name, open_reviews, merged_reviews)`+expectedReviewerCounters)
+		if err != nil {
+			return nil, fmt.Errorf("%s: rebuild reviewer counters: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return check, nil
+}
+
+// GetSubtreeStatistics rolls pull request and membership counts up the team
+// hierarchy starting at rootTeam. Every unit carries its own counts and the
+// totals of its whole subtree.
+func (r *StatisticsRepository) GetSubtreeStatistics(ctx context.Context, rootTeam string) (*model.SubtreeStatistics, error) {
+	const op = "StatisticsRepository.GetSubtreeStatistics"
+
+	rows, err := r.db.Query(ctx, `
+        WITH RECURSIVE subtree AS (
+            SELECT team_name, parent_team, 0 AS depth, ARRAY[team_name] AS path
+            FROM teams
+            WHERE team_name = $1
+            UNION ALL
+            SELECT t.team_name, t.parent_team, s.depth + 1, s.path || t.team_name
+            FROM teams t
+            JOIN subtree s ON t.parent_team = s.team_name
+        ),
+        own AS (
+            SELECT
+                s.team_name,
+                COUNT(pr.pull_request_id) AS total_prs,
+                COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open_prs,
+                COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS merged_prs
+            FROM subtree s
+            LEFT JOIN pull_requests pr ON pr.team_name = s.team_name
+            GROUP BY s.team_name
+        )
+        SELECT
+            s.team_name,
+            s.parent_team,
+            s.depth,
+            (SELECT COUNT(*) FROM team_memberships m WHERE m.team_name = s.team_name) AS members,
+            o.total_prs,
+            o.open_prs,
+            o.merged_prs,
+            (SELECT COUNT(*) FROM subtree d WHERE s.team_name = ANY(d.path)) AS subtree_teams,
+            (SELECT COUNT(DISTINCT m.user_id)
+               FROM subtree d
+               JOIN team_memberships m ON m.team_name = d.team_name
+              WHERE s.team_name = ANY(d.path)) AS subtree_members,
+            (SELECT COALESCE(SUM(od.total_prs), 0)::bigint
+               FROM subtree d JOIN own od ON od.team_name = d.team_name
+              WHERE s.team_name = ANY(d.path)) AS subtree_total_prs,
+            (SELECT COALESCE(SUM(od.open_prs), 0)::bigint
+               FROM subtree d JOIN own od ON od.team_name = d.team_name
+              WHERE s.team_name = ANY(d.path)) AS subtree_open_prs,
+            (SELECT COALESCE(SUM(od.merged_prs), 0)::bigint
+               FROM subtree d JOIN own od ON od.team_name = d.team_name
+              WHERE s.team_name = ANY(d.path)) AS subtree_merged_prs
+        FROM subtree s
+        JOIN own o ON o.team_name = s.team_name
+        ORDER BY s.path
+    `, rootTeam)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query subtree stats: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := &model.SubtreeStatistics{
+		RootTeam: rootTeam,
+		Units:    []model.UnitStats{},
+	}
+	for rows.Next() {
+		var us model.UnitStats
+		err := rows.Scan(
+			&us.TeamName,
+			&us.ParentTeam,
+			&us.Depth,
+			&us.Members,
+			&us.TotalPRs,
+			&us.OpenPRs,
+			&us.MergedPRs,
+			&us.Subtree.Teams,
+			&us.Subtree.Members,
+			&us.Subtree.TotalPRs,
+			&us.Subtree.OpenPRs,
+			&us.Subtree.MergedPRs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan subtree stats: %w", op, err)
+		}
+		stats.Units = append(stats.Units, us)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	if len(stats.Units) == 0 {
+		return nil, int_errors.ErrTeamNotFound
+	}
+
+	return stats, nil
+}
+
+// GetOpenReviewCounts returns how many open PRs each reviewer is assigned to.
+// Reviewers without open reviews are left out.
+func (r *StatisticsRepository) GetOpenReviewCounts(ctx context.Context) (map[string]int, error) {
+	const op = "StatisticsRepository.GetOpenReviewCounts"
+
+	rows, err := r.db.Query(ctx, `
+		SELECT prr.reviewer_id, COUNT(*)
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		WHERE pr.status = 'OPEN'
+		GROUP BY prr.reviewer_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			reviewerID string
+			n          int
+		)
+		if err := rows.Scan(&reviewerID, &n); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		counts[reviewerID] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// timingPRs is the per-PR base of the timing statistics: the PR's team, its
+// current reviewer count, how often a reviewer was taken off it, and when it
+// first had a reviewer. A reassignment implies an earlier assignment, so
+// logged reassignments count towards the latter too.
+const timingPRs = `
+    WITH prs AS (
+        SELECT
             pr.pull_request_id,
-            pr.pull_request_name,
-            pr.author_id,
+            COALESCE(pr.team_name, a.team_name) AS team_name,
             pr.status,
-            COUNT(prr.reviewer_id) as reviewers_count
+            pr.created_at,
+            pr.merged_at,
+            (SELECT COUNT(*) FROM pr_reviewers prr
+              WHERE prr.pull_request_id = pr.pull_request_id) AS reviewers,
+            (SELECT COUNT(*) FROM pr_reassignments ra
+              WHERE ra.pull_request_id = pr.pull_request_id) AS reassignments,
+            LEAST(
+                (SELECT MIN(prr.assigned_at) FROM pr_reviewers prr
+                  WHERE prr.pull_request_id = pr.pull_request_id),
+                (SELECT MIN(ra.reassigned_at) FROM pr_reassignments ra
+                  WHERE ra.pull_request_id = pr.pull_request_id)
+            ) AS first_assigned_at
         FROM pull_requests pr
-        LEFT JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id
-        GROUP BY pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
-        ORDER BY pr.created_at DESC
-    `)
+        JOIN users a ON a.user_id = pr.author_id
+        WHERE ($1::timestamptz IS NULL OR pr.created_at >= $1)
+          AND ($2::timestamptz IS NULL OR pr.created_at < $2)
+    )
+`
+
+// GetReviewLoads returns, per team, the reviews each active member was
+// assigned within rng, members without any included. Only the current
+// reviewers in pr_reviewers are counted, so assignments later reassigned
+// away are not. Archived teams are left out.
+func (r *StatisticsRepository) GetReviewLoads(ctx context.Context, teamName *string, rng model.TimeRange) ([]model.TeamLoad, error) {
+	const op = "StatisticsRepository.GetReviewLoads"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
 	if err != nil {
-		return nil, fmt.Errorf("%s: query pr stats: %w", op, err)
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
 	}
-	defer prStatsRows.Close()
+	defer tx.Rollback(ctx)
 
-	for prStatsRows.Next() {
-		var ps model.PRStats
-		err := prStatsRows.Scan(
-			&ps.PullRequestID,
-			&ps.PullRequestName,
-			&ps.AuthorID,
-			&ps.Status,
-			&ps.ReviewersCount,
+	if teamName != nil {
+		if err := teamExists(ctx, tx, *teamName); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+        WITH members AS (
+            SELECT m.team_name, u.user_id, u.username
+            FROM team_memberships m
+            JOIN users u ON u.user_id = m.user_id
+            JOIN teams t ON t.team_name = m.team_name
+            WHERE m.is_active AND u.is_active AND t.archived_at IS NULL
+              AND ($1::text IS NULL OR m.team_name = $1)
+        ),
+        prs AS (
+            SELECT pr.pull_request_id, pr.author_id, pr.created_at, COALESCE(pr.team_name, a.team_name) AS team_name
+            FROM pull_requests pr
+            JOIN users a ON a.user_id = pr.author_id
+            WHERE $1::text IS NULL OR COALESCE(pr.team_name, a.team_name) = $1
+        ),
+        assignments AS (
+            SELECT p.team_name, prr.reviewer_id, COUNT(*) AS n
+            FROM pr_reviewers prr
+            JOIN prs p ON p.pull_request_id = prr.pull_request_id
+            WHERE ($2::timestamptz IS NULL OR prr.assigned_at >= $2)
+              AND ($3::timestamptz IS NULL OR prr.assigned_at < $3)
+            GROUP BY 1, 2
+        ),
+        authored AS (
+            SELECT team_name, author_id, COUNT(*) AS n
+            FROM prs
+            WHERE ($2::timestamptz IS NULL OR created_at >= $2)
+              AND ($3::timestamptz IS NULL OR created_at < $3)
+            GROUP BY 1, 2
+        )
+        SELECT mb.team_name, mb.user_id, mb.username, COALESCE(asg.n, 0), COALESCE(au.n, 0)
+        FROM members mb
+        LEFT JOIN assignments asg ON asg.team_name = mb.team_name AND asg.reviewer_id = mb.user_id
+        LEFT JOIN authored au ON au.team_name = mb.team_name AND au.author_id = mb.user_id
+        ORDER BY mb.team_name, COALESCE(asg.n, 0) DESC, mb.user_id
+    `, teamName, rng.From, rng.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var loads []model.TeamLoad
+	for rows.Next() {
+		var (
+			team string
+			m    model.MemberLoad
 		)
-		if err != nil {
-			return nil, fmt.Errorf("%s: scan pr stats: %w", op, err)
+		if err := rows.Scan(&team, &m.UserID, &m.Username, &m.Assignments, &m.AuthoredPRs); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
 		}
-		stats.PRStats = append(stats.PRStats, ps)
+		if n := len(loads); n == 0 || loads[n-1].TeamName != team {
+			loads = append(loads, model.TeamLoad{TeamName: team})
+		}
+		last := &loads[len(loads)-1]
+		last.Members = append(last.Members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
 	}
 
-	var totalPRs, totalReviewers int
-	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM pull_requests`).Scan(&totalPRs)
+	return loads, nil
+}
+
+// GetReviewPairs returns the active members of a team, by user ID, and how
+// many reviews of the team's PRs each author got from each reviewer within
+// rng, by assignment time.
+func (r *StatisticsRepository) GetReviewPairs(ctx context.Context, teamName string, rng model.TimeRange) ([]model.PairMember, []model.ReviewPair, error) {
+	const op = "StatisticsRepository.GetReviewPairs"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
 	if err != nil {
-		return nil, fmt.Errorf("%s: query total prs: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: start transaction: %w", op, err)
 	}
+	defer tx.Rollback(ctx)
 
-	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&totalReviewers)
+	if err := teamExists(ctx, tx, teamName); err != nil {
+		return nil, nil, err
+	}
+
+	memberRows, err := tx.Query(ctx, `
+        SELECT u.user_id, u.username
+        FROM team_memberships m
+        JOIN users u ON u.user_id = m.user_id
+        WHERE m.team_name = $1 AND m.is_active AND u.is_active
+        ORDER BY u.user_id
+    `, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("%s: query total reviewers: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: query members: %w", op, err)
 	}
+	defer memberRows.Close()
 
-	stats.TotalPRs = totalPRs
-	stats.TotalReviewers = totalReviewers
+	var members []model.PairMember
+	for memberRows.Next() {
+		var m model.PairMember
+		if err := memberRows.Scan(&m.UserID, &m.Username); err != nil {
+			return nil, nil, fmt.Errorf("%s: scan member: %w", op, err)
+		}
+		members = append(members, m)
+	}
+	if err := memberRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	pairRows, err := tx.Query(ctx, `
+        SELECT pr.author_id, prr.reviewer_id, COUNT(*)
+        FROM pr_reviewers prr
+        JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+        JOIN users a ON a.user_id = pr.author_id
+        WHERE COALESCE(pr.team_name, a.team_name) = $1
+          AND ($2::timestamptz IS NULL OR prr.assigned_at >= $2)
+          AND ($3::timestamptz IS NULL OR prr.assigned_at < $3)
+        GROUP BY 1, 2
+    `, teamName, rng.From, rng.To)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: query pairs: %w", op, err)
+	}
+	defer pairRows.Close()
+
+	var pairs []model.ReviewPair
+	for pairRows.Next() {
+		var p model.ReviewPair
+		if err := pairRows.Scan(&p.AuthorID, &p.ReviewerID, &p.Assignments); err != nil {
+			return nil, nil, fmt.Errorf("%s: scan pair: %w", op, err)
+		}
+		pairs = append(pairs, p)
+	}
+	if err := pairRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	return members, pairs, nil
+}
+
+// percentiles yields p50, p90 and p99 of a duration in seconds; NULL
+// durations, such as those of open PRs, are ignored.
+func percentiles(duration string) string {
+	return `percentile_cont(ARRAY[0.5, 0.9, 0.99]) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM ` + duration + `)::float8)`
+}
+
+// GetTimingStatistics computes review latencies and reassignment churn for
+// PRs created within rng. All queries read the same snapshot.
+func (r *StatisticsRepository) GetTimingStatistics(ctx context.Context, rng model.TimeRange) (*model.TimingStatistics, error) {
+	const op = "StatisticsRepository.GetTimingStatistics"
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	stats := &model.TimingStatistics{
+		From:         rng.From,
+		To:           rng.To,
+		Teams:        []model.TeamTiming{},
+		Reviewers:    []model.ReviewerTiming{},
+		PullRequests: []model.PRReassignments{},
+	}
+
+	var firstAssignment, merge []float64
+	sum := &stats.Summary
+	err = tx.QueryRow(ctx, timingPRs+`
+        SELECT
+            COUNT(*),
+            COUNT(*) FILTER (WHERE status = 'MERGED'),
+            COUNT(*) FILTER (WHERE first_assigned_at IS NULL),
+            `+percentiles("first_assigned_at - created_at")+`,
+            `+percentiles("merged_at - created_at")+`,
+            COALESCE(SUM(reassignments), 0)::bigint,
+            COUNT(*) FILTER (WHERE status = 'MERGED' AND reviewers < 2)
+        FROM prs
+    `, rng.From, rng.To).Scan(
+		&sum.TotalPRs,
+		&sum.MergedPRs,
+		&sum.UnassignedPRs,
+		&firstAssignment,
+		&merge,
+		&sum.Reassignments,
+		&sum.MergedUnderTwoReviewers,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query summary: %w", op, err)
+	}
+	sum.TimeToFirstAssignment = toPercentiles(firstAssignment)
+	sum.TimeToMerge = toPercentiles(merge)
+	if sum.TotalPRs > 0 {
+		sum.ReassignmentsPerPR = float64(sum.Reassignments) / float64(sum.TotalPRs)
+	}
+	if sum.MergedPRs > 0 {
+		sum.MergedUnderTwoReviewersShare = float64(sum.MergedUnderTwoReviewers) / float64(sum.MergedPRs)
+	}
+
+	teamRows, err := tx.Query(ctx, timingPRs+`
+        SELECT
+            team_name,
+            COUNT(*),
+            COUNT(*) FILTER (WHERE status = 'MERGED'),
+            `+percentiles("first_assigned_at - created_at")+`,
+            `+percentiles("merged_at - created_at")+`,
+            COALESCE(SUM(reassignments), 0)::bigint
+        FROM prs
+        GROUP BY team_name
+        ORDER BY team_name
+    `, rng.From, rng.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query team timing: %w", op, err)
+	}
+	defer teamRows.Close()
+
+	for teamRows.Next() {
+		var tt model.TeamTiming
+		if err := teamRows.Scan(&tt.TeamName, &tt.TotalPRs, &tt.MergedPRs, &firstAssignment, &merge, &tt.Reassignments); err != nil {
+			return nil, fmt.Errorf("%s: scan team timing: %w", op, err)
+		}
+		tt.TimeToFirstAssignment = toPercentiles(firstAssignment)
+		tt.TimeToMerge = toPercentiles(merge)
+		stats.Teams = append(stats.Teams, tt)
+	}
+	if err := teamRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	reviewerRows, err := tx.Query(ctx, timingPRs+`
+        SELECT
+            u.user_id,
+            u.username,
+            COUNT(*),
+            `+percentiles("p.merged_at - prr.assigned_at")+`
+        FROM prs p
+        JOIN pr_reviewers prr ON prr.pull_request_id = p.pull_request_id
+        JOIN users u ON u.user_id = prr.reviewer_id
+        WHERE p.status = 'MERGED'
+        GROUP BY u.user_id, u.username
+        ORDER BY u.user_id
+    `, rng.From, rng.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query reviewer timing: %w", op, err)
+	}
+	defer reviewerRows.Close()
+
+	for reviewerRows.Next() {
+		var rt model.ReviewerTiming
+		if err := reviewerRows.Scan(&rt.UserID, &rt.Username, &rt.MergedReviews, &merge); err != nil {
+			return nil, fmt.Errorf("%s: scan reviewer timing: %w", op, err)
+		}
+		rt.TimeToMerge = toPercentiles(merge)
+		stats.Reviewers = append(stats.Reviewers, rt)
+	}
+	if err := reviewerRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	prRows, err := tx.Query(ctx, timingPRs+`
+        SELECT pull_request_id, team_name, reassignments
+        FROM prs
+        WHERE reassignments > 0
+        ORDER BY reassignments DESC, pull_request_id
+    `, rng.From, rng.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query reassignments: %w", op, err)
+	}
+	defer prRows.Close()
+
+	for prRows.Next() {
+		var pr model.PRReassignments
+		if err := prRows.Scan(&pr.PullRequestID, &pr.TeamName, &pr.Reassignments); err != nil {
+			return nil, fmt.Errorf("%s: scan reassignments: %w", op, err)
+		}
+		stats.PullRequests = append(stats.PullRequests, pr)
+	}
+	if err := prRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
 
 	return stats, nil
 }
+
+// toPercentiles converts the array percentile_cont returns; it is NULL when
+// there was nothing to measure.
+func toPercentiles(values []float64) *model.Percentiles {
+	if len(values) != 3 {
+		return nil
+	}
+	return &model.Percentiles{P50: values[0], P90: values[1], P99: values[2]}
+}
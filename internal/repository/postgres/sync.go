@@ -0,0 +1,321 @@
+package postgres
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/model"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// OrgState reads the teams, users, memberships and open reviews an org sync
+// is planned against.
+func (r *TeamRepository) OrgState(ctx context.Context) (*model.OrgState, error) {
+	const op = "TeamRepository.OrgState"
+
+	state, err := readOrgState(ctx, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return state, nil
+}
+
+// ApplySync plans against the current state and executes the plan in one
+// transaction. Concurrent syncs are serialised with an advisory lock. When
+// planID is set and differs from the fresh plan, nothing is changed and
+// int_errors.ErrSyncPlanStale is returned.
+//
+// Reviews affected by the plan are handed to another active member of the
+// PR's team once all membership changes are in place, the same way
+// DeleteUser does; reviews without a replacement are dropped.
+func (r *TeamRepository) ApplySync(ctx context.Context, desired []*model.Team, opts model.SyncOptions, planID string) (*model.SyncResult, error) {
+	const op = "TeamRepository.ApplySync"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('org_sync'))`); err != nil {
+		return nil, fmt.Errorf("%s: lock: %w", op, err)
+	}
+
+	state, err := readOrgState(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	plan := model.PlanSync(state, desired, opts)
+	if planID != "" && planID != plan.ID {
+		return nil, int_errors.ErrSyncPlanStale
+	}
+
+	for _, t := range plan.Teams {
+		switch t.Action {
+		case model.SyncCreateTeam:
+			_, err = tx.Exec(ctx, `INSERT INTO teams (team_name) VALUES ($1)`, t.TeamName)
+		case model.SyncRestoreTeam:
+			_, err = tx.Exec(ctx, `UPDATE teams SET archived_at = NULL WHERE team_name = $1`, t.TeamName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s team %s: %w", op, t.Action, t.TeamName, err)
+		}
+	}
+
+	for _, u := range plan.Users {
+		switch u.Action {
+		case model.SyncAddUser, model.SyncMoveUser, model.SyncUpdateUser:
+			err = syncUser(ctx, tx, u)
+		case model.SyncDeactivateUser, model.SyncRemoveUser:
+			// Removed users are deactivated first so that they are not
+			// picked as replacements before they are deleted below.
+			_, err = tx.Exec(ctx, `UPDATE users SET is_active = false WHERE user_id = $1`, u.UserID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s user %s: %w", op, u.Action, u.UserID, err)
+		}
+	}
+
+	reassignments, err := handOverReviews(ctx, tx, plan.AffectedReviews)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The plan only removes users without PR history, so nothing counted
+	// goes with them.
+	for _, u := range plan.Users {
+		if u.Action != model.SyncRemoveUser {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM users WHERE user_id = $1`, u.UserID); err != nil {
+			return nil, fmt.Errorf("%s: remove user %s: %w", op, u.UserID, err)
+		}
+	}
+
+	for _, t := range plan.Teams {
+		if t.Action != model.SyncArchiveTeam {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `UPDATE teams SET archived_at = NOW() WHERE team_name = $1`, t.TeamName); err != nil {
+			return nil, fmt.Errorf("%s: archive team %s: %w", op, t.TeamName, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return &model.SyncResult{Plan: plan, Reassignments: reassignments}, nil
+}
+
+// handOverReviews replaces each reviewer with another active member of the
+// PR's team, the way DeleteUser does; reviews without a replacement are
+// dropped. The reviewers must already be off the team, or inactive, so that
+// they are not picked again.
+func handOverReviews(ctx context.Context, q querier, reviews []model.AffectedReview) ([]model.SyncReassignment, error) {
+	prIDs := make([]string, 0, len(reviews))
+	teams := make([]string, 0, len(reviews))
+	for _, rv := range reviews {
+		if !slices.Contains(prIDs, rv.PRID) {
+			prIDs = append(prIDs, rv.PRID)
+		}
+		teams = append(teams, rv.TeamName)
+	}
+	counters := counterDelta{}
+	if err := counters.count(ctx, q, prIDs, -1); err != nil {
+		return nil, err
+	}
+	if err := lockReviewCursors(ctx, q, teams); err != nil {
+		return nil, err
+	}
+
+	reassignments := make([]model.SyncReassignment, 0, len(reviews))
+	for _, rv := range reviews {
+		_, err := q.Exec(ctx, `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`, rv.PRID, rv.ReviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("delete review %s of %s: %w", rv.PRID, rv.ReviewerID, err)
+		}
+
+		current, err := selectReviewerIDs(ctx, q, rv.PRID)
+		if err != nil {
+			return nil, fmt.Errorf("get reviewers of %s: %w", rv.PRID, err)
+		}
+
+		candidates, err := selectTeamReviewers(ctx, q, rv.TeamName, rv.PRID, rv.AuthorID, append([]string{rv.ReviewerID, rv.AuthorID}, current...), 1)
+		if err != nil {
+			return nil, fmt.Errorf("select replacement for %s: %w", rv.PRID, err)
+		}
+		if len(candidates) == 0 {
+			logger.FromContext(ctx).Warn("no replacement reviewer",
+				slog.String("user_id", rv.ReviewerID),
+				slog.String("pr_id", rv.PRID),
+			)
+			if err := logReassignment(ctx, q, rv.PRID, rv.ReviewerID, nil); err != nil {
+				return nil, err
+			}
+			reassignments = append(reassignments, model.SyncReassignment{PRID: rv.PRID, ReviewerID: rv.ReviewerID})
+			continue
+		}
+		newReviewerID := candidates[0]
+
+		_, err = q.Exec(ctx, `INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`, rv.PRID, newReviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("insert replacement for %s: %w", rv.PRID, err)
+		}
+		if err := logReassignment(ctx, q, rv.PRID, rv.ReviewerID, &newReviewerID); err != nil {
+			return nil, err
+		}
+
+		reassignments = append(reassignments, model.SyncReassignment{PRID: rv.PRID, ReviewerID: rv.ReviewerID, ReplacedBy: &newReviewerID})
+	}
+
+	if err := counters.count(ctx, q, prIDs, 1); err != nil {
+		return nil, err
+	}
+	if err := counters.apply(ctx, q); err != nil {
+		return nil, err
+	}
+	return reassignments, nil
+}
+
+// syncUser makes the user's row and memberships match the desired state
+// exactly, reactivating the user if needed.
+func syncUser(ctx context.Context, q querier, u model.SyncUserChange) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO users (user_id, username, team_name, is_active)
+		VALUES ($1, $2, $3, true)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			username = EXCLUDED.username,
+			team_name = EXCLUDED.team_name,
+			is_active = true
+	`, u.UserID, u.Username, u.PrimaryTeam)
+	if err != nil {
+		return fmt.Errorf("upsert user: %w", err)
+	}
+
+	_, err = q.Exec(ctx, `DELETE FROM team_memberships WHERE user_id = $1 AND team_name <> ALL($2)`, u.UserID, u.ToTeams)
+	if err != nil {
+		return fmt.Errorf("delete memberships: %w", err)
+	}
+
+	for _, m := range u.Memberships {
+		_, err := q.Exec(ctx, `
+			INSERT INTO team_memberships (user_id, team_name, role, is_active)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, team_name)
+			DO UPDATE SET
+				role = EXCLUDED.role,
+				is_active = EXCLUDED.is_active
+		`, u.UserID, m.TeamName, m.Role, m.IsActive)
+		if err != nil {
+			return fmt.Errorf("upsert membership %s: %w", m.TeamName, err)
+		}
+	}
+
+	return nil
+}
+
+func readOrgState(ctx context.Context, q querier) (*model.OrgState, error) {
+	state := &model.OrgState{
+		Teams: make(map[string]bool),
+		Users: make(map[string]*model.OrgUser),
+	}
+
+	rows, err := q.Query(ctx, `SELECT team_name, archived_at IS NOT NULL FROM teams`)
+	if err != nil {
+		return nil, fmt.Errorf("select teams: %w", err)
+	}
+	for rows.Next() {
+		var (
+			name     string
+			archived bool
+		)
+		if err := rows.Scan(&name, &archived); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		state.Teams[name] = archived
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select teams: %w", err)
+	}
+
+	rows, err = q.Query(ctx, `
+		SELECT u.user_id, u.username, u.team_name, u.is_active,
+		       EXISTS(SELECT 1 FROM pull_requests pr WHERE pr.author_id = u.user_id AND pr.status = 'OPEN'),
+		       EXISTS(SELECT 1 FROM pull_requests pr WHERE pr.author_id = u.user_id)
+		           OR EXISTS(
+		               SELECT 1
+		               FROM pr_reviewers prr
+		               JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		               WHERE prr.reviewer_id = u.user_id AND pr.status <> 'OPEN'
+		           )
+		FROM users u
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("select users: %w", err)
+	}
+	for rows.Next() {
+		u := &model.OrgUser{Memberships: make(map[string]model.TeamMembership)}
+		if err := rows.Scan(&u.ID, &u.Username, &u.PrimaryTeam, &u.IsActive, &u.AuthorsOpenPRs, &u.HasHistory); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		state.Users[u.ID] = u
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select users: %w", err)
+	}
+
+	rows, err = q.Query(ctx, `SELECT user_id, team_name, role, is_active FROM team_memberships`)
+	if err != nil {
+		return nil, fmt.Errorf("select memberships: %w", err)
+	}
+	for rows.Next() {
+		var (
+			userID string
+			m      model.TeamMembership
+		)
+		if err := rows.Scan(&userID, &m.TeamName, &m.Role, &m.IsActive); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan membership: %w", err)
+		}
+		if u, ok := state.Users[userID]; ok {
+			u.Memberships[m.TeamName] = m
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select memberships: %w", err)
+	}
+
+	rows, err = q.Query(ctx, `
+		SELECT pr.pull_request_id, COALESCE(pr.team_name, a.team_name), pr.author_id, prr.reviewer_id
+		FROM pull_requests pr
+		JOIN pr_reviewers prr ON prr.pull_request_id = pr.pull_request_id
+		JOIN users a ON a.user_id = pr.author_id
+		WHERE pr.status = 'OPEN'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("select open reviews: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rv model.OpenReview
+		if err := rows.Scan(&rv.PRID, &rv.TeamName, &rv.AuthorID, &rv.ReviewerID); err != nil {
+			return nil, fmt.Errorf("scan open review: %w", err)
+		}
+		state.OpenReviews = append(state.OpenReviews, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select open reviews: %w", err)
+	}
+
+	return state, nil
+}
@@ -4,8 +4,12 @@ import (
 	"avito-pr-service/internal/int_errors"
 	"avito-pr-service/internal/model"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,20 +26,21 @@ func (r *TeamRepository) GetTeam(ctx context.Context, teamName string) (*model.T
 	const op = "TeamRepository.GetTeam"
 
 	rows, err := r.pool.Query(ctx, `
-		SELECT user_id, username, is_active
-		FROM users
-		WHERE team_name = $1
-		ORDER BY username
+		SELECT u.user_id, u.username, m.role, m.is_active AND u.is_active
+		FROM team_memberships m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.team_name = $1
+		ORDER BY u.username
 	`, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("%s: query failed: %w", op, err)
 	}
 	defer rows.Close()
 
-	var members []*model.User
+	var members []*model.TeamMember
 	for rows.Next() {
-		var member model.User
-		if err := rows.Scan(&member.ID, &member.Username, &member.IsActive); err != nil {
+		var member model.TeamMember
+		if err := rows.Scan(&member.UserID, &member.Username, &member.Role, &member.IsActive); err != nil {
 			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
 		}
 		members = append(members, &member)
@@ -62,31 +67,572 @@ func (r *TeamRepository) AddTeam(ctx context.Context, team *model.Team) (*model.
 	}
 	defer tx.Rollback(ctx)
 
-	// Insert or ignore if already exists
-	_, err = tx.Exec(ctx, `INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT (team_name) DO NOTHING`, team.TeamName)
+	if _, _, err := upsertTeam(ctx, tx, team); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return team, nil
+}
+
+// ImportTeams upserts all teams in a single transaction, so a failure on any
+// of them leaves the database untouched. With dryRun the transaction is rolled
+// back after the writes, which makes the returned counts exact.
+func (r *TeamRepository) ImportTeams(ctx context.Context, teams []*model.Team, dryRun bool) (*model.ImportResult, error) {
+	const op = "TeamRepository.ImportTeams"
+
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("%s: insert team: %w", op, err)
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	res := &model.ImportResult{DryRun: dryRun, Teams: len(teams)}
+	for _, team := range teams {
+		teamCreated, usersCreated, err := upsertTeam(ctx, tx, team)
+		if err != nil {
+			return nil, fmt.Errorf("%s: team %s: %w", op, team.TeamName, err)
+		}
+		if teamCreated {
+			res.TeamsCreated++
+		}
+		res.UsersCreated += usersCreated
+		res.Members += len(team.Members)
+	}
+
+	if dryRun {
+		return res, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// upsertTeam creates the team if needed and upserts its members. New users get
+// this team as their primary one; existing users keep their primary team and
+// simply gain (or update) a membership here.
+func upsertTeam(ctx context.Context, tx pgx.Tx, team *model.Team) (teamCreated bool, usersCreated int, err error) {
+	// Adding to an archived team brings it back.
+	err = tx.QueryRow(ctx, `
+		INSERT INTO teams (team_name) VALUES ($1)
+		ON CONFLICT (team_name) DO UPDATE SET archived_at = NULL
+		RETURNING xmax = 0
+	`, team.TeamName).Scan(&teamCreated)
+	if err != nil {
+		return false, 0, fmt.Errorf("insert team: %w", err)
 	}
 
 	for _, member := range team.Members {
-		_, err := tx.Exec(ctx, `
-			INSERT INTO users (user_id, username, team_name, is_active)
-			VALUES ($1, $2, $3, $4)
+		// xmax is zero only for a freshly inserted row.
+		var inserted bool
+		err := tx.QueryRow(ctx, `
+			INSERT INTO users (user_id, username, team_name)
+			VALUES ($1, $2, $3)
 			ON CONFLICT (user_id) 
-			DO UPDATE SET 
-				username = EXCLUDED.username, 
-				team_name = EXCLUDED.team_name, 
+			DO UPDATE SET username = EXCLUDED.username
+			RETURNING xmax = 0
+		`, member.UserID, member.Username, team.TeamName).Scan(&inserted)
+		if err != nil {
+			return false, 0, fmt.Errorf("upsert user %s: %w", member.UserID, err)
+		}
+		if inserted {
+			usersCreated++
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO team_memberships (user_id, team_name, role, is_active)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, team_name)
+			DO UPDATE SET
+				role = EXCLUDED.role,
 				is_active = EXCLUDED.is_active
-		`, member.ID, member.Username, team.TeamName, member.IsActive)
+		`, member.UserID, team.TeamName, member.Role, member.IsActive)
+		if err != nil {
+			return false, 0, fmt.Errorf("upsert membership %s: %w", member.UserID, err)
+		}
+	}
+
+	return teamCreated, usersCreated, nil
+}
+
+// ListTeams returns the teams that are not archived with their members,
+// including teams that have none, ordered by team name and username. With
+// teamNames only those teams are returned.
+func (r *TeamRepository) ListTeams(ctx context.Context, teamNames ...string) ([]*model.Team, error) {
+	const op = "TeamRepository.ListTeams"
+
+	teams, err := selectTeams(ctx, r.pool, teamNames)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return teams, nil
+}
+
+// CreateTeam creates a team with the given members, who join with the member
+// role. An archived team of the same name is restored instead; an active one
+// yields int_errors.ErrTeamExists.
+func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string, memberIDs []string) (*model.Team, error) {
+	const op = "TeamRepository.CreateTeam"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx, `
+		INSERT INTO teams (team_name) VALUES ($1)
+		ON CONFLICT (team_name) DO UPDATE SET archived_at = NULL
+		WHERE teams.archived_at IS NOT NULL
+	`, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert team: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, int_errors.ErrTeamExists
+	}
+
+	if err := insertMembers(ctx, tx, teamName, memberIDs); err != nil {
+		return nil, err
+	}
 
+	teams, err := selectTeams(ctx, tx, []string{teamName})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return teams[0], nil
+}
+
+// UpdateMembers applies the patch to the member list of an active team. New
+// members join with the member role, remaining ones keep theirs, and removed
+// members leave as described for removeMembers.
+func (r *TeamRepository) UpdateMembers(ctx context.Context, teamName string, patch model.MemberPatch) (*model.Team, error) {
+	const op = "TeamRepository.UpdateMembers"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockTeamMembers(ctx, tx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := patch.Apply(current)
+
+	var added, removed []string
+	for _, id := range desired {
+		if !slices.Contains(current, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range current {
+		if !slices.Contains(desired, id) {
+			removed = append(removed, id)
+		}
+	}
+
+	if err := removeMembers(ctx, tx, teamName, removed); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := insertMembers(ctx, tx, teamName, added); err != nil {
+		return nil, err
+	}
+
+	teams, err := selectTeams(ctx, tx, []string{teamName})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return teams[0], nil
+}
+
+// ArchiveTeam removes every member from the team and archives it. The team
+// row stays, so that its pull requests keep their team.
+func (r *TeamRepository) ArchiveTeam(ctx context.Context, teamName string) error {
+	const op = "TeamRepository.ArchiveTeam"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockTeamMembers(ctx, tx, teamName)
+	if err != nil {
+		return err
+	}
+
+	if err := removeMembers(ctx, tx, teamName, current); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE teams SET archived_at = NOW() WHERE team_name = $1`, teamName); err != nil {
+		return fmt.Errorf("%s: archive team: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// lockTeamMembers locks an active team against concurrent member changes and
+// returns its member ids.
+func lockTeamMembers(ctx context.Context, tx pgx.Tx, teamName string) ([]string, error) {
+	var found bool
+	err := tx.QueryRow(ctx, `
+		SELECT true FROM teams WHERE team_name = $1 AND archived_at IS NULL FOR UPDATE
+	`, teamName).Scan(&found)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("lock team %s: %w", teamName, err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT user_id FROM team_memberships WHERE team_name = $1 ORDER BY user_id`, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("select members of %s: %w", teamName, err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan member of %s: %w", teamName, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// insertMembers adds memberships with the default role; an unknown user
+// yields int_errors.ErrUserNotFound.
+func insertMembers(ctx context.Context, tx pgx.Tx, teamName string, userIDs []string) error {
+	for _, id := range userIDs {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO team_memberships (user_id, team_name) VALUES ($1, $2)
+			ON CONFLICT (user_id, team_name) DO NOTHING
+		`, id, teamName)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+				return int_errors.ErrUserNotFound
+			}
+			return fmt.Errorf("insert membership %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// removeMembers takes users off the team. Users whose primary team it was
+// move to the first other team they belong to, if any. Their reviews on the
+// team's open pull requests are handed over to the remaining members.
+func removeMembers(ctx context.Context, tx pgx.Tx, teamName string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT pr.pull_request_id, pr.author_id, prr.reviewer_id
+		FROM pull_requests pr
+		JOIN pr_reviewers prr ON prr.pull_request_id = pr.pull_request_id
+		JOIN users a ON a.user_id = pr.author_id
+		WHERE pr.status = 'OPEN'
+		  AND COALESCE(pr.team_name, a.team_name) = $1
+		  AND prr.reviewer_id = ANY($2)
+		ORDER BY pr.pull_request_id, prr.reviewer_id
+		FOR UPDATE OF pr
+	`, teamName, userIDs)
+	if err != nil {
+		return fmt.Errorf("select open reviews: %w", err)
+	}
+	var reviews []model.AffectedReview
+	for rows.Next() {
+		rv := model.AffectedReview{TeamName: teamName}
+		if err := rows.Scan(&rv.PRID, &rv.AuthorID, &rv.ReviewerID); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan open review: %w", err)
+		}
+		reviews = append(reviews, rv)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("select open reviews: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM team_memberships WHERE team_name = $1 AND user_id = ANY($2)`, teamName, userIDs)
+	if err != nil {
+		return fmt.Errorf("delete memberships: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users u
+		SET team_name = (SELECT MIN(m.team_name) FROM team_memberships m WHERE m.user_id = u.user_id)
+		WHERE u.user_id = ANY($2)
+		  AND u.team_name = $1
+		  AND EXISTS(SELECT 1 FROM team_memberships m WHERE m.user_id = u.user_id)
+	`, teamName, userIDs)
+	if err != nil {
+		return fmt.Errorf("move primary team: %w", err)
+	}
+
+	if _, err := handOverReviews(ctx, tx, reviews); err != nil {
+		return err
+	}
+	return nil
+}
+
+func selectTeams(ctx context.Context, q querier, teamNames []string) ([]*model.Team, error) {
+	rows, err := q.Query(ctx, `
+		SELECT t.team_name, u.user_id, u.username, m.role, m.is_active AND u.is_active
+		FROM teams t
+		LEFT JOIN team_memberships m ON m.team_name = t.team_name
+		LEFT JOIN users u ON u.user_id = m.user_id
+		WHERE t.archived_at IS NULL
+		  AND ($1::text[] IS NULL OR t.team_name = ANY($1))
+		ORDER BY t.team_name, u.username
+	`, teamNames)
+	if err != nil {
+		return nil, fmt.Errorf("select teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []*model.Team
+	for rows.Next() {
+		var (
+			teamName         string
+			userID, username *string
+			role             *string
+			isActive         *bool
+		)
+		if err := rows.Scan(&teamName, &userID, &username, &role, &isActive); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+
+		if len(teams) == 0 || teams[len(teams)-1].TeamName != teamName {
+			teams = append(teams, &model.Team{TeamName: teamName, Members: []*model.TeamMember{}})
+		}
+		if userID == nil {
+			continue
+		}
+
+		team := teams[len(teams)-1]
+		team.Members = append(team.Members, &model.TeamMember{
+			UserID:   *userID,
+			Username: *username,
+			Role:     *role,
+			IsActive: *isActive,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select teams: %w", err)
+	}
+
+	return teams, nil
+}
+
+// SetParent attaches teamName to parentTeam, or detaches it when parentTeam is
+// nil. Changes to the hierarchy are serialised with an advisory lock so that
+// two concurrent calls cannot build a cycle between them.
+func (r *TeamRepository) SetParent(ctx context.Context, teamName string, parentTeam *string) (*model.TeamNode, error) {
+	const op = "TeamRepository.SetParent"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('team_hierarchy'))`); err != nil {
+		return nil, fmt.Errorf("%s: lock hierarchy: %w", op, err)
+	}
+
+	if err := teamExists(ctx, tx, teamName); err != nil {
+		return nil, err
+	}
+
+	if parentTeam != nil {
+		if err := teamExists(ctx, tx, *parentTeam); err != nil {
+			return nil, err
+		}
+
+		var inSubtree bool
+		err = tx.QueryRow(ctx, `
+			WITH RECURSIVE subtree AS (
+				SELECT team_name FROM teams WHERE team_name = $1
+				UNION
+				SELECT t.team_name FROM teams t JOIN subtree s ON t.parent_team = s.team_name
+			)
+			SELECT EXISTS(SELECT 1 FROM subtree WHERE team_name = $2)
+		`, teamName, *parentTeam).Scan(&inSubtree)
 		if err != nil {
-			return nil, fmt.Errorf("%s: upsert user %s: %w", op, member.ID, err)
+			return nil, fmt.Errorf("%s: check cycle: %w", op, err)
+		}
+		if inSubtree {
+			return nil, int_errors.ErrTeamHierarchyCycle
 		}
 	}
 
+	_, err = tx.Exec(ctx, `UPDATE teams SET parent_team = $2 WHERE team_name = $1`, teamName, parentTeam)
+	if err != nil {
+		return nil, fmt.Errorf("%s: update parent: %w", op, err)
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("%s: commit: %w", op, err)
 	}
 
-	return team, nil
+	return &model.TeamNode{TeamName: teamName, ParentTeam: parentTeam}, nil
+}
+
+func (r *TeamRepository) GetReviewSettings(ctx context.Context, teamName string) (*model.ReviewSettings, error) {
+	const op = "TeamRepository.GetReviewSettings"
+
+	settings := &model.ReviewSettings{TeamName: teamName}
+	err := r.pool.QueryRow(ctx, `
+		SELECT review_strategy, pairing_diversity, diversity_lookback_days, diversity_lookback_prs
+		FROM teams
+		WHERE team_name = $1
+	`, teamName).Scan(&settings.Strategy, &settings.PairingDiversity, &settings.LookbackDays, &settings.LookbackPRs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("%s: select settings: %w", op, err)
+	}
+	return settings, nil
+}
+
+func (r *TeamRepository) SetReviewSettings(ctx context.Context, settings *model.ReviewSettings) (*model.ReviewSettings, error) {
+	const op = "TeamRepository.SetReviewSettings"
+
+	res, err := r.pool.Exec(ctx, `
+		UPDATE teams
+		SET review_strategy = $2, pairing_diversity = $3, diversity_lookback_days = $4, diversity_lookback_prs = $5
+		WHERE team_name = $1
+	`, settings.TeamName, settings.Strategy, settings.PairingDiversity, settings.LookbackDays, settings.LookbackPRs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: update settings: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, int_errors.ErrTeamNotFound
+	}
+	return settings, nil
+}
+
+func (r *TeamRepository) GetSubtree(ctx context.Context, teamName string) (*model.TeamNode, error) {
+	const op = "TeamRepository.GetSubtree"
+
+	rows, err := r.pool.Query(ctx, `
+		WITH RECURSIVE subtree AS (
+			SELECT team_name, parent_team, ARRAY[team_name] AS path
+			FROM teams
+			WHERE team_name = $1
+			UNION ALL
+			SELECT t.team_name, t.parent_team, s.path || t.team_name
+			FROM teams t
+			JOIN subtree s ON t.parent_team = s.team_name
+		)
+		SELECT team_name, parent_team FROM subtree ORDER BY path
+	`, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	// Rows come in depth-first order, so every parent is seen before its children.
+	nodes := make(map[string]*model.TeamNode)
+	var root *model.TeamNode
+	for rows.Next() {
+		node := &model.TeamNode{Children: []*model.TeamNode{}}
+		if err := rows.Scan(&node.TeamName, &node.ParentTeam); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		nodes[node.TeamName] = node
+
+		if root == nil {
+			root = node
+			continue
+		}
+		parent := nodes[*node.ParentTeam]
+		parent.Children = append(parent.Children, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	if root == nil {
+		return nil, int_errors.ErrTeamNotFound
+	}
+
+	return root, nil
+}
+
+// GetMemberRoles returns the user's role in every team they belong to.
+func (r *TeamRepository) GetMemberRoles(ctx context.Context, userID string) (map[string]string, error) {
+	const op = "TeamRepository.GetMemberRoles"
+
+	rows, err := r.pool.Query(ctx, `SELECT team_name, role FROM team_memberships WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	roles := make(map[string]string)
+	for rows.Next() {
+		var teamName, role string
+		if err := rows.Scan(&teamName, &role); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		roles[teamName] = role
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+// RenameTeam relies on ON UPDATE CASCADE to carry the new name over to
+// members, memberships, PRs and child units.
+func (r *TeamRepository) RenameTeam(ctx context.Context, teamName, newTeamName string) (*model.Team, error) {
+	const op = "TeamRepository.RenameTeam"
+
+	res, err := r.pool.Exec(ctx, `UPDATE teams SET team_name = $2 WHERE team_name = $1`, teamName, newTeamName)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return nil, int_errors.ErrTeamExists
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, int_errors.ErrTeamNotFound
+	}
+
+	team, err := r.GetTeam(ctx, newTeamName)
+	if errors.Is(err, int_errors.ErrTeamNotFound) {
+		return &model.Team{TeamName: newTeamName}, nil
+	}
+	return team, err
 }
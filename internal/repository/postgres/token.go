@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTokenRepository(pool *pgxpool.Pool) *TokenRepository {
+	return &TokenRepository{pool: pool}
+}
+
+func (r *TokenRepository) CreateToken(ctx context.Context, token *model.APIToken, tokenHash string) (*model.APIToken, error) {
+	const op = "TokenRepository.CreateToken"
+
+	var created model.APIToken
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO api_tokens (token_id, name, user_id, token_hash, scopes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING token_id, name, user_id, scopes, created_at, revoked_at
+	`, token.ID, token.Name, token.UserID, tokenHash, token.Scopes).Scan(
+		&created.ID, &created.Name, &created.UserID, &created.Scopes, &created.CreatedAt, &created.RevokedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return nil, int_errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: insert token: %w", op, err)
+	}
+
+	return &created, nil
+}
+
+// UpsertToken stores a token under a fixed ID, replacing its secret and
+// scopes. It is used for tokens bootstrapped from the config file, which runs
+// on every start, so a token revoked through the API stays revoked.
+func (r *TokenRepository) UpsertToken(ctx context.Context, token *model.APIToken, tokenHash string) (*model.APIToken, error) {
+	const op = "TokenRepository.UpsertToken"
+
+	var upserted model.APIToken
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO api_tokens (token_id, name, token_hash, scopes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token_id)
+		DO UPDATE SET
+			name = EXCLUDED.name,
+			token_hash = EXCLUDED.token_hash,
+			scopes = EXCLUDED.scopes
+		RETURNING token_id, name, user_id, scopes, created_at, revoked_at
+	`, token.ID, token.Name, tokenHash, token.Scopes).Scan(
+		&upserted.ID, &upserted.Name, &upserted.UserID, &upserted.Scopes, &upserted.CreatedAt, &upserted.RevokedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: upsert token: %w", op, err)
+	}
+
+	return &upserted, nil
+}
+
+func (r *TokenRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	const op = "TokenRepository.GetTokenByHash"
+
+	var token model.APIToken
+	err := r.pool.QueryRow(ctx, `
+		SELECT token_id, name, user_id, scopes, created_at, revoked_at
+		FROM api_tokens
+		WHERE token_hash = $1
+	`, tokenHash).Scan(&token.ID, &token.Name, &token.UserID, &token.Scopes, &token.CreatedAt, &token.RevokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrTokenNotFound
+		}
+		return nil, fmt.Errorf("%s: select token: %w", op, err)
+	}
+
+	return &token, nil
+}
+
+func (r *TokenRepository) RevokeToken(ctx context.Context, tokenID string) (*model.APIToken, error) {
+	const op = "TokenRepository.RevokeToken"
+
+	var token model.APIToken
+	err := r.pool.QueryRow(ctx, `
+		UPDATE api_tokens
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE token_id = $1
+		RETURNING token_id, name, user_id, scopes, created_at, revoked_at
+	`, tokenID).Scan(&token.ID, &token.Name, &token.UserID, &token.Scopes, &token.CreatedAt, &token.RevokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrTokenNotFound
+		}
+		return nil, fmt.Errorf("%s: revoke token: %w", op, err)
+	}
+
+	return &token, nil
+}
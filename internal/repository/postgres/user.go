@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
 	"avito-pr-service/internal/model"
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -77,3 +79,423 @@ func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActiv
 
 	return &user, nil
 }
+
+func (r *UserRepository) SetMembershipsActive(ctx context.Context, userID string, teamNames []string, isActive bool) (*model.User, error) {
+	const op = "UserRepository.SetMembershipsActive"
+
+	_, err := r.pool.Exec(ctx, `
+		UPDATE team_memberships
+		SET is_active = $3
+		WHERE user_id = $1 AND team_name = ANY($2)
+	`, userID, teamNames, isActive)
+	if err != nil {
+		return nil, fmt.Errorf("%s: update query failed: %w", op, err)
+	}
+
+	return r.GetUser(ctx, userID)
+}
+
+func (r *UserRepository) GetUser(ctx context.Context, userID string) (*model.User, error) {
+	const op = "UserRepository.GetUser"
+
+	var user model.User
+	err := r.pool.QueryRow(ctx, `
+		SELECT user_id, username, team_name, is_active
+		FROM users
+		WHERE user_id = $1
+	`, userID).Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: select user: %w", op, err)
+	}
+
+	user.Memberships, err = selectMemberships(ctx, r.pool, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, user *model.User) (*model.User, error) {
+	const op = "UserRepository.CreateUser"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := teamExists(ctx, tx, user.TeamName); err != nil {
+		return nil, err
+	}
+
+	res, err := tx.Exec(ctx, `
+		INSERT INTO users (user_id, username, team_name, is_active)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO NOTHING
+	`, user.ID, user.Username, user.TeamName, user.IsActive)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert user: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, int_errors.ErrUserExists
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO team_memberships (user_id, team_name) VALUES ($1, $2)
+	`, user.ID, user.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert membership: %w", op, err)
+	}
+	user.Memberships = []model.TeamMembership{{TeamName: user.TeamName, Role: model.RoleMember, IsActive: true}}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) UpdateUser(ctx context.Context, req model.UpdateUserRequest) (*model.User, error) {
+	const op = "UserRepository.UpdateUser"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if req.TeamName != nil {
+		if err := teamExists(ctx, tx, *req.TeamName); err != nil {
+			return nil, err
+		}
+	}
+
+	var oldTeam string
+	err = tx.QueryRow(ctx, `SELECT team_name FROM users WHERE user_id = $1 FOR UPDATE`, req.ID).Scan(&oldTeam)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: select user: %w", op, err)
+	}
+
+	var user model.User
+	err = tx.QueryRow(ctx, `
+		UPDATE users
+		SET username = COALESCE($2, username),
+		    team_name = COALESCE($3, team_name)
+		WHERE user_id = $1
+		RETURNING user_id, username, team_name, is_active
+	`, req.ID, req.Username, req.TeamName).Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive)
+	if err != nil {
+		return nil, fmt.Errorf("%s: update user: %w", op, err)
+	}
+
+	// Changing the primary team moves the user: the membership in the old
+	// primary team is replaced by one in the new team.
+	if user.TeamName != oldTeam {
+		_, err = tx.Exec(ctx, `DELETE FROM team_memberships WHERE user_id = $1 AND team_name = $2`, user.ID, oldTeam)
+		if err != nil {
+			return nil, fmt.Errorf("%s: delete old membership: %w", op, err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO team_memberships (user_id, team_name) VALUES ($1, $2)
+			ON CONFLICT (user_id, team_name) DO NOTHING
+		`, user.ID, user.TeamName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: insert membership: %w", op, err)
+		}
+	}
+
+	user.Memberships, err = selectMemberships(ctx, tx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return &user, nil
+}
+
+// DeleteUser removes the user after handing each of their open reviews over to
+// another active member of the PR's team. Reviews with no available
+// replacement are simply dropped. Users that still author open pull requests
+// cannot be deleted, and neither can users who authored or reviewed any
+// merged one: that history stays, so they are to be deactivated instead.
+func (r *UserRepository) DeleteUser(ctx context.Context, userID string) ([]model.ReviewReassignment, error) {
+	const op = "UserRepository.DeleteUser"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: start transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `SELECT user_id FROM users WHERE user_id = $1 FOR UPDATE`, userID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, int_errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: select user: %w", op, err)
+	}
+
+	var hasOpen bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM pull_requests WHERE author_id = $1 AND status = 'OPEN')
+	`, userID).Scan(&hasOpen)
+	if err != nil {
+		return nil, fmt.Errorf("%s: check authored prs: %w", op, err)
+	}
+	if hasOpen {
+		return nil, int_errors.ErrUserHasOpenPullRequests
+	}
+
+	hasHistory, err := hasReviewHistory(ctx, tx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if hasHistory {
+		return nil, int_errors.ErrUserHasHistory
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT pr.pull_request_id, pr.author_id, COALESCE(pr.team_name, a.team_name)
+		FROM pull_requests pr
+		JOIN users a ON a.user_id = pr.author_id
+		JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id
+		WHERE prr.reviewer_id = $1 AND pr.status = 'OPEN'
+		ORDER BY pr.pull_request_id
+		FOR UPDATE OF pr
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select open reviews: %w", op, err)
+	}
+
+	type openReview struct {
+		prID     string
+		authorID string
+		teamName string
+	}
+	var reviews []openReview
+	for rows.Next() {
+		var rv openReview
+		if err := rows.Scan(&rv.prID, &rv.authorID, &rv.teamName); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: scan open review: %w", op, err)
+		}
+		reviews = append(reviews, rv)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	reviewedIDs := make([]string, len(reviews))
+	reviewedTeams := make([]string, len(reviews))
+	for i, rv := range reviews {
+		reviewedIDs[i] = rv.prID
+		reviewedTeams[i] = rv.teamName
+	}
+	counters := counterDelta{}
+	if err := counters.count(ctx, tx, reviewedIDs, -1); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := lockReviewCursors(ctx, tx, reviewedTeams); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reassignments := make([]model.ReviewReassignment, 0, len(reviews))
+	for _, rv := range reviews {
+		_, err := tx.Exec(ctx, `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`, rv.prID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: delete review %s: %w", op, rv.prID, err)
+		}
+
+		current, err := selectReviewerIDs(ctx, tx, rv.prID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: get reviewers of %s: %w", op, rv.prID, err)
+		}
+
+		candidates, err := selectTeamReviewers(ctx, tx, rv.teamName, rv.prID, rv.authorID, append([]string{userID, rv.authorID}, current...), 1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: select replacement for %s: %w", op, rv.prID, err)
+		}
+		if len(candidates) == 0 {
+			logger.FromContext(ctx).Warn("no replacement reviewer for deleted user",
+				slog.String("user_id", userID),
+				slog.String("pr_id", rv.prID),
+			)
+			if err := logReassignment(ctx, tx, rv.prID, userID, nil); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			reassignments = append(reassignments, model.ReviewReassignment{PRID: rv.prID})
+			continue
+		}
+		newReviewerID := candidates[0]
+
+		_, err = tx.Exec(ctx, `INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`, rv.prID, newReviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: insert replacement for %s: %w", op, rv.prID, err)
+		}
+		if err := logReassignment(ctx, tx, rv.prID, userID, &newReviewerID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		reassignments = append(reassignments, model.ReviewReassignment{PRID: rv.prID, ReplacedBy: &newReviewerID})
+	}
+
+	// Reviews the user leaves behind are counted again; their own counters
+	// go with the user row.
+	if err := counters.count(ctx, tx, reviewedIDs, 1); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := counters.apply(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("%s: delete user: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return reassignments, nil
+}
+
+// hasReviewHistory reports whether the user authored any PR or reviews a
+// merged one. Reviews of open PRs are not history: they can be handed over.
+func hasReviewHistory(ctx context.Context, q querier, userID string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM pull_requests WHERE author_id = $1)
+		    OR EXISTS(
+		        SELECT 1
+		        FROM pr_reviewers prr
+		        JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		        WHERE prr.reviewer_id = $1 AND pr.status <> 'OPEN'
+		    )
+	`, userID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check review history: %w", err)
+	}
+	return exists, nil
+}
+
+func teamExists(ctx context.Context, q querier, teamName string) error {
+	var exists bool
+	err := q.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`, teamName).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check team %s: %w", teamName, err)
+	}
+	if !exists {
+		return int_errors.ErrTeamNotFound
+	}
+	return nil
+}
+
+func selectMemberships(ctx context.Context, q querier, userID string) ([]model.TeamMembership, error) {
+	rows, err := q.Query(ctx, `
+		SELECT team_name, role, is_active
+		FROM team_memberships
+		WHERE user_id = $1
+		ORDER BY team_name
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("select memberships: %w", err)
+	}
+	defer rows.Close()
+
+	memberships := []model.TeamMembership{}
+	for rows.Next() {
+		var m model.TeamMembership
+		if err := rows.Scan(&m.TeamName, &m.Role, &m.IsActive); err != nil {
+			return nil, fmt.Errorf("scan membership: %w", err)
+		}
+		memberships = append(memberships, m)
+	}
+	return memberships, rows.Err()
+}
+
+// ListUsers returns one page of users ordered by id together with the number
+// of users matching the filter.
+func (r *UserRepository) ListUsers(ctx context.Context, filter model.UserFilter, offset, limit int) ([]*model.User, int, error) {
+	const op = "UserRepository.ListUsers"
+
+	const where = `
+		WHERE ($1::text IS NULL OR user_id = $1)
+		  AND ($2::text IS NULL OR username = $2)
+		  AND ($3::bool IS NULL OR is_active = $3)
+	`
+
+	var total int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where,
+		filter.UserID, filter.Username, filter.IsActive).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: count users: %w", op, err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT user_id, username, team_name, is_active
+		FROM users`+where+`
+		ORDER BY user_id
+		OFFSET $4 LIMIT $5
+	`, filter.UserID, filter.Username, filter.IsActive, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: select users: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := []*model.User{}
+	byID := make(map[string]*model.User)
+	ids := []string{}
+	for rows.Next() {
+		user := &model.User{Memberships: []model.TeamMembership{}}
+		if err := rows.Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan user: %w", op, err)
+		}
+		users = append(users, user)
+		byID[user.ID] = user
+		ids = append(ids, user.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+	rows.Close()
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT user_id, team_name, role, is_active
+		FROM team_memberships
+		WHERE user_id = ANY($1)
+		ORDER BY user_id, team_name
+	`, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: select memberships: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			m      model.TeamMembership
+		)
+		if err := rows.Scan(&userID, &m.TeamName, &m.Role, &m.IsActive); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan membership: %w", op, err)
+		}
+		user := byID[userID]
+		user.Memberships = append(user.Memberships, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: iteration error: %w", op, err)
+	}
+
+	return users, total, nil
+}
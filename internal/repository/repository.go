@@ -6,21 +6,69 @@ import (
 )
 
 type PRRepository interface {
-	CreatePR(ctx context.Context, prID, title, authorID string) (*model.PullRequest, error)
-	MergePR(ctx context.Context, prID string) (*model.PullRequest, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (*model.PullRequest, string, error)
+	GetPR(ctx context.Context, prID string) (*model.PullRequest, error)
+	CreatePR(ctx context.Context, prID, title, authorID, teamName string) (*model.PullRequest, error)
+	// MergePR reports whether this call moved the PR to MERGED.
+	MergePR(ctx context.Context, prID string) (*model.PullRequest, bool, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID string, escalate bool) (*model.PullRequest, string, error)
 }
 
 type UserRepository interface {
 	GetUserReviews(ctx context.Context, userID string) ([]model.PullRequest, error)
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*model.User, error)
+	// SetMembershipsActive sets the activity of the user's memberships in
+	// teamNames only; the user's global flag is left as is.
+	SetMembershipsActive(ctx context.Context, userID string, teamNames []string, isActive bool) (*model.User, error)
+	GetUser(ctx context.Context, userID string) (*model.User, error)
+	CreateUser(ctx context.Context, user *model.User) (*model.User, error)
+	UpdateUser(ctx context.Context, req model.UpdateUserRequest) (*model.User, error)
+	DeleteUser(ctx context.Context, userID string) ([]model.ReviewReassignment, error)
+	ListUsers(ctx context.Context, filter model.UserFilter, offset, limit int) ([]*model.User, int, error)
 }
 
 type TeamRepository interface {
 	GetTeam(ctx context.Context, teamName string) (*model.Team, error)
 	AddTeam(ctx context.Context, team *model.Team) (*model.Team, error)
+	SetParent(ctx context.Context, teamName string, parentTeam *string) (*model.TeamNode, error)
+	GetSubtree(ctx context.Context, teamName string) (*model.TeamNode, error)
+	GetReviewSettings(ctx context.Context, teamName string) (*model.ReviewSettings, error)
+	SetReviewSettings(ctx context.Context, settings *model.ReviewSettings) (*model.ReviewSettings, error)
+	GetMemberRoles(ctx context.Context, userID string) (map[string]string, error)
+	RenameTeam(ctx context.Context, teamName, newTeamName string) (*model.Team, error)
+	// ImportTeams upserts all teams atomically; with dryRun nothing is kept.
+	ImportTeams(ctx context.Context, teams []*model.Team, dryRun bool) (*model.ImportResult, error)
+	// ListTeams returns all active teams, or only the named ones.
+	ListTeams(ctx context.Context, teamNames ...string) ([]*model.Team, error)
+	CreateTeam(ctx context.Context, teamName string, memberIDs []string) (*model.Team, error)
+	UpdateMembers(ctx context.Context, teamName string, patch model.MemberPatch) (*model.Team, error)
+	ArchiveTeam(ctx context.Context, teamName string) error
+	OrgState(ctx context.Context) (*model.OrgState, error)
+	// ApplySync re-plans inside its transaction; a non-empty planID must match.
+	ApplySync(ctx context.Context, desired []*model.Team, opts model.SyncOptions, planID string) (*model.SyncResult, error)
 }
 
 type StatisticsRepository interface {
-	GetStatistics(ctx context.Context) (*model.Statistics, error)
+	GetStatistics(ctx context.Context, filter model.StatisticsFilter, prPage, reviewerPage model.Page) (*model.Statistics, error)
+	GetSubtreeStatistics(ctx context.Context, rootTeam string) (*model.SubtreeStatistics, error)
+	GetTimingStatistics(ctx context.Context, rng model.TimeRange) (*model.TimingStatistics, error)
+	GetStatisticsHistory(ctx context.Context, q model.HistoryQuery) (*model.StatisticsHistory, error)
+	GetReviewLoads(ctx context.Context, teamName *string, rng model.TimeRange) ([]model.TeamLoad, error)
+	GetReviewPairs(ctx context.Context, teamName string, rng model.TimeRange) ([]model.PairMember, []model.ReviewPair, error)
+	// RebuildCounters recomputes the counters behind GetStatistics.
+	RebuildCounters(ctx context.Context) (*model.CounterCheck, error)
+}
+
+type TokenRepository interface {
+	CreateToken(ctx context.Context, token *model.APIToken, tokenHash string) (*model.APIToken, error)
+	UpsertToken(ctx context.Context, token *model.APIToken, tokenHash string) (*model.APIToken, error)
+	GetTokenByHash(ctx context.Context, tokenHash string) (*model.APIToken, error)
+	RevokeToken(ctx context.Context, tokenID string) (*model.APIToken, error)
+}
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	// PoolUsage returns the number of acquired connections and the pool size.
+	PoolUsage() (acquired, max int32)
+	// MigrationVersion reads the golang-migrate schema_migrations table.
+	MigrationVersion(ctx context.Context) (version int64, dirty bool, err error)
 }
@@ -0,0 +1,125 @@
+package roster
+
+import (
+	"avito-pr-service/internal/model"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{"team_name", "user_id", "username", "role", "is_active"}
+
+func readCSV(r io.Reader) ([]entry, []LineError, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, []LineError{{Line: 1, Message: "missing header row"}}, nil
+	}
+	if err != nil {
+		return nil, []LineError{csvLineError(err)}, nil
+	}
+
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var missing []string
+	for _, name := range csvHeader[:3] {
+		if _, ok := cols[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		msg := "header is missing column(s) " + strings.Join(missing, ", ")
+		return nil, []LineError{{Line: 1, Message: msg}}, nil
+	}
+
+	var (
+		entries []entry
+		errs    []LineError
+	)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// A malformed quote leaves the reader out of sync with the rows,
+			// so anything after it would be reported against the wrong line.
+			errs = append(errs, csvLineError(err))
+			break
+		}
+
+		line, _ := cr.FieldPos(0)
+		field := func(name string) string {
+			i, ok := cols[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		e := entry{
+			line:     line,
+			teamName: field("team_name"),
+			userID:   field("user_id"),
+			username: field("username"),
+			role:     field("role"),
+			isActive: true,
+		}
+		if e.role == "" {
+			e.role = model.RoleMember
+		}
+		if v := field("is_active"); v != "" {
+			active, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, LineError{Line: line, Message: fmt.Sprintf("is_active must be true or false, got %q", v)})
+				continue
+			}
+			e.isActive = active
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, errs, nil
+}
+
+func csvLineError(err error) LineError {
+	var pe *csv.ParseError
+	if errors.As(err, &pe) {
+		return LineError{Line: pe.Line, Message: pe.Err.Error()}
+	}
+	return LineError{Message: err.Error()}
+}
+
+func writeCSV(w io.Writer, teams []*model.Team) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, team := range teams {
+		if len(team.Members) == 0 {
+			if err := cw.Write([]string{team.TeamName, "", "", "", ""}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, m := range team.Members {
+			record := []string{team.TeamName, m.UserID, m.Username, m.Role, strconv.FormatBool(m.IsActive)}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,70 @@
+package roster
+
+import (
+	"avito-pr-service/internal/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []entry
+		errs  []LineError
+	}{
+		{
+			name:  "optional columns default",
+			input: "user_id, Team_Name ,username\nu1,backend, Alice \n",
+			want:  []entry{{line: 2, teamName: "backend", userID: "u1", username: "Alice", role: model.RoleMember, isActive: true}},
+		},
+		{
+			name:  "bare team",
+			input: "team_name,user_id,username,role,is_active\nfrontend,,,,\n",
+			want:  []entry{{line: 2, teamName: "frontend", role: model.RoleMember, isActive: true}},
+		},
+		{
+			name:  "short row",
+			input: "team_name,user_id,username,role,is_active\nbackend,u1,Alice\n",
+			want:  []entry{{line: 2, teamName: "backend", userID: "u1", username: "Alice", role: model.RoleMember, isActive: true}},
+		},
+		{
+			name:  "empty input",
+			input: "",
+			errs:  []LineError{{Line: 1, Message: "missing header row"}},
+		},
+		{
+			name:  "missing columns",
+			input: "team_name,role\nbackend,lead\n",
+			errs:  []LineError{{Line: 1, Message: "header is missing column(s) user_id, username"}},
+		},
+		{
+			name:  "bad is_active",
+			input: "team_name,user_id,username,is_active\nbackend,u1,Alice,yes\nbackend,u2,Bob,false\n",
+			want:  []entry{{line: 3, teamName: "backend", userID: "u2", username: "Bob", role: model.RoleMember}},
+			errs:  []LineError{{Line: 2, Message: `is_active must be true or false, got "yes"`}},
+		},
+		{
+			name:  "malformed quote stops reading",
+			input: "team_name,user_id,username\nbackend,u1,Alice\nbackend,u2,\"Bob\nbackend,u3,Carol\n",
+			want:  []entry{{line: 2, teamName: "backend", userID: "u1", username: "Alice", role: model.RoleMember, isActive: true}},
+			errs:  []LineError{{Line: 4, Message: `extraneous or missing " in quoted-field`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, errs, err := readCSV(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("readCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("readCSV() entries = %+v, want %+v", entries, tt.want)
+			}
+			if !reflect.DeepEqual(errs, tt.errs) {
+				t.Errorf("readCSV() errors = %+v, want %+v", errs, tt.errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Package roster reads and writes team rosters - teams with their members,
+// roles and active flags - in the CSV and YAML formats used by bulk import
+// and export.
+//
+// CSV has one row per membership:
+//
+//	team_name,user_id,username,role,is_active
+//	backend,u1,Alice,lead,true
+//	backend,u2,Bob,member,false
+//	frontend,,,,
+//
+// A row with an empty user_id and username declares a team without members.
+// The role and is_active columns are optional and default to member and true.
+//
+// YAML nests members under their team:
+//
+//	teams:
+//	  - team_name: backend
+//	    members:
+//	      - {user_id: u1, username: Alice, role: lead}
+//	      - {user_id: u2, username: Bob, is_active: false}
+package roster
+
+import (
+	"avito-pr-service/internal/model"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat accepts a format name or a media type such as text/csv or
+// application/yaml.
+func ParseFormat(s string) (Format, error) {
+	if mediaType, _, err := mime.ParseMediaType(s); err == nil {
+		s = mediaType
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "csv", "text/csv":
+		return FormatCSV, nil
+	case "yaml", "yml", "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unsupported roster format %q, want csv or yaml", s)
+}
+
+func (f Format) ContentType() string {
+	if f == FormatYAML {
+		return "application/yaml"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// LineError is a problem with one entry of the input. Line is 1-based; it is 0
+// when the input could not be parsed far enough to point at a line.
+type LineError struct {
+	Line    int
+	Message string
+}
+
+// ValidationError lists every invalid entry found in the input.
+type ValidationError struct {
+	Errors []LineError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, le := range e.Errors {
+		msgs[i] = fmt.Sprintf("line %d: %s", le.Line, le.Message)
+	}
+	return "invalid roster: " + strings.Join(msgs, "; ")
+}
+
+// Read parses and validates a roster. Invalid input yields a
+// *ValidationError; teams are returned in the order they first appear.
+func Read(r io.Reader, f Format) ([]*model.Team, error) {
+	var (
+		entries []entry
+		errs    []LineError
+		err     error
+	)
+	switch f {
+	case FormatCSV:
+		entries, errs, err = readCSV(r)
+	case FormatYAML:
+		entries, errs, err = readYAML(r)
+	default:
+		return nil, fmt.Errorf("unsupported roster format %q", f)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	teams, buildErrs := build(entries)
+	errs = append(errs, buildErrs...)
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	return teams, nil
+}
+
+// Write renders teams in the given format. Teams without members are kept so
+// that an export can be imported back unchanged.
+func Write(w io.Writer, f Format, teams []*model.Team) error {
+	switch f {
+	case FormatCSV:
+		return writeCSV(w, teams)
+	case FormatYAML:
+		return writeYAML(w, teams)
+	}
+	return fmt.Errorf("unsupported roster format %q", f)
+}
+
+// entry is one membership (or a bare team) as read from the input, before
+// cross-entry validation.
+type entry struct {
+	line     int
+	teamName string
+	userID   string
+	username string
+	role     string
+	isActive bool
+}
+
+func build(entries []entry) ([]*model.Team, []LineError) {
+	type seenUser struct {
+		username string
+		line     int
+	}
+
+	var (
+		errs    []LineError
+		teams   []*model.Team
+		byName  = make(map[string]*model.Team)
+		users   = make(map[string]seenUser)
+		members = make(map[[2]string]int)
+	)
+
+	for _, e := range entries {
+		fail := func(format string, args ...any) {
+			errs = append(errs, LineError{Line: e.line, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if e.teamName == "" {
+			fail("team_name must not be empty")
+			continue
+		}
+
+		team, ok := byName[e.teamName]
+		if !ok {
+			team = &model.Team{TeamName: e.teamName, Members: []*model.TeamMember{}}
+			byName[e.teamName] = team
+			teams = append(teams, team)
+		}
+
+		if e.userID == "" && e.username == "" {
+			continue
+		}
+		if e.userID == "" || e.username == "" {
+			fail("user_id and username must both be set or both be empty")
+			continue
+		}
+		if !model.IsValidRole(e.role) {
+			fail("unknown role %q, want member, lead or admin", e.role)
+			continue
+		}
+
+		if prev, ok := users[e.userID]; ok && prev.username != e.username {
+			fail("user %s is named %q here but %q on line %d", e.userID, e.username, prev.username, prev.line)
+			continue
+		}
+		users[e.userID] = seenUser{username: e.username, line: e.line}
+
+		key := [2]string{e.teamName, e.userID}
+		if prev, ok := members[key]; ok {
+			fail("user %s is already listed in team %s on line %d", e.userID, e.teamName, prev)
+			continue
+		}
+		members[key] = e.line
+
+		team.Members = append(team.Members, &model.TeamMember{
+			UserID:   e.userID,
+			Username: e.username,
+			Role:     e.role,
+			IsActive: e.isActive,
+		})
+	}
+
+	return teams, errs
+}
@@ -0,0 +1,158 @@
+package roster
+
+import (
+	"avito-pr-service/internal/model"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// wantLineErrors checks that err is a *ValidationError with exactly want.
+func wantLineErrors(t *testing.T, err error, want []LineError) {
+	t.Helper()
+
+	if len(want) == 0 {
+		if err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+		return
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("error = %v, want *ValidationError", err)
+	}
+	if !reflect.DeepEqual(ve.Errors, want) {
+		t.Errorf("errors = %+v, want %+v", ve.Errors, want)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	e := func(line int, team, id, name, role string) entry {
+		return entry{line: line, teamName: team, userID: id, username: name, role: role, isActive: true}
+	}
+
+	tests := []struct {
+		name    string
+		entries []entry
+		want    []*model.Team
+		errs    []LineError
+	}{
+		{
+			name: "teams in order of appearance",
+			entries: []entry{
+				e(2, "backend", "u1", "Alice", model.RoleLead),
+				e(3, "frontend", "", "", model.RoleMember),
+				e(4, "backend", "u2", "Bob", model.RoleMember),
+				e(5, "frontend", "u1", "Alice", model.RoleAdmin),
+			},
+			want: []*model.Team{
+				{TeamName: "backend", Members: []*model.TeamMember{
+					{UserID: "u1", Username: "Alice", Role: model.RoleLead, IsActive: true},
+					{UserID: "u2", Username: "Bob", Role: model.RoleMember, IsActive: true},
+				}},
+				{TeamName: "frontend", Members: []*model.TeamMember{
+					{UserID: "u1", Username: "Alice", Role: model.RoleAdmin, IsActive: true},
+				}},
+			},
+		},
+		{
+			name:    "empty team name",
+			entries: []entry{e(2, "", "u1", "Alice", model.RoleMember)},
+			errs:    []LineError{{Line: 2, Message: "team_name must not be empty"}},
+		},
+		{
+			name: "half-empty rows",
+			entries: []entry{
+				e(2, "backend", "u1", "", model.RoleMember),
+				e(3, "backend", "", "Bob", model.RoleMember),
+			},
+			errs: []LineError{
+				{Line: 2, Message: "user_id and username must both be set or both be empty"},
+				{Line: 3, Message: "user_id and username must both be set or both be empty"},
+			},
+		},
+		{
+			name:    "unknown role",
+			entries: []entry{e(2, "backend", "u1", "Alice", "owner")},
+			errs:    []LineError{{Line: 2, Message: `unknown role "owner", want member, lead or admin`}},
+		},
+		{
+			name: "name conflict",
+			entries: []entry{
+				e(2, "backend", "u1", "Alice", model.RoleMember),
+				e(3, "frontend", "u1", "Alicia", model.RoleMember),
+			},
+			errs: []LineError{{Line: 3, Message: `user u1 is named "Alicia" here but "Alice" on line 2`}},
+		},
+		{
+			name: "duplicate member",
+			entries: []entry{
+				e(2, "backend", "u1", "Alice", model.RoleMember),
+				e(3, "backend", "u1", "Alice", model.RoleLead),
+			},
+			errs: []LineError{{Line: 3, Message: "user u1 is already listed in team backend on line 2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, errs := build(tt.entries)
+			if !reflect.DeepEqual(errs, tt.errs) {
+				t.Fatalf("build() errors = %+v, want %+v", errs, tt.errs)
+			}
+			if tt.want != nil && !reflect.DeepEqual(teams, tt.want) {
+				t.Errorf("build() = %+v, want %+v", teams, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	teams := []*model.Team{
+		{TeamName: "backend", Members: []*model.TeamMember{
+			{UserID: "u1", Username: "Alice", Role: model.RoleLead, IsActive: true},
+			{UserID: "u2", Username: "Bob, Jr.", Role: model.RoleMember, IsActive: false},
+		}},
+		{TeamName: "frontend", Members: []*model.TeamMember{}},
+		{TeamName: "payments", Members: []*model.TeamMember{
+			{UserID: "u1", Username: "Alice", Role: model.RoleAdmin, IsActive: true},
+		}},
+	}
+
+	for _, f := range []Format{FormatCSV, FormatYAML} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, f, teams); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			got, err := Read(&buf, f)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, teams) {
+				t.Errorf("Read(Write()) = %+v, want %+v", got, teams)
+			}
+		})
+	}
+}
+
+func TestReadSortsErrorsByLine(t *testing.T) {
+	// The is_active error comes from the CSV reader and the others from
+	// build, yet they are reported in line order.
+	input := strings.Join([]string{
+		"team_name,user_id,username,role,is_active",
+		"backend,u1,Alice,owner,true",
+		"backend,u2,Bob,member,maybe",
+		",u3,Carol,member,true",
+	}, "\n")
+
+	_, err := Read(strings.NewReader(input), FormatCSV)
+	wantLineErrors(t, err, []LineError{
+		{Line: 2, Message: `unknown role "owner", want member, lead or admin`},
+		{Line: 3, Message: `is_active must be true or false, got "maybe"`},
+		{Line: 4, Message: "team_name must not be empty"},
+	})
+}
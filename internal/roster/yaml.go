@@ -0,0 +1,167 @@
+package roster
+
+import (
+	"avito-pr-service/internal/model"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type yamlDocument struct {
+	Teams []yaml.Node `yaml:"teams"`
+}
+
+type yamlTeamIn struct {
+	TeamName string      `yaml:"team_name"`
+	Members  []yaml.Node `yaml:"members"`
+}
+
+type yamlMemberIn struct {
+	UserID   string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	Role     string `yaml:"role"`
+	IsActive *bool  `yaml:"is_active"`
+}
+
+// readYAML decodes teams and members from their own nodes so that each error
+// points at the line of the offending entry.
+func readYAML(r io.Reader) ([]entry, []LineError, error) {
+	var doc yamlDocument
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, []LineError{{Line: 1, Message: "document is empty"}}, nil
+		}
+		return nil, yamlDocumentErrors(err), nil
+	}
+
+	var (
+		entries []entry
+		errs    []LineError
+	)
+	for i := range doc.Teams {
+		teamNode := &doc.Teams[i]
+
+		var team yamlTeamIn
+		if err := teamNode.Decode(&team); err != nil {
+			errs = append(errs, LineError{Line: teamNode.Line, Message: yamlMessage(err)})
+			continue
+		}
+		teamName := strings.TrimSpace(team.TeamName)
+
+		entries = append(entries, entry{line: teamNode.Line, teamName: teamName})
+
+		for j := range team.Members {
+			memberNode := &team.Members[j]
+
+			var m yamlMemberIn
+			if err := memberNode.Decode(&m); err != nil {
+				errs = append(errs, LineError{Line: memberNode.Line, Message: yamlMessage(err)})
+				continue
+			}
+
+			e := entry{
+				line:     memberNode.Line,
+				teamName: teamName,
+				userID:   strings.TrimSpace(m.UserID),
+				username: strings.TrimSpace(m.Username),
+				role:     strings.TrimSpace(m.Role),
+				isActive: m.IsActive == nil || *m.IsActive,
+			}
+			if e.role == "" {
+				e.role = model.RoleMember
+			}
+			if e.userID == "" && e.username == "" {
+				errs = append(errs, LineError{Line: e.line, Message: "member must have user_id and username"})
+				continue
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, errs, nil
+}
+
+// yamlDocumentErrors splits a document-level error into one LineError per
+// message, recovering the line from texts such as
+// "yaml: line 3: did not find expected key".
+func yamlDocumentErrors(err error) []LineError {
+	msgs := []string{err.Error()}
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		msgs = te.Errors
+	}
+
+	errs := make([]LineError, len(msgs))
+	for i, msg := range msgs {
+		msg = strings.TrimPrefix(msg, "yaml: ")
+		errs[i] = LineError{Message: msg}
+		if rest, ok := strings.CutPrefix(msg, "line "); ok {
+			if num, tail, ok := strings.Cut(rest, ": "); ok {
+				if line, convErr := strconv.Atoi(num); convErr == nil {
+					errs[i] = LineError{Line: line, Message: tail}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// yamlMessage strips the "line N:" prefixes yaml.v3 puts into type errors;
+// the line is reported separately.
+func yamlMessage(err error) string {
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return err.Error()
+	}
+
+	msgs := make([]string, len(te.Errors))
+	for i, msg := range te.Errors {
+		if _, rest, ok := strings.Cut(msg, ": "); ok && strings.HasPrefix(msg, "line ") {
+			msg = rest
+		}
+		msgs[i] = msg
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type yamlTeamOut struct {
+	TeamName string          `yaml:"team_name"`
+	Members  []yamlMemberOut `yaml:"members"`
+}
+
+type yamlMemberOut struct {
+	UserID   string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	Role     string `yaml:"role"`
+	IsActive bool   `yaml:"is_active"`
+}
+
+func writeYAML(w io.Writer, teams []*model.Team) error {
+	out := struct {
+		Teams []yamlTeamOut `yaml:"teams"`
+	}{Teams: make([]yamlTeamOut, 0, len(teams))}
+
+	for _, team := range teams {
+		t := yamlTeamOut{TeamName: team.TeamName, Members: make([]yamlMemberOut, 0, len(team.Members))}
+		for _, m := range team.Members {
+			t.Members = append(t.Members, yamlMemberOut{
+				UserID:   m.UserID,
+				Username: m.Username,
+				Role:     m.Role,
+				IsActive: m.IsActive,
+			})
+		}
+		out.Teams = append(out.Teams, t)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+	return enc.Close()
+}
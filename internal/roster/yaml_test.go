@@ -0,0 +1,79 @@
+package roster
+
+import (
+	"avito-pr-service/internal/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []entry
+		errs  []LineError
+	}{
+		{
+			name: "members with defaults",
+			input: `teams:
+  - team_name: backend
+    members:
+      - {user_id: u1, username: Alice, role: lead}
+      - {user_id: u2, username: Bob, is_active: false}
+  - team_name: frontend
+`,
+			want: []entry{
+				{line: 2, teamName: "backend"},
+				{line: 4, teamName: "backend", userID: "u1", username: "Alice", role: model.RoleLead, isActive: true},
+				{line: 5, teamName: "backend", userID: "u2", username: "Bob", role: model.RoleMember, isActive: false},
+				{line: 6, teamName: "frontend"},
+			},
+		},
+		{
+			name:  "empty document",
+			input: "",
+			errs:  []LineError{{Line: 1, Message: "document is empty"}},
+		},
+		{
+			name:  "teams is not a list",
+			input: "teams: 5\n",
+			errs:  []LineError{{Line: 1, Message: "cannot unmarshal !!int `5` into []yaml.Node"}},
+		},
+		{
+			name: "member without ids",
+			input: `teams:
+  - team_name: backend
+    members:
+      - {role: lead}
+`,
+			want: []entry{{line: 2, teamName: "backend"}},
+			errs: []LineError{{Line: 4, Message: "member must have user_id and username"}},
+		},
+		{
+			name: "wrong type",
+			input: `teams:
+  - team_name: backend
+    members:
+      - {user_id: u1, username: Alice, is_active: maybe}
+`,
+			want: []entry{{line: 2, teamName: "backend"}},
+			errs: []LineError{{Line: 4, Message: "cannot unmarshal !!str `maybe` into bool"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, errs, err := readYAML(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("readYAML() error = %v", err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("readYAML() entries = %+v, want %+v", entries, tt.want)
+			}
+			if !reflect.DeepEqual(errs, tt.errs) {
+				t.Errorf("readYAML() errors = %+v, want %+v", errs, tt.errs)
+			}
+		})
+	}
+}
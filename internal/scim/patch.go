@@ -0,0 +1,342 @@
+package scim
+
+import (
+	"avito-pr-service/internal/model"
+	"slices"
+	"strings"
+)
+
+// Operation is one entry of a PatchOp request. Op is matched
+// case-insensitively, as identity providers differ in how they spell it.
+type Operation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+// UserPatch holds the user attributes a PATCH changes; nil fields are left
+// alone. Attributes the service does not store are ignored. UserID comes from
+// SCIM userName, which is the user's ID here; DisplayName becomes the
+// service's username.
+type UserPatch struct {
+	UserID      *string
+	Active      *bool
+	DisplayName *string
+	Department  *string
+}
+
+// ParseUserPatch folds the operations into a single change. displayName takes
+// precedence over name.formatted as the source of DisplayName.
+func ParseUserPatch(ops []Operation) (*UserPatch, error) {
+	p := &UserPatch{}
+	var displayName, formatted *string
+
+	for _, op := range ops {
+		kind, err := opKind(op.Op)
+		if err != nil {
+			return nil, err
+		}
+
+		values := map[string]any{}
+		obj, isObj := op.Value.(map[string]any)
+		switch {
+		case op.Path == "" && !isObj:
+			return nil, errorf(InvalidValue, "%s without a path needs an object value", op.Op)
+		case isObj:
+			flatten(values, attributeName(op.Path), obj)
+		default:
+			values[attributeName(op.Path)] = op.Value
+		}
+
+		for attr, value := range values {
+			if kind == "remove" && isUserAttribute(attr) {
+				return nil, errorf(Mutability, "attribute %s cannot be removed", attr)
+			}
+
+			switch attr {
+			case "username":
+				s, err := stringValue(attr, value)
+				if err != nil {
+					return nil, err
+				}
+				p.UserID = &s
+			case "active":
+				b, err := boolValue(attr, value)
+				if err != nil {
+					return nil, err
+				}
+				p.Active = &b
+			case "displayname":
+				s, err := stringValue(attr, value)
+				if err != nil {
+					return nil, err
+				}
+				displayName = &s
+			case "name.formatted":
+				s, err := stringValue(attr, value)
+				if err != nil {
+					return nil, err
+				}
+				formatted = &s
+			case enterpriseDepartment:
+				s, err := stringValue(attr, value)
+				if err != nil {
+					return nil, err
+				}
+				p.Department = &s
+			}
+		}
+	}
+
+	p.DisplayName = displayName
+	if p.DisplayName == nil {
+		p.DisplayName = formatted
+	}
+	return p, nil
+}
+
+var enterpriseDepartment = strings.ToLower(SchemaEnterpriseUser) + ":department"
+
+func isUserAttribute(attr string) bool {
+	switch attr {
+	case "username", "active", "displayname", "name.formatted", enterpriseDepartment:
+		return true
+	}
+	return false
+}
+
+// flatten turns a path-less value such as {"name": {"formatted": "x"}} into
+// attribute paths ("name.formatted"). Extension schemas are joined with a
+// colon, the way RFC 7644 spells their attribute paths.
+func flatten(dst map[string]any, prefix string, obj map[string]any) {
+	for k, v := range obj {
+		key := strings.ToLower(k)
+		switch {
+		case prefix != "" && strings.HasPrefix(prefix, "urn:"):
+			key = prefix + ":" + key
+		case prefix != "":
+			key = prefix + "." + key
+		default:
+			key = attributeName(key)
+		}
+
+		if nested, ok := v.(map[string]any); ok {
+			flatten(dst, key, nested)
+			continue
+		}
+		dst[key] = v
+	}
+}
+
+// GroupPatch is the change a PATCH makes to a group. DisplayName is set when
+// the request tries to rename the group.
+type GroupPatch struct {
+	DisplayName *string
+	Members     model.MemberPatch
+}
+
+// ParseGroupPatch folds member operations into one model.MemberPatch so that
+// the repository can apply them atomically. Operations apply in order: a later
+// add cancels an earlier remove of the same member and vice versa, and a
+// replace discards everything before it.
+func ParseGroupPatch(ops []Operation) (*GroupPatch, error) {
+	p := &GroupPatch{}
+	m := &p.Members
+
+	add := func(ids []string) {
+		m.Remove = without(m.Remove, ids)
+		m.Add = appendNew(m.Add, ids)
+	}
+	remove := func(ids []string) {
+		m.Add = without(m.Add, ids)
+		m.Remove = appendNew(m.Remove, ids)
+	}
+	replace := func(ids []string) {
+		m.Replace = &ids
+		m.Add, m.Remove = nil, nil
+	}
+
+	for _, op := range ops {
+		kind, err := opKind(op.Op)
+		if err != nil {
+			return nil, err
+		}
+
+		path := attributeName(op.Path)
+		value := op.Value
+
+		// A path-less add or replace carries the attributes in its value.
+		if path == "" {
+			obj, ok := value.(map[string]any)
+			if !ok || kind == "remove" {
+				return nil, errorf(NoTarget, "%s without a path is not supported here", op.Op)
+			}
+			for k, v := range obj {
+				switch attributeName(k) {
+				case "displayname":
+					s, err := stringValue("displayName", v)
+					if err != nil {
+						return nil, err
+					}
+					p.DisplayName = &s
+				case "members":
+					ids, err := memberValues(v)
+					if err != nil {
+						return nil, err
+					}
+					if kind == "add" {
+						add(ids)
+					} else {
+						replace(ids)
+					}
+				}
+			}
+			continue
+		}
+
+		switch {
+		case path == "displayname":
+			if kind == "remove" {
+				return nil, errorf(Mutability, "displayName cannot be removed")
+			}
+			s, err := stringValue("displayName", value)
+			if err != nil {
+				return nil, err
+			}
+			p.DisplayName = &s
+
+		case path == "members":
+			switch kind {
+			case "add":
+				ids, err := memberValues(value)
+				if err != nil {
+					return nil, err
+				}
+				add(ids)
+			case "replace":
+				ids, err := memberValues(value)
+				if err != nil {
+					return nil, err
+				}
+				replace(ids)
+			case "remove":
+				// Without a value every member goes; some providers list
+				// the members to remove in the value instead of a filter.
+				if value == nil {
+					replace([]string{})
+					continue
+				}
+				ids, err := memberValues(value)
+				if err != nil {
+					return nil, err
+				}
+				remove(ids)
+			}
+
+		case strings.HasPrefix(path, "members["):
+			if kind != "remove" {
+				return nil, errorf(InvalidPath, "filtered member paths are only supported with remove")
+			}
+			id, err := memberFilter(path)
+			if err != nil {
+				return nil, err
+			}
+			remove([]string{id})
+
+		default:
+			return nil, errorf(InvalidPath, "unsupported path %s", op.Path)
+		}
+	}
+
+	return p, nil
+}
+
+// memberFilter extracts the id from `members[value eq "id"]`.
+func memberFilter(path string) (string, error) {
+	inner, ok := strings.CutPrefix(path, "members[")
+	if !ok || !strings.HasSuffix(inner, "]") {
+		return "", errorf(InvalidPath, "malformed path %s", path)
+	}
+	f, err := ParseFilter(strings.TrimSuffix(inner, "]"))
+	if err != nil {
+		return "", errorf(InvalidPath, "%s", err.Error())
+	}
+	if f.Attribute != "value" {
+		return "", errorf(InvalidPath, "members can only be filtered by value")
+	}
+	return f.Value, nil
+}
+
+// memberValues reads [{"value": "id"}, ...]; a single object is accepted too.
+func memberValues(v any) ([]string, error) {
+	var items []any
+	switch v := v.(type) {
+	case []any:
+		items = v
+	case map[string]any:
+		items = []any{v}
+	default:
+		return nil, errorf(InvalidValue, "members must be a list of objects with a value")
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, errorf(InvalidValue, "members must be a list of objects with a value")
+		}
+		id, ok := obj["value"].(string)
+		if !ok || id == "" {
+			return nil, errorf(InvalidValue, "member without a value")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func opKind(op string) (string, error) {
+	kind := strings.ToLower(op)
+	switch kind {
+	case "add", "replace", "remove":
+		return kind, nil
+	}
+	return "", errorf(InvalidSyntax, "unknown operation %q", op)
+}
+
+func stringValue(attr string, v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", errorf(InvalidValue, "%s must be a string", attr)
+	}
+	return s, nil
+}
+
+// boolValue also accepts "True" and "False": Azure AD sends booleans as
+// strings.
+func boolValue(attr string, v any) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, errorf(InvalidValue, "%s must be a boolean", attr)
+}
+
+func without(s, ids []string) []string {
+	return slices.DeleteFunc(s, func(id string) bool { return slices.Contains(ids, id) })
+}
+
+func appendNew(s, ids []string) []string {
+	for _, id := range ids {
+		if !slices.Contains(s, id) {
+			s = append(s, id)
+		}
+	}
+	return s
+}
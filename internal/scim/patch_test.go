@@ -0,0 +1,381 @@
+package scim
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// operations decodes the Operations of a PatchOp request body.
+func operations(t *testing.T, body string) []Operation {
+	t.Helper()
+
+	var req struct {
+		Operations []Operation
+	}
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("decode patch: %v", err)
+	}
+	return req.Operations
+}
+
+func strPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestParseUserPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want UserPatch
+	}{
+		{
+			name: "replace active by path",
+			body: `{"Operations": [{"op": "replace", "path": "active", "value": false}]}`,
+			want: UserPatch{Active: boolPtr(false)},
+		},
+		{
+			name: "azure ad capitalised op and string boolean",
+			body: `{"Operations": [{"op": "Replace", "path": "active", "value": "False"}]}`,
+			want: UserPatch{Active: boolPtr(false)},
+		},
+		{
+			// RFC 7644, section 3.5.2.1: attributes the service does not
+			// store are ignored.
+			name: "path-less add",
+			body: `{"Operations": [{
+				"op": "add",
+				"value": {
+					"emails": [{"value": "babs@jensen.org", "type": "home"}],
+					"nickname": "Babs"
+				}
+			}]}`,
+			want: UserPatch{},
+		},
+		{
+			name: "path-less replace of several attributes",
+			body: `{"Operations": [{
+				"op": "replace",
+				"value": {
+					"userName": "u1",
+					"displayName": "Babs Jensen",
+					"active": true,
+					"name": {"formatted": "Ms. Barbara J Jensen III"}
+				}
+			}]}`,
+			want: UserPatch{UserID: strPtr("u1"), DisplayName: strPtr("Babs Jensen"), Active: boolPtr(true)},
+		},
+		{
+			name: "name.formatted when there is no displayName",
+			body: `{"Operations": [{"op": "replace", "path": "name.formatted", "value": "Barbara Jensen"}]}`,
+			want: UserPatch{DisplayName: strPtr("Barbara Jensen")},
+		},
+		{
+			name: "nested name object",
+			body: `{"Operations": [{"op": "replace", "path": "name", "value": {"formatted": "Barbara Jensen"}}]}`,
+			want: UserPatch{DisplayName: strPtr("Barbara Jensen")},
+		},
+		{
+			name: "displayName wins over name.formatted",
+			body: `{"Operations": [
+				{"op": "replace", "path": "displayName", "value": "Babs"},
+				{"op": "replace", "path": "name.formatted", "value": "Barbara Jensen"}
+			]}`,
+			want: UserPatch{DisplayName: strPtr("Babs")},
+		},
+		{
+			name: "core schema prefix",
+			body: `{"Operations": [{"op": "replace", "path": "urn:ietf:params:scim:schemas:core:2.0:User:displayName", "value": "Babs"}]}`,
+			want: UserPatch{DisplayName: strPtr("Babs")},
+		},
+		{
+			name: "enterprise department by path",
+			body: `{"Operations": [{"op": "replace", "path": "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "value": "payments"}]}`,
+			want: UserPatch{Department: strPtr("payments")},
+		},
+		{
+			name: "enterprise department in a path-less value",
+			body: `{"Operations": [{
+				"op": "replace",
+				"value": {"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User": {"department": "payments"}}
+			}]}`,
+			want: UserPatch{Department: strPtr("payments")},
+		},
+		{
+			name: "later operation wins",
+			body: `{"Operations": [
+				{"op": "replace", "path": "active", "value": false},
+				{"op": "replace", "path": "active", "value": true}
+			]}`,
+			want: UserPatch{Active: boolPtr(true)},
+		},
+		{
+			name: "remove of an attribute the service does not store",
+			body: `{"Operations": [{"op": "remove", "path": "nickname"}]}`,
+			want: UserPatch{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserPatch(operations(t, tt.body))
+			if err != nil {
+				t.Fatalf("ParseUserPatch() error = %v", err)
+			}
+			if !equalPtr(got.UserID, tt.want.UserID) ||
+				!equalPtr(got.Active, tt.want.Active) ||
+				!equalPtr(got.DisplayName, tt.want.DisplayName) ||
+				!equalPtr(got.Department, tt.want.Department) {
+				t.Errorf("ParseUserPatch() = %s, want %s", formatUserPatch(got), formatUserPatch(&tt.want))
+			}
+		})
+	}
+}
+
+func formatUserPatch(p *UserPatch) string {
+	data, _ := json.Marshal(p)
+	return string(data)
+}
+
+func TestParseUserPatchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		typ  string
+	}{
+		{
+			name: "unknown op",
+			body: `{"Operations": [{"op": "move", "path": "active", "value": false}]}`,
+			typ:  InvalidSyntax,
+		},
+		{
+			name: "remove active",
+			body: `{"Operations": [{"op": "remove", "path": "active"}]}`,
+			typ:  Mutability,
+		},
+		{
+			name: "remove userName",
+			body: `{"Operations": [{"op": "remove", "path": "userName"}]}`,
+			typ:  Mutability,
+		},
+		{
+			name: "path-less op with a scalar value",
+			body: `{"Operations": [{"op": "replace", "value": "Babs"}]}`,
+			typ:  InvalidValue,
+		},
+		{
+			name: "non-boolean active",
+			body: `{"Operations": [{"op": "replace", "path": "active", "value": "yes"}]}`,
+			typ:  InvalidValue,
+		},
+		{
+			name: "non-string displayName",
+			body: `{"Operations": [{"op": "replace", "path": "displayName", "value": 42}]}`,
+			typ:  InvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseUserPatch(operations(t, tt.body))
+			wantSCIMError(t, err, tt.typ)
+		})
+	}
+}
+
+func TestParseGroupPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		current     []string
+		wantMembers []string
+		wantName    *string
+	}{
+		{
+			// RFC 7644, section 3.5.2.1.
+			name: "add member",
+			body: `{"Operations": [{
+				"op": "add",
+				"path": "members",
+				"value": [{
+					"display": "Babs Jensen",
+					"$ref": "https://example.com/v2/Users/2819c223-7f76-453a-919d-413861904646",
+					"value": "2819c223-7f76-453a-919d-413861904646"
+				}]
+			}]}`,
+			current:     []string{"u1"},
+			wantMembers: []string{"u1", "2819c223-7f76-453a-919d-413861904646"},
+		},
+		{
+			// RFC 7644, section 3.5.2.2.
+			name:        "remove a single member",
+			body:        `{"Operations": [{"op": "remove", "path": "members[value eq \"2819c223-7f76-453a-919d-413861904646\"]"}]}`,
+			current:     []string{"u1", "2819c223-7f76-453a-919d-413861904646"},
+			wantMembers: []string{"u1"},
+		},
+		{
+			name:        "remove all members",
+			body:        `{"Operations": [{"op": "remove", "path": "members"}]}`,
+			current:     []string{"u1", "u2"},
+			wantMembers: []string{},
+		},
+		{
+			name: "remove multiple members",
+			body: `{"Operations": [
+				{"op": "remove", "path": "members[value eq \"u1\"]"},
+				{"op": "remove", "path": "members[value eq \"u3\"]"}
+			]}`,
+			current:     []string{"u1", "u2", "u3"},
+			wantMembers: []string{"u2"},
+		},
+		{
+			name:        "remove members listed in the value",
+			body:        `{"Operations": [{"op": "remove", "path": "members", "value": [{"value": "u1"}, {"value": "u2"}]}]}`,
+			current:     []string{"u1", "u2", "u3"},
+			wantMembers: []string{"u3"},
+		},
+		{
+			name: "remove all members and add new ones",
+			body: `{"Operations": [
+				{"op": "remove", "path": "members"},
+				{"op": "add", "path": "members", "value": [{"value": "u4"}, {"value": "u5"}]}
+			]}`,
+			current:     []string{"u1", "u2"},
+			wantMembers: []string{"u4", "u5"},
+		},
+		{
+			// RFC 7644, section 3.5.2.3.
+			name:        "replace members",
+			body:        `{"Operations": [{"op": "replace", "path": "members", "value": [{"value": "u2"}, {"value": "u9"}]}]}`,
+			current:     []string{"u1", "u2"},
+			wantMembers: []string{"u2", "u9"},
+		},
+		{
+			name:        "path-less add",
+			body:        `{"Operations": [{"op": "Add", "value": {"members": [{"value": "u3"}]}}]}`,
+			current:     []string{"u1"},
+			wantMembers: []string{"u1", "u3"},
+		},
+		{
+			name:        "path-less replace",
+			body:        `{"Operations": [{"op": "replace", "value": {"displayName": "backend", "members": [{"value": "u3"}]}}]}`,
+			current:     []string{"u1"},
+			wantMembers: []string{"u3"},
+			wantName:    strPtr("backend"),
+		},
+		{
+			name: "later add cancels an earlier remove",
+			body: `{"Operations": [
+				{"op": "remove", "path": "members[value eq \"u1\"]"},
+				{"op": "add", "path": "members", "value": [{"value": "u1"}]}
+			]}`,
+			current:     []string{"u1", "u2"},
+			wantMembers: []string{"u1", "u2"},
+		},
+		{
+			name: "later remove cancels an earlier add",
+			body: `{"Operations": [
+				{"op": "add", "path": "members", "value": [{"value": "u3"}]},
+				{"op": "remove", "path": "members[value eq \"u3\"]"}
+			]}`,
+			current:     []string{"u1"},
+			wantMembers: []string{"u1"},
+		},
+		{
+			name:        "single member object",
+			body:        `{"Operations": [{"op": "add", "path": "members", "value": {"value": "u2"}}]}`,
+			current:     []string{"u1"},
+			wantMembers: []string{"u1", "u2"},
+		},
+		{
+			name:        "rename",
+			body:        `{"Operations": [{"op": "replace", "path": "displayName", "value": "backend-core"}]}`,
+			current:     []string{"u1"},
+			wantMembers: []string{"u1"},
+			wantName:    strPtr("backend-core"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGroupPatch(operations(t, tt.body))
+			if err != nil {
+				t.Fatalf("ParseGroupPatch() error = %v", err)
+			}
+			if members := got.Members.Apply(tt.current); !slices.Equal(members, tt.wantMembers) {
+				t.Errorf("members = %v, want %v", members, tt.wantMembers)
+			}
+			if !equalPtr(got.DisplayName, tt.wantName) {
+				t.Errorf("DisplayName = %v, want %v", got.DisplayName, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestParseGroupPatchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		typ  string
+	}{
+		{
+			name: "unknown op",
+			body: `{"Operations": [{"op": "copy", "path": "members", "value": []}]}`,
+			typ:  InvalidSyntax,
+		},
+		{
+			name: "remove displayName",
+			body: `{"Operations": [{"op": "remove", "path": "displayName"}]}`,
+			typ:  Mutability,
+		},
+		{
+			name: "path-less remove",
+			body: `{"Operations": [{"op": "remove", "value": {"members": [{"value": "u1"}]}}]}`,
+			typ:  NoTarget,
+		},
+		{
+			name: "unsupported path",
+			body: `{"Operations": [{"op": "replace", "path": "externalId", "value": "x"}]}`,
+			typ:  InvalidPath,
+		},
+		{
+			name: "filtered path with add",
+			body: `{"Operations": [{"op": "add", "path": "members[value eq \"u1\"]", "value": {"value": "u1"}}]}`,
+			typ:  InvalidPath,
+		},
+		{
+			name: "member filter on another attribute",
+			body: `{"Operations": [{"op": "remove", "path": "members[display eq \"Babs\"]"}]}`,
+			typ:  InvalidPath,
+		},
+		{
+			name: "malformed member filter",
+			body: `{"Operations": [{"op": "remove", "path": "members[value eq \"u1\""}]}`,
+			typ:  InvalidPath,
+		},
+		{
+			name: "member without a value",
+			body: `{"Operations": [{"op": "add", "path": "members", "value": [{"display": "Babs"}]}]}`,
+			typ:  InvalidValue,
+		},
+		{
+			name: "members as a string",
+			body: `{"Operations": [{"op": "add", "path": "members", "value": "u1"}]}`,
+			typ:  InvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseGroupPatch(operations(t, tt.body))
+			wantSCIMError(t, err, tt.typ)
+		})
+	}
+}
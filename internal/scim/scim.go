@@ -0,0 +1,97 @@
+// Package scim parses the parts of SCIM 2.0 requests (RFC 7644) that the
+// provisioning endpoints accept: equality filters and PATCH operations on
+// users and group members.
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	SchemaUser           = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup          = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaEnterpriseUser = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SchemaListResponse   = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp        = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError          = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceConfig  = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// Error types from RFC 7644, section 3.12.
+const (
+	InvalidFilter = "invalidFilter"
+	InvalidSyntax = "invalidSyntax"
+	InvalidPath   = "invalidPath"
+	InvalidValue  = "invalidValue"
+	Mutability    = "mutability"
+	NoTarget      = "noTarget"
+	Uniqueness    = "uniqueness"
+)
+
+// Error is a request the parser rejects, with the SCIM error type a client
+// gets back. All of them are answered with 400 Bad Request.
+type Error struct {
+	Type   string
+	Detail string
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+func errorf(typ, format string, args ...any) *Error {
+	return &Error{Type: typ, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Filter is an `attribute eq value` expression, the only kind of filter the
+// service supports. Attribute is lower-cased and stripped of a schema prefix;
+// boolean literals are kept as "true" and "false".
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses an equality filter such as `userName eq "bjensen"`.
+func ParseFilter(s string) (*Filter, error) {
+	s = strings.TrimSpace(s)
+
+	attr, rest, ok := strings.Cut(s, " ")
+	if !ok {
+		return nil, errorf(InvalidFilter, "filter %q is not of the form `attribute eq value`", s)
+	}
+	op, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(op, "eq") {
+		return nil, errorf(InvalidFilter, "only the eq operator is supported, got %q", s)
+	}
+
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(value, `"`):
+		var str string
+		if err := json.Unmarshal([]byte(value), &str); err != nil {
+			return nil, errorf(InvalidFilter, "invalid string literal %s", value)
+		}
+		value = str
+	case strings.EqualFold(value, "true"), strings.EqualFold(value, "false"):
+		value = strings.ToLower(value)
+	default:
+		return nil, errorf(InvalidFilter, "unsupported comparison value %s", value)
+	}
+
+	return &Filter{Attribute: attributeName(attr), Value: value}, nil
+}
+
+// attributeName lower-cases an attribute path and drops the core schema URN
+// clients may prefix it with. Extension attributes keep their URN, so that
+// they do not collide with core ones.
+func attributeName(path string) string {
+	path = strings.ToLower(strings.TrimSpace(path))
+	for _, schema := range []string{SchemaUser, SchemaGroup} {
+		if prefix := strings.ToLower(schema) + ":"; strings.HasPrefix(path, prefix) {
+			return strings.TrimPrefix(path, prefix)
+		}
+	}
+	return path
+}
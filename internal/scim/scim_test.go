@@ -0,0 +1,80 @@
+package scim
+
+import (
+	"errors"
+	"testing"
+)
+
+// wantSCIMError fails the test unless err is an *Error of the given type.
+func wantSCIMError(t *testing.T, err error, typ string) {
+	t.Helper()
+
+	var scimErr *Error
+	if !errors.As(err, &scimErr) {
+		t.Fatalf("error = %v, want SCIM error %s", err, typ)
+	}
+	if scimErr.Type != typ {
+		t.Fatalf("error type = %s (%s), want %s", scimErr.Type, scimErr.Detail, typ)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter    string
+		attribute string
+		value     string
+	}{
+		// RFC 7644, section 3.4.2.2.
+		{filter: `userName eq "bjensen"`, attribute: "username", value: "bjensen"},
+		{filter: `userName Eq "john"`, attribute: "username", value: "john"},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "bjensen"`, attribute: "username", value: "bjensen"},
+		{filter: `meta.resourceType eq "User"`, attribute: "meta.resourcetype", value: "User"},
+		{filter: `displayName eq "backend"`, attribute: "displayname", value: "backend"},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:Group:displayName eq "backend"`, attribute: "displayname", value: "backend"},
+		{filter: `active eq true`, attribute: "active", value: "true"},
+		{filter: `active eq False`, attribute: "active", value: "false"},
+		{filter: `  userName  eq  "b jensen"  `, attribute: "username", value: "b jensen"},
+		{filter: `userName eq "say \"hi\""`, attribute: "username", value: `say "hi"`},
+		{filter: `value eq "2819c223-7f76-453a-919d-413861904646"`, attribute: "value", value: "2819c223-7f76-453a-919d-413861904646"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := ParseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			if f.Attribute != tt.attribute || f.Value != tt.value {
+				t.Errorf("ParseFilter() = {%q, %q}, want {%q, %q}", f.Attribute, f.Value, tt.attribute, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseFilterUnsupported(t *testing.T) {
+	// RFC 7644, section 3.4.2.2 examples beyond plain equality.
+	filters := []string{
+		`name.familyName co "O'Malley"`,
+		`userName sw "J"`,
+		`urn:ietf:params:scim:schemas:core:2.0:User:userName sw "J"`,
+		`title pr`,
+		`meta.lastModified gt "2011-05-13T04:42:34Z"`,
+		`title pr and userType eq "Employee"`,
+		`userType eq "Employee" and (emails co "example.com" or emails.value co "example.org")`,
+		`userType ne "Employee" and not (emails co "example.com")`,
+		`emails[type eq "work" and value co "@example.com"]`,
+		`userName eq "bjensen" and active eq true`,
+		`userName eq bjensen`,
+		`userName eq 42`,
+		`userName eq "unterminated`,
+		`userName`,
+		``,
+	}
+
+	for _, filter := range filters {
+		t.Run(filter, func(t *testing.T) {
+			_, err := ParseFilter(filter)
+			wantSCIMError(t, err, InvalidFilter)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/roster"
+	"avito-pr-service/internal/service"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxImportSize bounds the roster accepted by /admin/import and /admin/sync.
+const maxImportSize = 10 << 20
+
+type AdminHandler struct {
+	teamService *service.TeamService
+}
+
+func NewAdminHandler(teamService *service.TeamService) *AdminHandler {
+	return &AdminHandler{teamService: teamService}
+}
+
+func (h *AdminHandler) PostAdminImport(w http.ResponseWriter, r *http.Request, params api.PostAdminImportParams) {
+	formatName := r.Header.Get("Content-Type")
+	if params.Format != nil {
+		formatName = string(*params.Format)
+	}
+	teams, ok := readRoster(w, r, formatName)
+	if !ok {
+		return
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+	res, err := h.teamService.ImportTeams(r.Context(), teams, dryRun)
+	if err != nil {
+		switch err {
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "bulk import requires a token not bound to a user")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, api.ImportResult{
+		DryRun:       res.DryRun,
+		Teams:        res.Teams,
+		Members:      res.Members,
+		TeamsCreated: res.TeamsCreated,
+		UsersCreated: res.UsersCreated,
+	})
+}
+
+func (h *AdminHandler) GetAdminExport(w http.ResponseWriter, r *http.Request, params api.GetAdminExportParams) {
+	format := roster.FormatCSV
+	if params.Format != nil {
+		f, err := roster.ParseFormat(string(*params.Format))
+		if err != nil {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, err.Error())
+			return
+		}
+		format = f
+	}
+
+	teams, err := h.teamService.ExportTeams(r.Context())
+	if err != nil {
+		switch err {
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "export requires a token not bound to a user")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+
+	// Render into memory first so that an encoding failure can still be
+	// reported with a proper status.
+	var buf bytes.Buffer
+	if err := roster.Write(&buf, format, teams); err != nil {
+		logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+		WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="teams.%s"`, format))
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+func (h *AdminHandler) PostAdminSyncPlan(w http.ResponseWriter, r *http.Request, params api.PostAdminSyncPlanParams) {
+	opts, ok := syncOptions(w, (*string)(params.MissingUsers))
+	if !ok {
+		return
+	}
+	desired, ok := readRoster(w, r, syncFormat(r))
+	if !ok {
+		return
+	}
+
+	plan, err := h.teamService.PlanSync(r.Context(), desired, opts)
+	if err != nil {
+		writeSyncError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toAPISyncPlan(plan))
+}
+
+func (h *AdminHandler) PostAdminSyncApply(w http.ResponseWriter, r *http.Request, params api.PostAdminSyncApplyParams) {
+	opts, ok := syncOptions(w, (*string)(params.MissingUsers))
+	if !ok {
+		return
+	}
+	desired, ok := readRoster(w, r, syncFormat(r))
+	if !ok {
+		return
+	}
+
+	var planID string
+	if params.PlanId != nil {
+		planID = strings.TrimSpace(*params.PlanId)
+	}
+
+	res, err := h.teamService.ApplySync(r.Context(), desired, opts, planID)
+	if err != nil {
+		writeSyncError(w, r, err)
+		return
+	}
+
+	reassignments := make([]api.SyncReassignment, len(res.Reassignments))
+	for i, ra := range res.Reassignments {
+		reassignments[i] = api.SyncReassignment{
+			PullRequestId: ra.PRID,
+			ReviewerId:    ra.ReviewerID,
+			ReplacedBy:    ra.ReplacedBy,
+		}
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"plan":          toAPISyncPlan(res.Plan),
+		"reassignments": reassignments,
+	})
+}
+
+// syncFormat defaults to YAML, the format org charts are kept in.
+func syncFormat(r *http.Request) string {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		return ct
+	}
+	return string(roster.FormatYAML)
+}
+
+func syncOptions(w http.ResponseWriter, missingUsers *string) (model.SyncOptions, bool) {
+	opts := model.SyncOptions{MissingUsers: model.MissingUsersDeactivate}
+	if missingUsers == nil {
+		return opts, true
+	}
+
+	switch mu := model.MissingUsers(*missingUsers); mu {
+	case model.MissingUsersDeactivate, model.MissingUsersRemove:
+		opts.MissingUsers = mu
+		return opts, true
+	}
+	WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "missing_users must be deactivate or remove")
+	return opts, false
+}
+
+func writeSyncError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case int_errors.ErrForbidden:
+		WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "org sync requires a token not bound to a user")
+	case int_errors.ErrSyncPlanStale:
+		WriteJSONError(w, http.StatusConflict, api.PLANSTALE, err.Error())
+	default:
+		logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+		WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+	}
+}
+
+func toAPISyncPlan(plan *model.SyncPlan) api.SyncPlan {
+	resp := api.SyncPlan{
+		PlanId:          plan.ID,
+		Teams:           make([]api.SyncTeamChange, len(plan.Teams)),
+		Users:           make([]api.SyncUserChange, len(plan.Users)),
+		AffectedReviews: make([]api.AffectedReview, len(plan.AffectedReviews)),
+	}
+
+	for i, t := range plan.Teams {
+		resp.Teams[i] = api.SyncTeamChange{
+			TeamName: t.TeamName,
+			Action:   api.SyncTeamChangeAction(t.Action),
+		}
+	}
+	for i, u := range plan.Users {
+		change := api.SyncUserChange{
+			UserId:    u.UserID,
+			Username:  u.Username,
+			Action:    api.SyncUserChangeAction(u.Action),
+			FromTeams: u.FromTeams,
+			ToTeams:   u.ToTeams,
+		}
+		if u.PrimaryTeam != "" {
+			change.PrimaryTeam = &u.PrimaryTeam
+		}
+		if u.Reason != "" {
+			change.Reason = &u.Reason
+		}
+		resp.Users[i] = change
+	}
+	for i, rv := range plan.AffectedReviews {
+		resp.AffectedReviews[i] = api.AffectedReview{
+			PullRequestId: rv.PRID,
+			TeamName:      rv.TeamName,
+			ReviewerId:    rv.ReviewerID,
+			AuthorId:      rv.AuthorID,
+			Reason:        api.AffectedReviewReason(rv.Reason),
+		}
+	}
+
+	return resp
+}
+
+// readRoster reads and validates a roster from the request body, writing the
+// error response itself when it reports false.
+func readRoster(w http.ResponseWriter, r *http.Request, formatName string) ([]*model.Team, bool) {
+	format, err := roster.ParseFormat(formatName)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, err.Error())
+		return nil, false
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, fmt.Sprintf("file exceeds %d bytes", tooLarge.Limit))
+			return nil, false
+		}
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "read body: "+err.Error())
+		return nil, false
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "file is empty")
+		return nil, false
+	}
+
+	teams, err := roster.Read(bytes.NewReader(data), format)
+	if err != nil {
+		var invalid *roster.ValidationError
+		if errors.As(err, &invalid) {
+			writeRowErrors(w, invalid.Errors)
+			return nil, false
+		}
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, err.Error())
+		return nil, false
+	}
+
+	return teams, true
+}
+
+func writeRowErrors(w http.ResponseWriter, lineErrs []roster.LineError) {
+	rows := make([]api.RowError, len(lineErrs))
+	for i, le := range lineErrs {
+		rows[i] = api.RowError{Line: le.Line, Message: le.Message}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	resp := api.ErrorResponse{Rows: &rows}
+	resp.Error.Code = api.VALIDATIONFAILED
+	resp.Error.Message = fmt.Sprintf("%d invalid row(s)", len(rows))
+	if traceID := w.Header().Get(TraceIDHeader); traceID != "" {
+		resp.Error.TraceId = &traceID
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
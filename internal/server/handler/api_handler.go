@@ -7,18 +7,26 @@ import (
 )
 
 type APIHandler struct {
-	pr   *PRHandler
-	user *UserHandler
-	team *TeamHandler
-	stat *StatisticsHandler
+	pr     *PRHandler
+	user   *UserHandler
+	team   *TeamHandler
+	stat   *StatisticsHandler
+	auth   *AuthHandler
+	health *HealthHandler
+	admin  *AdminHandler
+	scim   *SCIMHandler
 }
 
-func NewAPIHandler(pr *PRHandler, user *UserHandler, team *TeamHandler, stat *StatisticsHandler) *APIHandler {
+func NewAPIHandler(pr *PRHandler, user *UserHandler, team *TeamHandler, stat *StatisticsHandler, auth *AuthHandler, health *HealthHandler, admin *AdminHandler, scim *SCIMHandler) *APIHandler {
 	return &APIHandler{
-		pr:   pr,
-		user: user,
-		team: team,
-		stat: stat,
+		pr:     pr,
+		user:   user,
+		team:   team,
+		stat:   stat,
+		auth:   auth,
+		health: health,
+		admin:  admin,
+		scim:   scim,
 	}
 }
 
@@ -34,6 +42,22 @@ func (h *APIHandler) GetUsersGetReview(w http.ResponseWriter, r *http.Request, p
 	h.user.GetUsersGetReview(w, r, params)
 }
 
+func (h *APIHandler) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params api.GetPullRequestGetParams) {
+	h.pr.GetPullRequestGet(w, r, params)
+}
+
+func (h *APIHandler) GetTeamReviewSettings(w http.ResponseWriter, r *http.Request, params api.GetTeamReviewSettingsParams) {
+	h.team.GetTeamReviewSettings(w, r, params)
+}
+
+func (h *APIHandler) PostTeamReviewSettings(w http.ResponseWriter, r *http.Request) {
+	h.team.PostTeamReviewSettings(w, r)
+}
+
+func (h *APIHandler) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	h.team.PostTeamRename(w, r)
+}
+
 func (h *APIHandler) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
 	h.pr.PostPullRequestMerge(w, r)
 }
@@ -50,10 +74,138 @@ func (h *APIHandler) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request
 	h.user.PostUsersSetIsActive(w, r)
 }
 
-func (h *APIHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
-	h.stat.GetStatistics(w, r)
+func (h *APIHandler) GetUsersGet(w http.ResponseWriter, r *http.Request, params api.GetUsersGetParams) {
+	h.user.GetUsersGet(w, r, params)
+}
+
+func (h *APIHandler) PostUsersCreate(w http.ResponseWriter, r *http.Request) {
+	h.user.PostUsersCreate(w, r)
+}
+
+func (h *APIHandler) PostUsersUpdate(w http.ResponseWriter, r *http.Request) {
+	h.user.PostUsersUpdate(w, r)
+}
+
+func (h *APIHandler) PostUsersDelete(w http.ResponseWriter, r *http.Request) {
+	h.user.PostUsersDelete(w, r)
+}
+
+func (h *APIHandler) PostTeamSetParent(w http.ResponseWriter, r *http.Request) {
+	h.team.PostTeamSetParent(w, r)
+}
+
+func (h *APIHandler) GetTeamSubtree(w http.ResponseWriter, r *http.Request, params api.GetTeamSubtreeParams) {
+	h.team.GetTeamSubtree(w, r, params)
+}
+
+func (h *APIHandler) GetStatistics(w http.ResponseWriter, r *http.Request, params api.GetStatisticsParams) {
+	h.stat.GetStatistics(w, r, params)
+}
+
+func (h *APIHandler) GetStatisticsFairness(w http.ResponseWriter, r *http.Request, params api.GetStatisticsFairnessParams) {
+	h.stat.GetStatisticsFairness(w, r, params)
+}
+
+func (h *APIHandler) GetStatisticsHistory(w http.ResponseWriter, r *http.Request, params api.GetStatisticsHistoryParams) {
+	h.stat.GetStatisticsHistory(w, r, params)
+}
+
+func (h *APIHandler) GetStatisticsPairs(w http.ResponseWriter, r *http.Request, params api.GetStatisticsPairsParams) {
+	h.stat.GetStatisticsPairs(w, r, params)
+}
+
+func (h *APIHandler) GetStatisticsSubtree(w http.ResponseWriter, r *http.Request, params api.GetStatisticsSubtreeParams) {
+	h.stat.GetStatisticsSubtree(w, r, params)
+}
+
+func (h *APIHandler) GetStatisticsTiming(w http.ResponseWriter, r *http.Request, params api.GetStatisticsTimingParams) {
+	h.stat.GetStatisticsTiming(w, r, params)
+}
+
+func (h *APIHandler) PostAuthTokensCreate(w http.ResponseWriter, r *http.Request) {
+	h.auth.PostAuthTokensCreate(w, r)
+}
+
+func (h *APIHandler) PostAuthTokensRevoke(w http.ResponseWriter, r *http.Request) {
+	h.auth.PostAuthTokensRevoke(w, r)
+}
+
+func (h *APIHandler) GetHealthLive(w http.ResponseWriter, r *http.Request) {
+	h.health.GetHealthLive(w, r)
 }
 
+func (h *APIHandler) GetHealthReady(w http.ResponseWriter, r *http.Request) {
+	h.health.GetHealthReady(w, r)
+}
+
+func (h *APIHandler) PostAdminImport(w http.ResponseWriter, r *http.Request, params api.PostAdminImportParams) {
+	h.admin.PostAdminImport(w, r, params)
+}
+
+func (h *APIHandler) GetAdminExport(w http.ResponseWriter, r *http.Request, params api.GetAdminExportParams) {
+	h.admin.GetAdminExport(w, r, params)
+}
+
+func (h *APIHandler) PostAdminSyncPlan(w http.ResponseWriter, r *http.Request, params api.PostAdminSyncPlanParams) {
+	h.admin.PostAdminSyncPlan(w, r, params)
+}
+
+func (h *APIHandler) PostAdminSyncApply(w http.ResponseWriter, r *http.Request, params api.PostAdminSyncApplyParams) {
+	h.admin.PostAdminSyncApply(w, r, params)
+}
+
+func (h *APIHandler) PostAdminStatisticsRebuild(w http.ResponseWriter, r *http.Request) {
+	h.stat.PostAdminStatisticsRebuild(w, r)
+}
+
+func (h *APIHandler) GetScimV2ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	h.scim.GetScimV2ServiceProviderConfig(w, r)
+}
+
+func (h *APIHandler) GetScimV2Users(w http.ResponseWriter, r *http.Request, params api.GetScimV2UsersParams) {
+	h.scim.GetScimV2Users(w, r, params)
+}
+
+func (h *APIHandler) PostScimV2Users(w http.ResponseWriter, r *http.Request) {
+	h.scim.PostScimV2Users(w, r)
+}
+
+func (h *APIHandler) GetScimV2UsersId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	h.scim.GetScimV2UsersId(w, r, id)
+}
+
+func (h *APIHandler) PatchScimV2UsersId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	h.scim.PatchScimV2UsersId(w, r, id)
+}
+
+func (h *APIHandler) DeleteScimV2UsersId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	h.scim.DeleteScimV2UsersId(w, r, id)
+}
+
+func (h *APIHandler) GetScimV2Groups(w http.ResponseWriter, r *http.Request, params api.GetScimV2GroupsParams) {
+	h.scim.GetScimV2Groups(w, r, params)
+}
+
+func (h *APIHandler) PostScimV2Groups(w http.ResponseWriter, r *http.Request) {
+	h.scim.PostScimV2Groups(w, r)
+}
+
+func (h *APIHandler) GetScimV2GroupsId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	h.scim.GetScimV2GroupsId(w, r, id)
+}
+
+func (h *APIHandler) PatchScimV2GroupsId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	h.scim.PatchScimV2GroupsId(w, r, id)
+}
+
+func (h *APIHandler) DeleteScimV2GroupsId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	h.scim.DeleteScimV2GroupsId(w, r, id)
+}
+
+// TraceIDHeader is set by the tracing middleware before the handler runs;
+// WriteJSONError copies it into the body so it survives copy-pasted reports.
+const TraceIDHeader = "X-Trace-Id"
+
 func WriteJSONError(w http.ResponseWriter, status int, code api.ErrorResponseErrorCode, msg string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -61,6 +213,9 @@ func WriteJSONError(w http.ResponseWriter, status int, code api.ErrorResponseErr
 	resp := api.ErrorResponse{}
 	resp.Error.Code = code
 	resp.Error.Message = msg
+	if traceID := w.Header().Get(TraceIDHeader); traceID != "" {
+		resp.Error.TraceId = &traceID
+	}
 
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/service"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type AuthTokenCreateResponse struct {
+	Token  api.ApiToken `json:"token"`
+	Secret string       `json:"secret"`
+}
+
+type AuthHandler struct {
+	authService *service.AuthService
+}
+
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+func (h *AuthHandler) PostAuthTokensCreate(w http.ResponseWriter, r *http.Request) {
+	var body api.PostAuthTokensCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "invalid JSON")
+		return
+	}
+
+	name := strings.TrimSpace(body.Name)
+	if name == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "name must not be empty")
+		return
+	}
+	if len(body.Scopes) == 0 {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "scopes must not be empty")
+		return
+	}
+
+	scopes := make([]string, 0, len(body.Scopes))
+	for _, scope := range body.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	var userID *string
+	if body.UserId != nil {
+		uid := strings.TrimSpace(*body.UserId)
+		if uid == "" {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "user_id must not be empty")
+			return
+		}
+		userID = &uid
+	}
+
+	token, secret, err := h.authService.CreateToken(r.Context(), name, userID, scopes)
+	if err != nil {
+		switch err {
+		case int_errors.ErrInvalidScope:
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "unknown scope")
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "tokens are minted by tokens not bound to a user, with scopes the caller holds")
+		case int_errors.ErrUserNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "user not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, AuthTokenCreateResponse{
+		Token:  toAPIToken(token),
+		Secret: secret,
+	})
+}
+
+func (h *AuthHandler) PostAuthTokensRevoke(w http.ResponseWriter, r *http.Request) {
+	var body api.PostAuthTokensRevokeJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "invalid JSON")
+		return
+	}
+
+	tokenID := strings.TrimSpace(body.TokenId)
+	if tokenID == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "token_id must not be empty")
+		return
+	}
+
+	token, err := h.authService.RevokeToken(r.Context(), tokenID)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTokenNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "token not found")
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "revoking tokens requires a token not bound to a user")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"token": toAPIToken(token)})
+}
+
+func toAPIToken(t *model.APIToken) api.ApiToken {
+	scopes := make([]api.TokenScope, 0, len(t.Scopes))
+	for _, scope := range t.Scopes {
+		scopes = append(scopes, api.TokenScope(scope))
+	}
+
+	return api.ApiToken{
+		TokenId:   t.ID,
+		Name:      t.Name,
+		UserId:    t.UserID,
+		Scopes:    scopes,
+		CreatedAt: t.CreatedAt,
+		RevokedAt: t.RevokedAt,
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/service"
+	"net/http"
+)
+
+type HealthHandler struct {
+	service *service.HealthService
+}
+
+func NewHealthHandler(service *service.HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+func (h *HealthHandler) GetHealthLive(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, h.service.Live(r.Context()))
+}
+
+func (h *HealthHandler) GetHealthReady(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, h.service.Ready(r.Context()))
+}
+
+func writeHealth(w http.ResponseWriter, report *model.HealthReport) {
+	resp := api.HealthResponse{
+		Status: api.HealthResponseStatus(report.Status),
+		Checks: make([]api.HealthCheck, 0, len(report.Checks)),
+	}
+	for _, c := range report.Checks {
+		check := api.HealthCheck{
+			Name:      c.Name,
+			Status:    api.HealthCheckStatus(c.Status),
+			LatencyMs: float64(c.Latency.Microseconds()) / 1000,
+		}
+		if c.Detail != "" {
+			check.Detail = &c.Detail
+		}
+		if c.Error != "" {
+			check.Error = &c.Error
+		}
+		resp.Checks = append(resp.Checks, check)
+	}
+
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	WriteJSON(w, status, resp)
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"avito-pr-service/internal/api"
 	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
 	"avito-pr-service/internal/model"
 	"avito-pr-service/internal/service"
 	"encoding/json"
@@ -35,6 +37,9 @@ func (h *PRHandler) PostPullRequestCreate(w http.ResponseWriter, r *http.Request
 		PRName:   strings.TrimSpace(body.PullRequestName),
 		AuthorID: strings.TrimSpace(body.AuthorId),
 	}
+	if body.TeamName != nil {
+		req.TeamName = strings.TrimSpace(*body.TeamName)
+	}
 
 	if req.PRID == "" || req.PRName == "" || req.AuthorID == "" {
 		http.Error(w, "missing required fields", http.StatusBadRequest)
@@ -50,27 +55,43 @@ func (h *PRHandler) PostPullRequestCreate(w http.ResponseWriter, r *http.Request
 		case int_errors.ErrUserNotFound:
 			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "author not found")
 			return
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+			return
+		case int_errors.ErrAuthorNotInTeam:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "author is not a member of team")
+			return
 		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
 			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	status := api.PullRequestStatusOPEN
-	if pr.Status == model.StatusMerged {
-		status = api.PullRequestStatusMERGED
+	resp := toAPIPullRequest(pr)
+	WriteJSON(w, http.StatusCreated, map[string]interface{}{"pr": resp})
+}
+
+func (h *PRHandler) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params api.GetPullRequestGetParams) {
+	prID := strings.TrimSpace(params.PullRequestId)
+	if prID == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "pull_request_id must not be empty")
+		return
 	}
 
-	resp := api.PullRequest{
-		PullRequestId:     pr.PRID,
-		PullRequestName:   pr.PRName,
-		AuthorId:          pr.AuthorID,
-		AssignedReviewers: pr.AssignedReviewers,
-		Status:            status,
-		CreatedAt:         &pr.CreatedAt,
-		MergedAt:          pr.MergedAt,
+	pr, err := h.prService.GetPR(r.Context(), prID)
+	if err != nil {
+		switch err {
+		case int_errors.ErrPRNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "pull request not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
 	}
-	WriteJSON(w, http.StatusCreated, map[string]interface{}{"pr": resp})
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"pr": toAPIPullRequest(pr)})
 }
 
 func (h *PRHandler) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
@@ -93,25 +114,13 @@ func (h *PRHandler) PostPullRequestMerge(w http.ResponseWriter, r *http.Request)
 			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "pull request not found")
 			return
 		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
 			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	status := api.PullRequestStatusOPEN
-	if pr.Status == model.StatusMerged {
-		status = api.PullRequestStatusMERGED
-	}
-
-	resp := api.PullRequest{
-		PullRequestId:     pr.PRID,
-		PullRequestName:   pr.PRName,
-		AuthorId:          pr.AuthorID,
-		AssignedReviewers: pr.AssignedReviewers,
-		Status:            status,
-		CreatedAt:         &pr.CreatedAt,
-		MergedAt:          pr.MergedAt,
-	}
+	resp := toAPIPullRequest(pr)
 	WriteJSON(w, http.StatusOK, map[string]interface{}{"pr": resp})
 }
 
@@ -129,7 +138,9 @@ func (h *PRHandler) PostPullRequestReassign(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	pr, newReviewerID, err := h.prService.ReassignReviewer(r.Context(), prID, oldReviewerID)
+	escalate := body.Escalate != nil && *body.Escalate
+
+	pr, newReviewerID, err := h.prService.ReassignReviewer(r.Context(), prID, oldReviewerID, escalate)
 	if err != nil {
 		switch err {
 		case int_errors.ErrPRNotFound:
@@ -147,25 +158,41 @@ func (h *PRHandler) PostPullRequestReassign(w http.ResponseWriter, r *http.Reque
 		case int_errors.ErrUserNotFound:
 			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "user not found")
 			return
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "only team leads may reassign other reviewers")
+			return
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+			return
 		}
 	}
 
-	status := api.PullRequestStatusOPEN
-	if pr.Status == model.StatusMerged {
-		status = api.PullRequestStatusMERGED
-	}
 	resp := PostPullRequestReassignResponse{
-		Pr: api.PullRequest{
-			PullRequestId:     pr.PRID,
-			PullRequestName:   pr.PRName,
-			AuthorId:          pr.AuthorID,
-			AssignedReviewers: pr.AssignedReviewers,
-			Status:            status,
-			CreatedAt:         &pr.CreatedAt,
-			MergedAt:          pr.MergedAt,
-		},
+		Pr:         toAPIPullRequest(pr),
 		ReplacedBy: newReviewerID,
 	}
 
 	WriteJSON(w, http.StatusOK, resp)
 }
+
+func toAPIPullRequest(pr *model.PullRequest) api.PullRequest {
+	status := api.PullRequestStatusOPEN
+	if pr.Status == model.StatusMerged {
+		status = api.PullRequestStatusMERGED
+	}
+
+	resp := api.PullRequest{
+		PullRequestId:     pr.PRID,
+		PullRequestName:   pr.PRName,
+		AuthorId:          pr.AuthorID,
+		AssignedReviewers: pr.AssignedReviewers,
+		Status:            status,
+		CreatedAt:         &pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+	}
+	if pr.TeamName != "" {
+		resp.TeamName = &pr.TeamName
+	}
+	return resp
+}
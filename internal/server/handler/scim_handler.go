@@ -0,0 +1,450 @@
+package handler
+
+import (
+	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/scim"
+	"avito-pr-service/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	scimContentType = "application/scim+json"
+
+	scimDefaultCount = 100
+	scimMaxCount     = 200
+)
+
+type SCIMHandler struct {
+	scimService *service.SCIMService
+}
+
+func NewSCIMHandler(scimService *service.SCIMService) *SCIMHandler {
+	return &SCIMHandler{scimService: scimService}
+}
+
+func (h *SCIMHandler) GetScimV2ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	writeSCIM(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{scim.SchemaServiceConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": scimMaxCount},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "API token or JWT with the team:admin scope",
+			"primary":     true,
+		}},
+		"meta": map[string]string{
+			"resourceType": "ServiceProviderConfig",
+			"location":     "/scim/v2/ServiceProviderConfig",
+		},
+	})
+}
+
+func (h *SCIMHandler) GetScimV2Users(w http.ResponseWriter, r *http.Request, params api.GetScimV2UsersParams) {
+	var filter model.UserFilter
+	if params.Filter != nil && strings.TrimSpace(*params.Filter) != "" {
+		f, err := scim.ParseFilter(*params.Filter)
+		if err != nil {
+			writeSCIMFailure(w, r, err)
+			return
+		}
+
+		value := f.Value
+		switch f.Attribute {
+		case "id", "username":
+			filter.UserID = &value
+		case "displayname":
+			filter.Username = &value
+		case "active":
+			active, err := strconv.ParseBool(value)
+			if err != nil {
+				writeSCIMError(w, http.StatusBadRequest, scim.InvalidFilter, "active must be compared with true or false")
+				return
+			}
+			filter.IsActive = &active
+		default:
+			writeSCIMError(w, http.StatusBadRequest, scim.InvalidFilter, "users can be filtered by id, userName, displayName or active")
+			return
+		}
+	}
+
+	startIndex, count := scimPage(params.StartIndex, params.Count)
+	users, total, err := h.scimService.ListUsers(r.Context(), filter, startIndex-1, count)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+
+	resources := make([]interface{}, len(users))
+	for i, u := range users {
+		resources[i] = toSCIMUser(u)
+	}
+
+	writeSCIM(w, http.StatusOK, api.ScimListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func (h *SCIMHandler) PostScimV2Users(w http.ResponseWriter, r *http.Request) {
+	var body api.ScimUser
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidSyntax, "invalid JSON: "+err.Error())
+		return
+	}
+
+	userID := strings.TrimSpace(body.UserName)
+	if userID == "" {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidValue, "userName must not be empty")
+		return
+	}
+
+	user := model.NewUser(userID, scimUsername(&body), "", true)
+	if body.Active != nil {
+		user.IsActive = *body.Active
+	}
+	if ext := body.UrnIetfParamsScimSchemasExtensionEnterprise20User; ext != nil && ext.Department != nil {
+		user.TeamName = strings.TrimSpace(*ext.Department)
+	}
+
+	created, err := h.scimService.CreateUser(r.Context(), user)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+
+	resp := toSCIMUser(created)
+	w.Header().Set("Location", resp.Meta.Location)
+	writeSCIM(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) GetScimV2UsersId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	user, err := h.scimService.GetUser(r.Context(), id)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMUser(user))
+}
+
+func (h *SCIMHandler) PatchScimV2UsersId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	ops, ok := readSCIMPatch(w, r)
+	if !ok {
+		return
+	}
+
+	patch, err := scim.ParseUserPatch(ops)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	if patch.UserID != nil && *patch.UserID != id {
+		writeSCIMError(w, http.StatusBadRequest, scim.Mutability, "userName is the user id and cannot be changed")
+		return
+	}
+	if patch.DisplayName != nil && strings.TrimSpace(*patch.DisplayName) == "" {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidValue, "displayName must not be empty")
+		return
+	}
+	if patch.Department != nil && strings.TrimSpace(*patch.Department) == "" {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidValue, "department must not be empty")
+		return
+	}
+
+	req := model.UpdateUserRequest{ID: id, Username: patch.DisplayName, TeamName: patch.Department}
+	user, err := h.scimService.UpdateUser(r.Context(), req, patch.Active)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMUser(user))
+}
+
+func (h *SCIMHandler) DeleteScimV2UsersId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	if err := h.scimService.DeleteUser(r.Context(), id); err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) GetScimV2Groups(w http.ResponseWriter, r *http.Request, params api.GetScimV2GroupsParams) {
+	var names []string
+	if params.Filter != nil && strings.TrimSpace(*params.Filter) != "" {
+		f, err := scim.ParseFilter(*params.Filter)
+		if err != nil {
+			writeSCIMFailure(w, r, err)
+			return
+		}
+		if f.Attribute != "id" && f.Attribute != "displayname" {
+			writeSCIMError(w, http.StatusBadRequest, scim.InvalidFilter, "groups can be filtered by id or displayName")
+			return
+		}
+		names = []string{f.Value}
+	}
+
+	teams, err := h.scimService.ListGroups(r.Context(), names...)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+
+	startIndex, count := scimPage(params.StartIndex, params.Count)
+	page := teams[min(startIndex-1, len(teams)):]
+	page = page[:min(count, len(page))]
+
+	resources := make([]interface{}, len(page))
+	for i, t := range page {
+		resources[i] = toSCIMGroup(t)
+	}
+
+	writeSCIM(w, http.StatusOK, api.ScimListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: len(teams),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func (h *SCIMHandler) PostScimV2Groups(w http.ResponseWriter, r *http.Request) {
+	var body api.ScimGroup
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidSyntax, "invalid JSON: "+err.Error())
+		return
+	}
+
+	teamName := strings.TrimSpace(body.DisplayName)
+	if teamName == "" {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidValue, "displayName must not be empty")
+		return
+	}
+
+	var memberIDs []string
+	if body.Members != nil {
+		for _, m := range *body.Members {
+			memberIDs = append(memberIDs, m.Value)
+		}
+	}
+
+	team, err := h.scimService.CreateGroup(r.Context(), teamName, memberIDs)
+	if err != nil {
+		if err == int_errors.ErrUserNotFound {
+			writeSCIMError(w, http.StatusBadRequest, scim.InvalidValue, "unknown member")
+			return
+		}
+		writeSCIMFailure(w, r, err)
+		return
+	}
+
+	resp := toSCIMGroup(team)
+	w.Header().Set("Location", resp.Meta.Location)
+	writeSCIM(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) GetScimV2GroupsId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	team, err := h.scimService.GetGroup(r.Context(), id)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMGroup(team))
+}
+
+func (h *SCIMHandler) PatchScimV2GroupsId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	ops, ok := readSCIMPatch(w, r)
+	if !ok {
+		return
+	}
+
+	patch, err := scim.ParseGroupPatch(ops)
+	if err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	// The team name is the group's id; renaming goes through /team/rename.
+	if patch.DisplayName != nil && *patch.DisplayName != id {
+		writeSCIMError(w, http.StatusBadRequest, scim.Mutability, "displayName is the group id and cannot be changed, use /team/rename")
+		return
+	}
+
+	team, err := h.scimService.UpdateGroupMembers(r.Context(), id, patch.Members)
+	if err != nil {
+		if err == int_errors.ErrUserNotFound {
+			writeSCIMError(w, http.StatusBadRequest, scim.InvalidValue, "unknown member")
+			return
+		}
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMGroup(team))
+}
+
+func (h *SCIMHandler) DeleteScimV2GroupsId(w http.ResponseWriter, r *http.Request, id api.ScimIdPath) {
+	if err := h.scimService.DeleteGroup(r.Context(), id); err != nil {
+		writeSCIMFailure(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// scimUsername picks the username from displayName, then name.formatted, then
+// given and family name, and falls back to userName.
+func scimUsername(u *api.ScimUser) string {
+	if u.DisplayName != nil && strings.TrimSpace(*u.DisplayName) != "" {
+		return strings.TrimSpace(*u.DisplayName)
+	}
+	if n := u.Name; n != nil {
+		if n.Formatted != nil && strings.TrimSpace(*n.Formatted) != "" {
+			return strings.TrimSpace(*n.Formatted)
+		}
+		var parts []string
+		for _, p := range []*string{n.GivenName, n.FamilyName} {
+			if p != nil && strings.TrimSpace(*p) != "" {
+				parts = append(parts, strings.TrimSpace(*p))
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, " ")
+		}
+	}
+	return strings.TrimSpace(u.UserName)
+}
+
+// scimPage normalises startIndex (1-based) and count as RFC 7644 asks: values
+// below the minimum are raised to it rather than rejected.
+func scimPage(startIndex, count *int) (int, int) {
+	start, n := 1, scimDefaultCount
+	if startIndex != nil && *startIndex > 1 {
+		start = *startIndex
+	}
+	if count != nil {
+		n = min(max(*count, 0), scimMaxCount)
+	}
+	return start, n
+}
+
+func readSCIMPatch(w http.ResponseWriter, r *http.Request) ([]scim.Operation, bool) {
+	var body api.ScimPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidSyntax, "invalid JSON: "+err.Error())
+		return nil, false
+	}
+	if len(body.Operations) == 0 {
+		writeSCIMError(w, http.StatusBadRequest, scim.InvalidSyntax, "Operations must not be empty")
+		return nil, false
+	}
+
+	ops := make([]scim.Operation, len(body.Operations))
+	for i, op := range body.Operations {
+		ops[i] = scim.Operation{Op: op.Op, Value: op.Value}
+		if op.Path != nil {
+			ops[i].Path = *op.Path
+		}
+	}
+	return ops, true
+}
+
+func toSCIMUser(u *model.User) api.ScimUser {
+	location := "/scim/v2/Users/" + url.PathEscape(u.ID)
+
+	groups := make([]api.ScimMemberRef, len(u.Memberships))
+	for i, m := range u.Memberships {
+		ref := "/scim/v2/Groups/" + url.PathEscape(m.TeamName)
+		groups[i] = api.ScimMemberRef{Value: m.TeamName, Display: &m.TeamName, Ref: &ref}
+	}
+
+	return api.ScimUser{
+		Schemas:     []string{scim.SchemaUser, scim.SchemaEnterpriseUser},
+		Id:          &u.ID,
+		UserName:    u.ID,
+		DisplayName: &u.Username,
+		Name:        &api.ScimName{Formatted: &u.Username},
+		Active:      &u.IsActive,
+		Groups:      &groups,
+		UrnIetfParamsScimSchemasExtensionEnterprise20User: &api.ScimEnterpriseUser{
+			Department: &u.TeamName,
+		},
+		Meta: &api.ScimMeta{ResourceType: api.ScimMetaResourceTypeUser, Location: location},
+	}
+}
+
+func toSCIMGroup(t *model.Team) api.ScimGroup {
+	location := "/scim/v2/Groups/" + url.PathEscape(t.TeamName)
+
+	members := make([]api.ScimMemberRef, len(t.Members))
+	for i, m := range t.Members {
+		ref := "/scim/v2/Users/" + url.PathEscape(m.UserID)
+		members[i] = api.ScimMemberRef{Value: m.UserID, Display: &m.Username, Ref: &ref}
+	}
+
+	return api.ScimGroup{
+		Schemas:     []string{scim.SchemaGroup},
+		Id:          &t.TeamName,
+		DisplayName: t.TeamName,
+		Members:     &members,
+		Meta:        &api.ScimMeta{ResourceType: api.ScimMetaResourceTypeGroup, Location: location},
+	}
+}
+
+// writeSCIMFailure maps service errors onto SCIM error responses.
+func writeSCIMFailure(w http.ResponseWriter, r *http.Request, err error) {
+	var scimErr *scim.Error
+	if errors.As(err, &scimErr) {
+		writeSCIMError(w, http.StatusBadRequest, scimErr.Type, scimErr.Detail)
+		return
+	}
+
+	switch err {
+	case int_errors.ErrForbidden:
+		writeSCIMError(w, http.StatusForbidden, "", "SCIM provisioning requires a token not bound to a user")
+	case int_errors.ErrUserNotFound:
+		writeSCIMError(w, http.StatusNotFound, "", "user not found")
+	case int_errors.ErrTeamNotFound:
+		writeSCIMError(w, http.StatusNotFound, "", "group not found")
+	case int_errors.ErrUserExists:
+		writeSCIMError(w, http.StatusConflict, scim.Uniqueness, "user already exists")
+	case int_errors.ErrTeamExists:
+		writeSCIMError(w, http.StatusConflict, scim.Uniqueness, "group already exists")
+	case int_errors.ErrUserHasOpenPullRequests:
+		writeSCIMError(w, http.StatusConflict, "", "user authors open pull requests; deactivate instead")
+	default:
+		logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+		writeSCIMError(w, http.StatusInternalServerError, "", "internal error: "+err.Error())
+	}
+}
+
+func writeSCIMError(w http.ResponseWriter, status int, scimType, detail string) {
+	resp := api.ScimError{
+		Schemas: []string{scim.SchemaError},
+		Status:  strconv.Itoa(status),
+		Detail:  &detail,
+	}
+	if scimType != "" {
+		t := api.ScimErrorScimType(scimType)
+		resp.ScimType = &t
+	}
+	writeSCIM(w, status, resp)
+}
+
+func writeSCIM(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", scimContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
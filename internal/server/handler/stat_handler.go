@@ -1,11 +1,40 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
+	"avito-pr-service/internal/model"
 	"avito-pr-service/internal/service"
 )
 
+const (
+	statisticsDefaultLimit = 100
+	statisticsMaxLimit     = 1000
+
+	// historyMaxDays bounds the daily series; coarser ones may span ten times
+	// as long.
+	historyMaxDays = 366
+
+	// fairnessDefaultTolerance flags members off the team mean by more than
+	// half of it.
+	fairnessDefaultTolerance = 0.5
+
+	pairsDefaultTop = 10
+	pairsMaxTop     = 100
+)
+
 type StatisticsHandler struct {
 	service *service.StatisticsService
 }
@@ -14,11 +43,311 @@ func NewStatisticsHandler(service *service.StatisticsService) *StatisticsHandler
 	return &StatisticsHandler{service: service}
 }
 
-func (h *StatisticsHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.service.GetStatistics(r.Context())
+func (h *StatisticsHandler) GetStatistics(w http.ResponseWriter, r *http.Request, params api.GetStatisticsParams) {
+	filter := model.StatisticsFilter{
+		Range: model.TimeRange{From: params.From, To: params.To},
+	}
+	if params.TeamName != nil {
+		teamName := strings.TrimSpace(*params.TeamName)
+		if teamName == "" {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name must not be empty")
+			return
+		}
+		filter.TeamName = &teamName
+	}
+	if filter.Range.From != nil && filter.Range.To != nil && !filter.Range.From.Before(*filter.Range.To) {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "from must be before to")
+		return
+	}
+	if params.Status != nil {
+		switch *params.Status {
+		case api.OPEN, api.MERGED:
+			status := string(*params.Status)
+			filter.Status = &status
+		default:
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "status must be OPEN or MERGED")
+			return
+		}
+	}
+
+	prPage, ok := statisticsPage(w, "pr", params.PrOffset, params.PrLimit)
+	if !ok {
+		return
+	}
+	reviewerPage, ok := statisticsPage(w, "reviewers", params.ReviewersOffset, params.ReviewersLimit)
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.GetStatistics(r.Context(), filter, prPage, reviewerPage)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+// statisticsPage validates the <list>_offset and <list>_limit parameters,
+// writing a 400 response when they are out of range.
+func statisticsPage(w http.ResponseWriter, list string, offset, limit *int) (model.Page, bool) {
+	page := model.Page{Limit: statisticsDefaultLimit}
+	if offset != nil {
+		if *offset < 0 {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, list+"_offset must not be negative")
+			return page, false
+		}
+		page.Offset = *offset
+	}
+	if limit != nil {
+		if *limit < 0 || *limit > statisticsMaxLimit {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, fmt.Sprintf("%s_limit must be between 0 and %d", list, statisticsMaxLimit))
+			return page, false
+		}
+		page.Limit = *limit
+	}
+	return page, true
+}
+
+func (h *StatisticsHandler) GetStatisticsSubtree(w http.ResponseWriter, r *http.Request, params api.GetStatisticsSubtreeParams) {
+	teamName := strings.TrimSpace(params.TeamName)
+	if teamName == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name must not be empty")
+		return
+	}
+
+	stats, err := h.service.GetSubtreeStatistics(r.Context(), teamName)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+func (h *StatisticsHandler) GetStatisticsTiming(w http.ResponseWriter, r *http.Request, params api.GetStatisticsTimingParams) {
+	rng := model.TimeRange{From: params.From, To: params.To}
+	if rng.From != nil && rng.To != nil && !rng.From.Before(*rng.To) {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "from must be before to")
+		return
+	}
+
+	stats, err := h.service.GetTimingStatistics(r.Context(), rng)
 	if err != nil {
+		logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
 		http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	WriteJSON(w, http.StatusOK, stats)
 }
+
+func (h *StatisticsHandler) GetStatisticsHistory(w http.ResponseWriter, r *http.Request, params api.GetStatisticsHistoryParams) {
+	q := model.HistoryQuery{Granularity: model.GranularityDay}
+	if params.Granularity != nil {
+		switch *params.Granularity {
+		case api.Day, api.Week, api.Month:
+			q.Granularity = string(*params.Granularity)
+		default:
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "granularity must be day, week or month")
+			return
+		}
+	}
+	if params.TeamName != nil {
+		teamName := strings.TrimSpace(*params.TeamName)
+		if teamName == "" {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name must not be empty")
+			return
+		}
+		q.TeamName = &teamName
+	}
+
+	now := time.Now().UTC()
+	q.To = time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+	if params.To != nil {
+		q.To = params.To.Time
+	}
+	switch {
+	case params.From != nil:
+		q.From = params.From.Time
+	case q.Granularity == model.GranularityWeek:
+		q.From = q.To.AddDate(0, 0, -26*7+1)
+	case q.Granularity == model.GranularityMonth:
+		q.From = q.To.AddDate(-1, 0, 1)
+	default:
+		q.From = q.To.AddDate(0, 0, -89)
+	}
+
+	maxDays := historyMaxDays
+	if q.Granularity != model.GranularityDay {
+		maxDays *= 10
+	}
+	if q.From.After(q.To) {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "from must not be after to")
+		return
+	}
+	if q.To.Sub(q.From) >= time.Duration(maxDays)*24*time.Hour {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, fmt.Sprintf("a %s series may span at most %d days", q.Granularity, maxDays))
+		return
+	}
+
+	history, err := h.service.GetStatisticsHistory(r.Context(), q)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	WriteJSON(w, http.StatusOK, history)
+}
+
+func (h *StatisticsHandler) GetStatisticsFairness(w http.ResponseWriter, r *http.Request, params api.GetStatisticsFairnessParams) {
+	var teamName *string
+	if params.TeamName != nil {
+		name := strings.TrimSpace(*params.TeamName)
+		if name == "" {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name must not be empty")
+			return
+		}
+		teamName = &name
+	}
+	rng := model.TimeRange{From: params.From, To: params.To}
+	if rng.From != nil && rng.To != nil && !rng.From.Before(*rng.To) {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "from must be before to")
+		return
+	}
+	tolerance := fairnessDefaultTolerance
+	if params.Tolerance != nil {
+		tolerance = float64(*params.Tolerance)
+		if tolerance < 0 || math.IsNaN(tolerance) || math.IsInf(tolerance, 0) {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "tolerance must be a non-negative number")
+			return
+		}
+	}
+
+	report, err := h.service.GetFairness(r.Context(), teamName, rng, tolerance)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	WriteJSON(w, http.StatusOK, report)
+}
+
+func (h *StatisticsHandler) GetStatisticsPairs(w http.ResponseWriter, r *http.Request, params api.GetStatisticsPairsParams) {
+	teamName := strings.TrimSpace(params.TeamName)
+	if teamName == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name is required")
+		return
+	}
+	rng := model.TimeRange{From: params.From, To: params.To}
+	if rng.From != nil && rng.To != nil && !rng.From.Before(*rng.To) {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "from must be before to")
+		return
+	}
+	top := pairsDefaultTop
+	if params.Top != nil {
+		top = *params.Top
+		if top < 0 || top > pairsMaxTop {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, fmt.Sprintf("top must be between 0 and %d", pairsMaxTop))
+			return
+		}
+	}
+	format := api.Json
+	if params.Format != nil {
+		switch *params.Format {
+		case api.Json, api.Csv:
+			format = *params.Format
+		default:
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "format must be json or csv")
+			return
+		}
+	}
+
+	matrix, err := h.service.GetPairs(r.Context(), teamName, rng, top)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if format == api.Json {
+		WriteJSON(w, http.StatusOK, matrix)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writePairMatrixCSV(&buf, matrix); err != nil {
+		logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+		http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="pairs.csv"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// writePairMatrixCSV writes the matrix alone: a header of reviewer IDs, then
+// a row per author.
+func writePairMatrixCSV(w io.Writer, m *model.PairMatrix) error {
+	cw := csv.NewWriter(w)
+	header := []string{"author_id"}
+	for _, member := range m.Members {
+		header = append(header, member.UserID)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i, member := range m.Members {
+		record := []string{member.UserID}
+		for _, n := range m.Matrix[i] {
+			record = append(record, strconv.Itoa(n))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (h *StatisticsHandler) PostAdminStatisticsRebuild(w http.ResponseWriter, r *http.Request) {
+	check, err := h.service.RebuildCounters(r.Context())
+	if err != nil {
+		switch err {
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "rebuilding counters requires a token not bound to a user")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+	WriteJSON(w, http.StatusOK, check)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+
+	"avito-pr-service/internal/model"
+)
+
+func TestWritePairMatrixCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix model.PairMatrix
+		want   string
+	}{
+		{
+			name: "authors by reviewers",
+			matrix: model.BuildPairMatrix(
+				[]model.PairMember{{UserID: "u2"}, {UserID: "u1"}, {UserID: "u,3"}},
+				[]model.ReviewPair{
+					{AuthorID: "u1", ReviewerID: "u2", Assignments: 4},
+					{AuthorID: "u2", ReviewerID: "u,3", Assignments: 1},
+				},
+				10,
+			),
+			want: "author_id,u2,u1,\"u,3\"\n" +
+				"u2,0,0,1\n" +
+				"u1,4,0,0\n" +
+				"\"u,3\",0,0,0\n",
+		},
+		{
+			name:   "no members",
+			matrix: model.BuildPairMatrix(nil, nil, 10),
+			want:   "author_id\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writePairMatrixCSV(&buf, &tt.matrix); err != nil {
+				t.Fatalf("writePairMatrixCSV() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writePairMatrixCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
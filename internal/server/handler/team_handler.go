@@ -3,6 +3,8 @@ package handler
 import (
 	"avito-pr-service/internal/api"
 	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
 	"avito-pr-service/internal/model"
 	"avito-pr-service/internal/service"
 	"encoding/json"
@@ -34,24 +36,13 @@ func (h *TeamHandler) GetTeamGet(w http.ResponseWriter, r *http.Request, params
 			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
 			return
 		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
 			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	apiMembers := make([]api.TeamMember, 0, len(team.Members))
-	for _, m := range team.Members {
-		apiMembers = append(apiMembers, api.TeamMember{
-			UserId:   m.ID,
-			Username: m.Username,
-			IsActive: m.IsActive,
-		})
-	}
-
-	resp := api.Team{
-		TeamName: team.TeamName,
-		Members:  apiMembers,
-	}
+	resp := toAPITeam(team)
 	WriteJSON(w, http.StatusOK, map[string]interface{}{"team": resp})
 }
 
@@ -69,7 +60,7 @@ func (h *TeamHandler) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	members := make([]*model.User, 0, len(body.Members))
+	members := make([]*model.TeamMember, 0, len(body.Members))
 
 	for i, m := range body.Members {
 		uid := strings.TrimSpace(m.UserId)
@@ -82,9 +73,21 @@ func (h *TeamHandler) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		members = append(members,
-			model.NewUser(uid, username, teamName, m.IsActive),
-		)
+		role := model.RoleMember
+		if m.Role != nil {
+			role = string(*m.Role)
+		}
+		if !model.IsValidRole(role) {
+			http.Error(w, "unknown role "+role+" for member "+uid, http.StatusBadRequest)
+			return
+		}
+
+		members = append(members, &model.TeamMember{
+			UserID:   uid,
+			Username: username,
+			Role:     role,
+			IsActive: m.IsActive,
+		})
 	}
 
 	team, err := h.teamService.CreateTeam(r.Context(), &model.Team{
@@ -96,24 +99,261 @@ func (h *TeamHandler) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 		case int_errors.ErrUserHasOpenPullRequests:
 			WriteJSONError(w, http.StatusBadRequest, api.PREXISTS, "some users have open PRs")
 			return
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "only team leads may change the team, and only list users of teams they lead")
+			return
 		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
 			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
+	resp := toAPITeam(team)
+	WriteJSON(w, http.StatusCreated, map[string]interface{}{"team": resp})
+}
+
+func (h *TeamHandler) PostTeamSetParent(w http.ResponseWriter, r *http.Request) {
+	var body api.PostTeamSetParentJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	teamName := strings.TrimSpace(body.TeamName)
+	if teamName == "" {
+		http.Error(w, "team_name must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	var parentTeam *string
+	if body.ParentTeam != nil {
+		parent := strings.TrimSpace(*body.ParentTeam)
+		if parent == "" {
+			http.Error(w, "parent_team must not be empty", http.StatusBadRequest)
+			return
+		}
+		parentTeam = &parent
+	}
+
+	node, err := h.teamService.SetParent(r.Context(), teamName, parentTeam)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+			return
+		case int_errors.ErrTeamHierarchyCycle:
+			WriteJSONError(w, http.StatusConflict, api.INVALIDPARENT, "team hierarchy would contain a cycle")
+			return
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "only leads of the team and its new parent may move it")
+			return
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"team": toAPITeamNode(node)})
+}
+
+func (h *TeamHandler) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	var body api.PostTeamRenameJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "invalid JSON")
+		return
+	}
+
+	teamName := strings.TrimSpace(body.TeamName)
+	newTeamName := strings.TrimSpace(body.NewTeamName)
+	if teamName == "" || newTeamName == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name and new_team_name must not be empty")
+		return
+	}
+	if teamName == newTeamName {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "new_team_name must differ from team_name")
+		return
+	}
+
+	team, err := h.teamService.RenameTeam(r.Context(), teamName, newTeamName)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		case int_errors.ErrTeamExists:
+			WriteJSONError(w, http.StatusConflict, api.TEAMEXISTS, newTeamName+" already exists")
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, err.Error())
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"team": toAPITeam(team)})
+}
+
+func (h *TeamHandler) GetTeamSubtree(w http.ResponseWriter, r *http.Request, params api.GetTeamSubtreeParams) {
+	teamName := strings.TrimSpace(params.TeamName)
+	if teamName == "" {
+		http.Error(w, "team_name must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	root, err := h.teamService.GetSubtree(r.Context(), teamName)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+			return
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"team": toAPITeamNode(root)})
+}
+
+func (h *TeamHandler) GetTeamReviewSettings(w http.ResponseWriter, r *http.Request, params api.GetTeamReviewSettingsParams) {
+	teamName := strings.TrimSpace(params.TeamName)
+	if teamName == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name must not be empty")
+		return
+	}
+
+	settings, err := h.teamService.GetReviewSettings(r.Context(), teamName)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toAPIReviewSettings(settings))
+}
+
+func (h *TeamHandler) PostTeamReviewSettings(w http.ResponseWriter, r *http.Request) {
+	var body api.ReviewSettings
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "invalid JSON")
+		return
+	}
+
+	settings := &model.ReviewSettings{
+		TeamName:         strings.TrimSpace(body.TeamName),
+		PairingDiversity: body.PairingDiversity,
+		LookbackDays:     body.LookbackDays,
+		LookbackPRs:      body.LookbackPrs,
+	}
+	if settings.TeamName == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name must not be empty")
+		return
+	}
+	if body.Strategy != nil {
+		switch *body.Strategy {
+		case api.Random, api.RoundRobin:
+			settings.Strategy = string(*body.Strategy)
+		default:
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "strategy must be random or round_robin")
+			return
+		}
+	} else {
+		// Omitting strategy keeps the team's current one.
+		current, err := h.teamService.GetReviewSettings(r.Context(), settings.TeamName)
+		if err != nil {
+			switch err {
+			case int_errors.ErrTeamNotFound:
+				WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+			default:
+				logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+				WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+			}
+			return
+		}
+		settings.Strategy = current.Strategy
+	}
+	if settings.Strategy == model.StrategyRoundRobin && settings.PairingDiversity {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "pairing_diversity applies to the random strategy only")
+		return
+	}
+	if (settings.LookbackDays != nil && *settings.LookbackDays < 1) || (settings.LookbackPRs != nil && *settings.LookbackPRs < 1) {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "lookback_days and lookback_prs must be positive")
+		return
+	}
+	if settings.LookbackDays != nil && settings.LookbackPRs != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "set either lookback_days or lookback_prs, not both")
+		return
+	}
+	if settings.PairingDiversity && settings.LookbackDays == nil && settings.LookbackPRs == nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "pairing_diversity requires lookback_days or lookback_prs")
+		return
+	}
+
+	settings, err := h.teamService.SetReviewSettings(r.Context(), settings)
+	if err != nil {
+		switch err {
+		case int_errors.ErrTeamNotFound:
+			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "only team leads may change the team")
+		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+			WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toAPIReviewSettings(settings))
+}
+
+func toAPIReviewSettings(settings *model.ReviewSettings) api.ReviewSettings {
+	strategy := api.ReviewSettingsStrategy(settings.Strategy)
+	return api.ReviewSettings{
+		TeamName:         settings.TeamName,
+		Strategy:         &strategy,
+		PairingDiversity: settings.PairingDiversity,
+		LookbackDays:     settings.LookbackDays,
+		LookbackPrs:      settings.LookbackPRs,
+	}
+}
+
+func toAPITeamNode(node *model.TeamNode) api.TeamNode {
+	resp := api.TeamNode{
+		TeamName:   node.TeamName,
+		ParentTeam: node.ParentTeam,
+	}
+	if node.Children != nil {
+		children := make([]api.TeamNode, 0, len(node.Children))
+		for _, child := range node.Children {
+			children = append(children, toAPITeamNode(child))
+		}
+		resp.Children = &children
+	}
+	return resp
+}
+
+func toAPITeam(team *model.Team) api.Team {
 	apiMembers := make([]api.TeamMember, 0, len(team.Members))
 	for _, m := range team.Members {
+		role := api.TeamRole(m.Role)
 		apiMembers = append(apiMembers, api.TeamMember{
-			UserId:   m.ID,
+			UserId:   m.UserID,
 			Username: m.Username,
 			IsActive: m.IsActive,
+			Role:     &role,
 		})
 	}
 
-	resp := api.Team{
+	return api.Team{
 		TeamName: team.TeamName,
 		Members:  apiMembers,
 	}
-	WriteJSON(w, http.StatusCreated, map[string]interface{}{"team": resp})
 }
@@ -3,6 +3,8 @@ package handler
 import (
 	"avito-pr-service/internal/api"
 	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
 	"avito-pr-service/internal/model"
 	"avito-pr-service/internal/service"
 	"encoding/json"
@@ -33,6 +35,7 @@ func (h *UserHandler) GetUsersGetReview(w http.ResponseWriter, r *http.Request,
 
 	prList, err := h.userService.GetByReviewer(r.Context(), userId)
 	if err != nil {
+		logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
 		http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -43,9 +46,9 @@ func (h *UserHandler) GetUsersGetReview(w http.ResponseWriter, r *http.Request,
 	}
 
 	for _, pr := range prList {
-		status := api.OPEN
+		status := api.PullRequestShortStatusOPEN
 		if pr.Status == model.StatusMerged {
-			status = api.MERGED
+			status = api.PullRequestShortStatusMERGED
 		}
 		resp.PullRequests = append(resp.PullRequests, api.PullRequestShort{
 			PullRequestId:   pr.PRID,
@@ -77,18 +80,185 @@ func (h *UserHandler) PostUsersSetIsActive(w http.ResponseWriter, r *http.Reques
 		case int_errors.ErrUserNotFound:
 			WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "user not found")
 			return
+		case int_errors.ErrForbidden:
+			WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "only leads of the user's team may change activity")
+			return
 		default:
+			logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
 			http.Error(w, "internal error: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	apiUser := api.User{
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"user": toAPIUser(u)})
+}
+
+type UsersDeleteResponse struct {
+	UserID            string                   `json:"user_id"`
+	ReassignedReviews []api.ReviewReassignment `json:"reassigned_reviews"`
+}
+
+func (h *UserHandler) GetUsersGet(w http.ResponseWriter, r *http.Request, params api.GetUsersGetParams) {
+	userID := strings.TrimSpace(params.UserId)
+	if userID == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "user_id must not be empty")
+		return
+	}
+
+	u, err := h.userService.GetUser(r.Context(), userID)
+	if err != nil {
+		writeUserError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"user": toAPIUser(u)})
+}
+
+func (h *UserHandler) PostUsersCreate(w http.ResponseWriter, r *http.Request) {
+	var body api.PostUsersCreateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "invalid JSON")
+		return
+	}
+
+	userID := strings.TrimSpace(body.UserId)
+	username := strings.TrimSpace(body.Username)
+	teamName := strings.TrimSpace(body.TeamName)
+	if userID == "" || username == "" || teamName == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "user_id, username and team_name must not be empty")
+		return
+	}
+
+	isActive := true
+	if body.IsActive != nil {
+		isActive = *body.IsActive
+	}
+
+	u, err := h.userService.CreateUser(r.Context(), model.NewUser(userID, username, teamName, isActive))
+	if err != nil {
+		writeUserError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, map[string]interface{}{"user": toAPIUser(u)})
+}
+
+func (h *UserHandler) PostUsersUpdate(w http.ResponseWriter, r *http.Request) {
+	var body api.PostUsersUpdateJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "invalid JSON")
+		return
+	}
+
+	req := model.UpdateUserRequest{ID: strings.TrimSpace(body.UserId)}
+	if req.ID == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "user_id must not be empty")
+		return
+	}
+
+	if body.Username != nil {
+		username := strings.TrimSpace(*body.Username)
+		if username == "" {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "username must not be empty")
+			return
+		}
+		req.Username = &username
+	}
+
+	if body.TeamName != nil {
+		teamName := strings.TrimSpace(*body.TeamName)
+		if teamName == "" {
+			WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "team_name must not be empty")
+			return
+		}
+		req.TeamName = &teamName
+	}
+
+	if req.Username == nil && req.TeamName == nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "nothing to update")
+		return
+	}
+
+	u, err := h.userService.UpdateUser(r.Context(), req)
+	if err != nil {
+		writeUserError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{"user": toAPIUser(u)})
+}
+
+func (h *UserHandler) PostUsersDelete(w http.ResponseWriter, r *http.Request) {
+	var body api.PostUsersDeleteJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "invalid JSON")
+		return
+	}
+
+	userID := strings.TrimSpace(body.UserId)
+	if userID == "" {
+		WriteJSONError(w, http.StatusBadRequest, api.BADREQUEST, "user_id must not be empty")
+		return
+	}
+
+	reassignments, err := h.userService.DeleteUser(r.Context(), userID)
+	if err != nil {
+		writeUserError(w, r, err)
+		return
+	}
+
+	resp := UsersDeleteResponse{
+		UserID:            userID,
+		ReassignedReviews: make([]api.ReviewReassignment, 0, len(reassignments)),
+	}
+	for _, ra := range reassignments {
+		resp.ReassignedReviews = append(resp.ReassignedReviews, api.ReviewReassignment{
+			PullRequestId: ra.PRID,
+			ReplacedBy:    ra.ReplacedBy,
+		})
+	}
+
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+func writeUserError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case int_errors.ErrUserNotFound:
+		WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "user not found")
+	case int_errors.ErrTeamNotFound:
+		WriteJSONError(w, http.StatusNotFound, api.NOTFOUND, "team not found")
+	case int_errors.ErrUserExists:
+		WriteJSONError(w, http.StatusConflict, api.USEREXISTS, "user already exists")
+	case int_errors.ErrUserHasOpenPullRequests:
+		WriteJSONError(w, http.StatusConflict, api.USERHASOPENPRS, "user has open pull requests")
+	case int_errors.ErrUserHasHistory:
+		WriteJSONError(w, http.StatusConflict, api.USERHASHISTORY, "user has pull request history; deactivate them instead")
+	case int_errors.ErrForbidden:
+		WriteJSONError(w, http.StatusForbidden, api.FORBIDDEN, "only leads of the user's teams may change the user")
+	default:
+		logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+		WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+	}
+}
+
+func toAPIUser(u *model.User) api.User {
+	resp := api.User{
 		UserId:   u.ID,
 		Username: u.Username,
 		TeamName: u.TeamName,
 		IsActive: u.IsActive,
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]interface{}{"team": apiUser})
+	if u.Memberships != nil {
+		teams := make([]api.TeamMembership, 0, len(u.Memberships))
+		for _, m := range u.Memberships {
+			teams = append(teams, api.TeamMembership{
+				TeamName: m.TeamName,
+				Role:     api.TeamRole(m.Role),
+				IsActive: m.IsActive,
+			})
+		}
+		resp.Teams = &teams
+	}
+	return resp
 }
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/auth"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/lib/logger"
+	"avito-pr-service/internal/lib/logger/sl"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/server/handler"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (*model.APIToken, error)
+}
+
+type JWTVerifier interface {
+	Verify(ctx context.Context, raw string) (*auth.Principal, error)
+}
+
+// Auth checks the bearer token of operations that declare bearerAuth in
+// openapi.yaml. The generated router puts the scopes an operation requires
+// into the request context; operations without security are passed through.
+// It must be installed through api.ChiServerOptions.Middlewares so that it
+// runs after that context value is set.
+//
+// When jwtVerifier is not nil, bearer values shaped like a JWT are verified
+// as SSO tokens; everything else is looked up as an API token.
+func Auth(tokens TokenAuthenticator, jwtVerifier JWTVerifier) api.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			required, ok := r.Context().Value(api.BearerAuthScopes).([]string)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			secret, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				handler.WriteJSONError(w, http.StatusUnauthorized, api.UNAUTHORIZED, "missing bearer token")
+				return
+			}
+
+			var (
+				principal *auth.Principal
+				err       error
+			)
+			if jwtVerifier != nil && looksLikeJWT(secret) {
+				principal, err = jwtVerifier.Verify(r.Context(), secret)
+			} else {
+				principal, err = tokenPrincipal(r.Context(), tokens, secret)
+			}
+			if err != nil {
+				if errors.Is(err, int_errors.ErrUnauthorized) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					handler.WriteJSONError(w, http.StatusUnauthorized, api.UNAUTHORIZED, "invalid or revoked token")
+					return
+				}
+				logger.FromContext(r.Context()).Error("request failed", sl.Err(err))
+				handler.WriteJSONError(w, http.StatusInternalServerError, api.INTERNAL, "internal error: "+err.Error())
+				return
+			}
+
+			for _, scope := range required {
+				if !principal.HasScope(scope) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+					handler.WriteJSONError(w, http.StatusForbidden, api.INSUFFICIENTSCOPE, "token lacks scope "+scope)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+func tokenPrincipal(ctx context.Context, tokens TokenAuthenticator, secret string) (*auth.Principal, error) {
+	token, err := tokens.Authenticate(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	principal := &auth.Principal{
+		TokenID: token.ID,
+		Name:    token.Name,
+		Scopes:  token.Scopes,
+	}
+	if token.UserID != nil {
+		principal.UserID = *token.UserID
+	}
+	return principal, nil
+}
+
+func looksLikeJWT(s string) bool {
+	return strings.Count(s, ".") == 2
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"avito-pr-service/internal/api"
+	"avito-pr-service/internal/auth"
+	"avito-pr-service/internal/lib/logger/sl"
+	"avito-pr-service/internal/ratelimit"
+	"avito-pr-service/internal/server/handler"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit applies a token bucket per client and route. A client is the
+// token or SSO user verified by Auth, or its IP address when authentication is
+// disabled; an unverified bearer value is never used, since anyone could mint
+// a fresh bucket per request with it. It must come before Auth in
+// api.ChiServerOptions.Middlewares, so that Auth wraps it and runs first.
+//
+// Operations without security in openapi.yaml, i.e. health probes, are not
+// limited.
+//
+// Limiter errors are logged and the request is let through: a broken shared
+// limiter must not take the API down with it.
+func RateLimit(log *slog.Logger, limiter ratelimit.Limiter, def ratelimit.Limit, routes map[string]ratelimit.Limit) api.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, secured := r.Context().Value(api.BearerAuthScopes).([]string); !secured {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			limit, ok := routes[r.URL.Path]
+			if !ok {
+				limit = def
+			}
+
+			res, err := limiter.Allow(r.Context(), r.URL.Path+"|"+clientKey(r), limit)
+			if err != nil {
+				log.ErrorContext(r.Context(), "rate limiter failed", slog.String("path", r.URL.Path), sl.Err(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+			if !res.Allowed {
+				retry := ceilSeconds(res.RetryAfter)
+				w.Header().Set("Retry-After", strconv.Itoa(retry))
+				handler.WriteJSONError(w, http.StatusTooManyRequests, api.RATELIMITED,
+					fmt.Sprintf("rate limit exceeded, retry in %ds", retry))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientKey(r *http.Request) string {
+	if p, ok := auth.FromContext(r.Context()); ok {
+		if p.TokenID != "" {
+			return "token:" + p.TokenID
+		}
+		return "user:" + p.UserID
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"avito-pr-service/internal/auth"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const tokenPrefix = "prs_"
+
+type AuthService struct {
+	tokenRepo repository.TokenRepository
+}
+
+func NewAuthService(tokenRepo repository.TokenRepository) *AuthService {
+	return &AuthService{tokenRepo: tokenRepo}
+}
+
+// CreateToken mints a new API token. The returned secret is shown to the
+// caller once; only its hash is stored. Tokens are minted by callers not bound
+// to a user, and never with a scope the caller does not hold itself.
+func (s *AuthService) CreateToken(ctx context.Context, name string, userID *string, scopes []string) (*model.APIToken, string, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, "", err
+	}
+	if err := validateScopes(scopes); err != nil {
+		return nil, "", err
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		for _, scope := range scopes {
+			if !p.HasScope(scope) {
+				return nil, "", int_errors.ErrForbidden
+			}
+		}
+	}
+
+	id, err := randomString(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return nil, "", err
+	}
+	secret = tokenPrefix + secret
+
+	token, err := s.tokenRepo.CreateToken(ctx, &model.APIToken{
+		ID:     "tok_" + id,
+		Name:   name,
+		UserID: userID,
+		Scopes: scopes,
+	}, HashToken(secret))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return token, secret, nil
+}
+
+// EnsureToken registers a token with a caller-chosen ID and secret, such as the
+// first admin token bootstrapped from the config file.
+func (s *AuthService) EnsureToken(ctx context.Context, id, name, secret string, scopes []string) (*model.APIToken, error) {
+	if id == "" || secret == "" {
+		return nil, fmt.Errorf("token id and secret must not be empty")
+	}
+	if err := validateScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	return s.tokenRepo.UpsertToken(ctx, &model.APIToken{
+		ID:     id,
+		Name:   name,
+		Scopes: scopes,
+	}, HashToken(secret))
+}
+
+// RevokeToken is reserved for callers not bound to a user, like CreateToken.
+func (s *AuthService) RevokeToken(ctx context.Context, tokenID string) (*model.APIToken, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.RevokeToken(ctx, tokenID)
+}
+
+// Authenticate resolves a bearer secret to its token. Unknown and revoked
+// tokens are both reported as ErrUnauthorized.
+func (s *AuthService) Authenticate(ctx context.Context, secret string) (*model.APIToken, error) {
+	token, err := s.tokenRepo.GetTokenByHash(ctx, HashToken(secret))
+	if err != nil {
+		if err == int_errors.ErrTokenNotFound {
+			return nil, int_errors.ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	if token.RevokedAt != nil {
+		return nil, int_errors.ErrUnauthorized
+	}
+
+	return token, nil
+}
+
+// HashToken returns the hex SHA-256 of a token secret. Secrets are long random
+// strings, so a fast unsalted hash is sufficient.
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !model.IsValidScope(scope) {
+			return int_errors.ErrInvalidScope
+		}
+	}
+	return nil
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
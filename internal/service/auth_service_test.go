@@ -0,0 +1,92 @@
+package service
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/repository"
+	"context"
+	"testing"
+)
+
+type fakeTokenRepo struct {
+	repository.TokenRepository
+
+	created []*model.APIToken
+	revoked []string
+}
+
+func (f *fakeTokenRepo) CreateToken(_ context.Context, token *model.APIToken, _ string) (*model.APIToken, error) {
+	f.created = append(f.created, token)
+	return token, nil
+}
+
+func (f *fakeTokenRepo) RevokeToken(_ context.Context, tokenID string) (*model.APIToken, error) {
+	f.revoked = append(f.revoked, tokenID)
+	return &model.APIToken{ID: tokenID}, nil
+}
+
+func TestAuthServiceCreateToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		scopes []string
+		want   error
+	}{
+		{
+			name:   "global token with the scopes it grants",
+			ctx:    asGlobal(model.ScopeTeamAdmin, model.ScopePRWrite),
+			scopes: []string{model.ScopePRWrite},
+		},
+		{name: "auth disabled", ctx: context.Background(), scopes: []string{model.ScopeStatsRead}},
+		{
+			name:   "global token escalating its scopes",
+			ctx:    asGlobal(model.ScopeTeamAdmin),
+			scopes: []string{model.ScopeTeamAdmin, model.ScopePRWrite},
+			want:   int_errors.ErrForbidden,
+		},
+		{name: "bound lead", ctx: asUser("lead-a"), scopes: []string{model.ScopeTeamAdmin}, want: int_errors.ErrForbidden},
+		{name: "unknown scope", ctx: asGlobal(model.ScopeTeamAdmin), scopes: []string{"root"}, want: int_errors.ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeTokenRepo{}
+			s := NewAuthService(tokens)
+
+			_, secret, err := s.CreateToken(tt.ctx, "ci", nil, tt.scopes)
+			wantErr(t, err, tt.want)
+
+			if created := len(tokens.created) > 0; created != (tt.want == nil) {
+				t.Errorf("CreateToken stored = %v, want %v", created, tt.want == nil)
+			}
+			if tt.want == nil && secret == "" {
+				t.Error("secret is empty")
+			}
+		})
+	}
+}
+
+func TestAuthServiceRevokeToken(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{name: "global token", ctx: asGlobal(model.ScopeTeamAdmin)},
+		{name: "bound admin", ctx: asUser("admin-a"), want: int_errors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeTokenRepo{}
+			s := NewAuthService(tokens)
+
+			_, err := s.RevokeToken(tt.ctx, "tok_1")
+			wantErr(t, err, tt.want)
+
+			if revoked := len(tokens.revoked) > 0; revoked != (tt.want == nil) {
+				t.Errorf("RevokeToken called = %v, want %v", revoked, tt.want == nil)
+			}
+		})
+	}
+}
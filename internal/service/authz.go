@@ -0,0 +1,51 @@
+package service
+
+import (
+	"avito-pr-service/internal/auth"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/repository"
+	"context"
+)
+
+// callerRoles returns the team roles of the user the request acts as. bound is
+// false for callers that are not tied to a user (global tokens, or requests
+// with authentication disabled); those are limited by token scopes alone and
+// skip per-team checks.
+func callerRoles(ctx context.Context, teamRepo repository.TeamRepository) (userID string, roles map[string]string, bound bool, err error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.UserID == "" {
+		return "", nil, false, nil
+	}
+
+	roles, err = teamRepo.GetMemberRoles(ctx, p.UserID)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	return p.UserID, roles, true, nil
+}
+
+// managesUser reports whether roles allow managing every team the user is in,
+// primary team included. Changes that reach beyond a single team, such as
+// renaming or deleting the user, need that.
+func managesUser(roles map[string]string, user *model.User) bool {
+	if !model.CanManageTeam(roles[user.TeamName]) {
+		return false
+	}
+	for _, m := range user.Memberships {
+		if !model.CanManageTeam(roles[m.TeamName]) {
+			return false
+		}
+	}
+	return true
+}
+
+// requireGlobalCaller rejects callers bound to a user. Operations spanning the
+// whole organisation cannot be checked against per-team roles.
+func requireGlobalCaller(ctx context.Context) error {
+	if p, ok := auth.FromContext(ctx); ok && p.UserID != "" {
+		return int_errors.ErrForbidden
+	}
+	return nil
+}
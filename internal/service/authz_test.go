@@ -0,0 +1,183 @@
+package service
+
+import (
+	"avito-pr-service/internal/auth"
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeTeamRepo serves member roles from a map and records the writes the
+// services make. Methods the tests do not need panic through the embedded
+// nil interface.
+type fakeTeamRepo struct {
+	repository.TeamRepository
+
+	roles   map[string]map[string]string
+	added   []*model.Team
+	parents map[string]*string
+}
+
+func (f *fakeTeamRepo) GetMemberRoles(_ context.Context, userID string) (map[string]string, error) {
+	return f.roles[userID], nil
+}
+
+func (f *fakeTeamRepo) AddTeam(_ context.Context, team *model.Team) (*model.Team, error) {
+	f.added = append(f.added, team)
+	return team, nil
+}
+
+func (f *fakeTeamRepo) SetParent(_ context.Context, teamName string, parentTeam *string) (*model.TeamNode, error) {
+	if f.parents == nil {
+		f.parents = map[string]*string{}
+	}
+	f.parents[teamName] = parentTeam
+	return &model.TeamNode{TeamName: teamName, ParentTeam: parentTeam}, nil
+}
+
+// fakeUserRepo keeps users in a map. SetIsActive and SetMembershipsActive
+// change the stored user, so tests can tell which flag was touched.
+type fakeUserRepo struct {
+	repository.UserRepository
+
+	users   map[string]*model.User
+	deleted []string
+}
+
+func (f *fakeUserRepo) GetUser(_ context.Context, userID string) (*model.User, error) {
+	u, ok := f.users[userID]
+	if !ok {
+		return nil, int_errors.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (f *fakeUserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (*model.User, error) {
+	u, err := f.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	u.IsActive = isActive
+	return u, nil
+}
+
+func (f *fakeUserRepo) SetMembershipsActive(ctx context.Context, userID string, teamNames []string, isActive bool) (*model.User, error) {
+	u, err := f.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range u.Memberships {
+		for _, name := range teamNames {
+			if m.TeamName == name {
+				u.Memberships[i].IsActive = isActive
+			}
+		}
+	}
+	return u, nil
+}
+
+func (f *fakeUserRepo) CreateUser(_ context.Context, user *model.User) (*model.User, error) {
+	f.users[user.ID] = user
+	return user, nil
+}
+
+func (f *fakeUserRepo) UpdateUser(ctx context.Context, req model.UpdateUserRequest) (*model.User, error) {
+	return f.GetUser(ctx, req.ID)
+}
+
+func (f *fakeUserRepo) DeleteUser(_ context.Context, userID string) ([]model.ReviewReassignment, error) {
+	f.deleted = append(f.deleted, userID)
+	return nil, nil
+}
+
+// fixture is an organisation of two teams: lead-a leads backend, admin-a
+// administers it, member-a is a plain member, and lead-b leads payments.
+// u-a belongs to backend only, u-b to payments only, and u-ab has backend as
+// primary team and is also a member of payments.
+func fixture() (*fakeTeamRepo, *fakeUserRepo) {
+	teams := &fakeTeamRepo{roles: map[string]map[string]string{
+		"lead-a":   {"backend": model.RoleLead},
+		"admin-a":  {"backend": model.RoleAdmin},
+		"member-a": {"backend": model.RoleMember},
+		"lead-b":   {"payments": model.RoleLead},
+	}}
+
+	user := func(id, primary string, teams ...string) *model.User {
+		u := &model.User{ID: id, Username: id, TeamName: primary, IsActive: true}
+		for _, t := range teams {
+			u.Memberships = append(u.Memberships, model.TeamMembership{TeamName: t, Role: model.RoleMember, IsActive: true})
+		}
+		return u
+	}
+	users := &fakeUserRepo{users: map[string]*model.User{
+		"u-a":  user("u-a", "backend", "backend"),
+		"u-b":  user("u-b", "payments", "payments"),
+		"u-ab": user("u-ab", "backend", "backend", "payments"),
+	}}
+
+	return teams, users
+}
+
+// asUser is the context of a request made with a token bound to userID.
+func asUser(userID string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		TokenID: "tok_" + userID,
+		Scopes:  []string{model.ScopeTeamAdmin},
+		UserID:  userID,
+	})
+}
+
+// asGlobal is the context of a request made with a token not bound to a user.
+func asGlobal(scopes ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		TokenID: "tok_global",
+		Scopes:  scopes,
+	})
+}
+
+func wantErr(t *testing.T, err, want error) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
+
+func TestManagesUser(t *testing.T) {
+	_, users := fixture()
+
+	tests := []struct {
+		name   string
+		roles  map[string]string
+		userID string
+		want   bool
+	}{
+		{name: "lead of the only team", roles: map[string]string{"backend": model.RoleLead}, userID: "u-a", want: true},
+		{name: "admin of the only team", roles: map[string]string{"backend": model.RoleAdmin}, userID: "u-a", want: true},
+		{name: "member of the only team", roles: map[string]string{"backend": model.RoleMember}, userID: "u-a"},
+		{name: "lead of another team", roles: map[string]string{"payments": model.RoleLead}, userID: "u-a"},
+		{name: "lead of one of two teams", roles: map[string]string{"backend": model.RoleLead}, userID: "u-ab"},
+		{
+			name:   "lead of both teams",
+			roles:  map[string]string{"backend": model.RoleLead, "payments": model.RoleAdmin},
+			userID: "u-ab",
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := managesUser(tt.roles, users.users[tt.userID]); got != tt.want {
+				t.Errorf("managesUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var errDraining = errors.New("service is shutting down")
+
+type HealthConfig struct {
+	// MaxPoolSaturation is the share of acquired connections above which the
+	// instance reports itself not ready, e.g. 0.9.
+	MaxPoolSaturation float64
+	// SchemaVersion is the lowest migration version the code works with.
+	SchemaVersion int64
+	CheckTimeout  time.Duration
+}
+
+type HealthService struct {
+	repo     repository.HealthRepository
+	cfg      HealthConfig
+	draining atomic.Bool
+}
+
+func NewHealthService(repo repository.HealthRepository, cfg HealthConfig) *HealthService {
+	return &HealthService{repo: repo, cfg: cfg}
+}
+
+// StartDraining makes every following readiness check fail, so that load
+// balancers stop routing here before the server stops accepting requests.
+func (s *HealthService) StartDraining() {
+	s.draining.Store(true)
+}
+
+// Live only reports that the process can serve HTTP; dependencies are left
+// to Ready, otherwise a database outage would get every replica restarted.
+func (s *HealthService) Live(_ context.Context) *model.HealthReport {
+	return &model.HealthReport{Status: model.HealthOK, Checks: []model.HealthCheck{}}
+}
+
+func (s *HealthService) Ready(ctx context.Context) *model.HealthReport {
+	checks := []struct {
+		name string
+		fn   func(context.Context) (string, error)
+	}{
+		{"shutdown", s.checkDraining},
+		{"postgres", s.checkPing},
+		{"pool", s.checkPool},
+		{"migrations", s.checkMigrations},
+	}
+
+	report := &model.HealthReport{Status: model.HealthOK}
+	for _, c := range checks {
+		report.Checks = append(report.Checks, s.run(ctx, c.name, c.fn))
+	}
+	for _, c := range report.Checks {
+		if c.Status != model.HealthOK {
+			report.Status = model.HealthFail
+			break
+		}
+	}
+
+	return report
+}
+
+func (s *HealthService) run(ctx context.Context, name string, fn func(context.Context) (string, error)) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := fn(ctx)
+	check := model.HealthCheck{Name: name, Status: model.HealthOK, Latency: time.Since(start), Detail: detail}
+	if err != nil {
+		check.Status = model.HealthFail
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func (s *HealthService) checkDraining(_ context.Context) (string, error) {
+	if s.draining.Load() {
+		return "", errDraining
+	}
+	return "", nil
+}
+
+func (s *HealthService) checkPing(ctx context.Context) (string, error) {
+	return "", s.repo.Ping(ctx)
+}
+
+func (s *HealthService) checkPool(_ context.Context) (string, error) {
+	acquired, max := s.repo.PoolUsage()
+	detail := fmt.Sprintf("%d of %d connections in use", acquired, max)
+	if max > 0 && float64(acquired)/float64(max) > s.cfg.MaxPoolSaturation {
+		return detail, errors.New("pool saturated")
+	}
+	return detail, nil
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) (string, error) {
+	version, dirty, err := s.repo.MigrationVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	detail := fmt.Sprintf("version %d, expected %d", version, s.cfg.SchemaVersion)
+	if dirty {
+		return detail, fmt.Errorf("migration %d is dirty", version)
+	}
+	if version < s.cfg.SchemaVersion {
+		return detail, errors.New("schema is behind")
+	}
+	return detail, nil
+}
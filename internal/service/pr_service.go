@@ -1,27 +1,127 @@
 package service
 
 import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/metrics"
 	"avito-pr-service/internal/model"
 	"avito-pr-service/internal/repository"
 	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("avito-pr-service/internal/service")
+
 type PRService struct {
-	prRepo repository.PRRepository
+	prRepo   repository.PRRepository
+	teamRepo repository.TeamRepository
+}
+
+func NewPRService(prRepo repository.PRRepository, teamRepo repository.TeamRepository) *PRService {
+	return &PRService{prRepo: prRepo, teamRepo: teamRepo}
+}
+
+func (s *PRService) CreatePR(ctx context.Context, req model.CreatePRRequest) (_ *model.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "PRService.CreatePR", trace.WithAttributes(
+		attribute.String("pr.id", req.PRID),
+		attribute.String("pr.author_id", req.AuthorID),
+	))
+	defer func() { endSpan(span, err) }()
+
+	pr, err := s.prRepo.CreatePR(ctx, req.PRID, req.PRName, req.AuthorID, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("pr.reviewers", len(pr.AssignedReviewers)))
+	metrics.PRsCreated.Inc()
+	metrics.AssignmentSize.Observe(float64(len(pr.AssignedReviewers)))
+	return pr, nil
+}
+
+func (s *PRService) GetPR(ctx context.Context, prID string) (*model.PullRequest, error) {
+	return s.prRepo.GetPR(ctx, prID)
 }
 
-func NewPRService(prRepo repository.PRRepository) *PRService {
-	return &PRService{prRepo: prRepo}
+func (s *PRService) MergePR(ctx context.Context, prID string) (_ *model.PullRequest, err error) {
+	ctx, span := tracer.Start(ctx, "PRService.MergePR", trace.WithAttributes(attribute.String("pr.id", prID)))
+	defer func() { endSpan(span, err) }()
+
+	pr, merged, err := s.prRepo.MergePR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if merged {
+		metrics.PRsMerged.Inc()
+	}
+	return pr, nil
 }
 
-func (s *PRService) CreatePR(ctx context.Context, req model.CreatePRRequest) (*model.PullRequest, error) {
-	return s.prRepo.CreatePR(ctx, req.PRID, req.PRName, req.AuthorID)
+// ReassignReviewer is open to leads and admins of the PR's team for any
+// reviewer; a plain member may only take themselves off a PR.
+func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string, escalate bool) (*model.PullRequest, string, error) {
+	ctx, span := tracer.Start(ctx, "PRService.ReassignReviewer", trace.WithAttributes(
+		attribute.String("pr.id", prID),
+		attribute.String("pr.old_reviewer_id", oldUserID),
+		attribute.Bool("escalate", escalate),
+	))
+
+	pr, replacedBy, err := s.reassignReviewer(ctx, prID, oldUserID, escalate)
+	outcome := reassignOutcome(err)
+	span.SetAttributes(attribute.String("outcome", outcome))
+	endSpan(span, err)
+
+	metrics.Reassignments.WithLabelValues(outcome).Inc()
+	return pr, replacedBy, err
+}
+
+func (s *PRService) reassignReviewer(ctx context.Context, prID, oldUserID string, escalate bool) (*model.PullRequest, string, error) {
+	callerID, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if bound && callerID != oldUserID {
+		pr, err := s.prRepo.GetPR(ctx, prID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !model.CanManageTeam(roles[pr.TeamName]) {
+			return nil, "", int_errors.ErrForbidden
+		}
+	}
+
+	return s.prRepo.ReassignReviewer(ctx, prID, oldUserID, escalate)
 }
 
-func (s *PRService) MergePR(ctx context.Context, prID string) (*model.PullRequest, error) {
-	return s.prRepo.MergePR(ctx, prID)
+func reassignOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.OutcomeReassigned
+	case errors.Is(err, int_errors.ErrNoReplacementCandidate):
+		return metrics.OutcomeNoCandidate
+	case errors.Is(err, int_errors.ErrReviewerNotAssigned):
+		return metrics.OutcomeNotAssigned
+	case errors.Is(err, int_errors.ErrPRMerged):
+		return metrics.OutcomeMerged
+	case errors.Is(err, int_errors.ErrPRNotFound), errors.Is(err, int_errors.ErrUserNotFound):
+		return metrics.OutcomeNotFound
+	case errors.Is(err, int_errors.ErrForbidden):
+		return metrics.OutcomeForbidden
+	default:
+		return metrics.OutcomeError
+	}
 }
 
-func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*model.PullRequest, string, error) {
-	return s.prRepo.ReassignReviewer(ctx, prID, oldUserID)
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"avito-pr-service/internal/repository"
+	"context"
+)
+
+// SCIMService backs the SCIM provisioning endpoints. An identity provider
+// manages the whole organisation through them, so every method is reserved
+// for callers not bound to a user.
+type SCIMService struct {
+	userRepo    repository.UserRepository
+	teamRepo    repository.TeamRepository
+	defaultTeam string
+}
+
+func NewSCIMService(userRepo repository.UserRepository, teamRepo repository.TeamRepository, defaultTeam string) *SCIMService {
+	return &SCIMService{
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		defaultTeam: defaultTeam,
+	}
+}
+
+func (s *SCIMService) ListUsers(ctx context.Context, filter model.UserFilter, offset, limit int) ([]*model.User, int, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, 0, err
+	}
+	return s.userRepo.ListUsers(ctx, filter, offset, limit)
+}
+
+func (s *SCIMService) GetUser(ctx context.Context, userID string) (*model.User, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetUser(ctx, userID)
+}
+
+// CreateUser places the user in their primary team, or in the default team
+// when none is given, creating the team first if needed.
+func (s *SCIMService) CreateUser(ctx context.Context, user *model.User) (*model.User, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+
+	if user.TeamName == "" {
+		user.TeamName = s.defaultTeam
+	}
+	if err := s.ensureTeam(ctx, user.TeamName); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.CreateUser(ctx, user)
+}
+
+// UpdateUser applies a profile change and, with active set, changes the
+// user's global flag the same way /users/setIsActive does.
+func (s *SCIMService) UpdateUser(ctx context.Context, req model.UpdateUserRequest, active *bool) (*model.User, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.TeamName != nil {
+		if err := s.ensureTeam(ctx, *req.TeamName); err != nil {
+			return nil, err
+		}
+	}
+	if req.Username != nil || req.TeamName != nil {
+		if _, err := s.userRepo.UpdateUser(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	if active != nil {
+		if _, err := s.userRepo.SetIsActive(ctx, req.ID, *active); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.userRepo.GetUser(ctx, req.ID)
+}
+
+// DeleteUser deactivates the user rather than deleting them. Identity
+// providers delete users as routine deprovisioning, and deleting would take
+// the user's review history along or fail on it.
+func (s *SCIMService) DeleteUser(ctx context.Context, userID string) error {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return err
+	}
+	_, err := s.userRepo.SetIsActive(ctx, userID, false)
+	return err
+}
+
+// ListGroups returns all active teams, or only the named ones.
+func (s *SCIMService) ListGroups(ctx context.Context, teamNames ...string) ([]*model.Team, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.ListTeams(ctx, teamNames...)
+}
+
+// GetGroup, unlike TeamService.GetTeam, also finds teams without members.
+func (s *SCIMService) GetGroup(ctx context.Context, teamName string) (*model.Team, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+
+	teams, err := s.teamRepo.ListTeams(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, int_errors.ErrTeamNotFound
+	}
+	return teams[0], nil
+}
+
+func (s *SCIMService) CreateGroup(ctx context.Context, teamName string, memberIDs []string) (*model.Team, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.CreateTeam(ctx, teamName, memberIDs)
+}
+
+func (s *SCIMService) UpdateGroupMembers(ctx context.Context, teamName string, patch model.MemberPatch) (*model.Team, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.UpdateMembers(ctx, teamName, patch)
+}
+
+// DeleteGroup archives the team after removing its members.
+func (s *SCIMService) DeleteGroup(ctx context.Context, teamName string) error {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return err
+	}
+	return s.teamRepo.ArchiveTeam(ctx, teamName)
+}
+
+// ensureTeam creates the team, or restores it if it was archived.
+func (s *SCIMService) ensureTeam(ctx context.Context, teamName string) error {
+	_, err := s.teamRepo.AddTeam(ctx, &model.Team{TeamName: teamName})
+	return err
+}
@@ -15,6 +15,61 @@ func NewStatisticsService(repo repository.StatisticsRepository) *StatisticsServi
 	return &StatisticsService{repo: repo}
 }
 
-func (s *StatisticsService) GetStatistics(ctx context.Context) (*model.Statistics, error) {
-	return s.repo.GetStatistics(ctx)
+func (s *StatisticsService) GetStatistics(ctx context.Context, filter model.StatisticsFilter, prPage, reviewerPage model.Page) (*model.Statistics, error) {
+	return s.repo.GetStatistics(ctx, filter, prPage, reviewerPage)
+}
+
+func (s *StatisticsService) GetSubtreeStatistics(ctx context.Context, rootTeam string) (*model.SubtreeStatistics, error) {
+	return s.repo.GetSubtreeStatistics(ctx, rootTeam)
+}
+
+func (s *StatisticsService) GetTimingStatistics(ctx context.Context, rng model.TimeRange) (*model.TimingStatistics, error) {
+	return s.repo.GetTimingStatistics(ctx, rng)
+}
+
+func (s *StatisticsService) GetStatisticsHistory(ctx context.Context, q model.HistoryQuery) (*model.StatisticsHistory, error) {
+	return s.repo.GetStatisticsHistory(ctx, q)
+}
+
+// GetFairness measures how evenly reviews were assigned within each team.
+func (s *StatisticsService) GetFairness(ctx context.Context, teamName *string, rng model.TimeRange, tolerance float64) (*model.FairnessReport, error) {
+	loads, err := s.repo.GetReviewLoads(ctx, teamName, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.FairnessReport{
+		From:      rng.From,
+		To:        rng.To,
+		Tolerance: tolerance,
+		Teams:     make([]model.TeamFairness, 0, len(loads)),
+	}
+	for _, team := range loads {
+		report.Teams = append(report.Teams, model.MeasureFairness(team, tolerance))
+	}
+	return report, nil
+}
+
+// GetPairs builds a team's author x reviewer matrix with its top pairs.
+func (s *StatisticsService) GetPairs(ctx context.Context, teamName string, rng model.TimeRange, top int) (*model.PairMatrix, error) {
+	members, pairs, err := s.repo.GetReviewPairs(ctx, teamName, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := model.BuildPairMatrix(members, pairs, top)
+	matrix.TeamName = teamName
+	matrix.From = rng.From
+	matrix.To = rng.To
+	return &matrix, nil
+}
+
+// RebuildCounters checks the statistics counters against the reviews and
+// rewrites them if they drifted. It is reserved for callers not bound to a
+// user.
+func (s *StatisticsService) RebuildCounters(ctx context.Context) (*model.CounterCheck, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.RebuildCounters(ctx)
 }
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"avito-pr-service/internal/int_errors"
 	"avito-pr-service/internal/model"
 	"avito-pr-service/internal/repository"
 	"context"
+	"errors"
 )
 
 type TeamService struct {
@@ -24,6 +26,135 @@ func (s *TeamService) GetTeam(ctx context.Context, name string) (*model.Team, er
 	return s.teamRepo.GetTeam(ctx, name)
 }
 
+// CreateTeam upserts the team and its members. A caller bound to a user must
+// be a lead or admin of that team, and only admins may hand out the admin role.
+// New teams can therefore only be created by global tokens. Upserting a member
+// overwrites their username and adds a membership, so a bound caller may only
+// list new users and users whose every team they manage.
 func (s *TeamService) CreateTeam(ctx context.Context, team *model.Team) (*model.Team, error) {
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if bound {
+		role := roles[team.TeamName]
+		if !model.CanManageTeam(role) {
+			return nil, int_errors.ErrForbidden
+		}
+		for _, m := range team.Members {
+			if m.Role == model.RoleAdmin && role != model.RoleAdmin {
+				return nil, int_errors.ErrForbidden
+			}
+
+			existing, err := s.userRepo.GetUser(ctx, m.UserID)
+			if errors.Is(err, int_errors.ErrUserNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !managesUser(roles, existing) {
+				return nil, int_errors.ErrForbidden
+			}
+		}
+	}
+
 	return s.teamRepo.AddTeam(ctx, team)
 }
+
+// RenameTeam follows the same rule as CreateTeam: a caller bound to a user
+// must lead or administer the team.
+func (s *TeamService) RenameTeam(ctx context.Context, teamName, newTeamName string) (*model.Team, error) {
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+	if bound && !model.CanManageTeam(roles[teamName]) {
+		return nil, int_errors.ErrForbidden
+	}
+
+	return s.teamRepo.RenameTeam(ctx, teamName, newTeamName)
+}
+
+// ImportTeams applies a bulk roster. It may create any number of teams, so
+// unlike CreateTeam it is reserved for callers not bound to a user.
+func (s *TeamService) ImportTeams(ctx context.Context, teams []*model.Team, dryRun bool) (*model.ImportResult, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.ImportTeams(ctx, teams, dryRun)
+}
+
+// ExportTeams returns the whole roster; like ImportTeams it is reserved for
+// callers not bound to a user.
+func (s *TeamService) ExportTeams(ctx context.Context) ([]*model.Team, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.ListTeams(ctx)
+}
+
+// PlanSync compares the desired org state with the database without changing
+// anything.
+func (s *TeamService) PlanSync(ctx context.Context, desired []*model.Team, opts model.SyncOptions) (*model.SyncPlan, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+
+	state, err := s.teamRepo.OrgState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.PlanSync(state, desired, opts), nil
+}
+
+// ApplySync makes the database match the desired org state. Passing the ID of
+// a reviewed plan guarantees that exactly that plan is executed.
+func (s *TeamService) ApplySync(ctx context.Context, desired []*model.Team, opts model.SyncOptions, planID string) (*model.SyncResult, error) {
+	if err := requireGlobalCaller(ctx); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.ApplySync(ctx, desired, opts, planID)
+}
+
+// SetParent moves the team in the hierarchy. A caller bound to a user must
+// lead or administer the team and, when attaching it, the new parent as well.
+func (s *TeamService) SetParent(ctx context.Context, teamName string, parentTeam *string) (*model.TeamNode, error) {
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+	if bound {
+		if !model.CanManageTeam(roles[teamName]) {
+			return nil, int_errors.ErrForbidden
+		}
+		if parentTeam != nil && !model.CanManageTeam(roles[*parentTeam]) {
+			return nil, int_errors.ErrForbidden
+		}
+	}
+
+	return s.teamRepo.SetParent(ctx, teamName, parentTeam)
+}
+
+func (s *TeamService) GetSubtree(ctx context.Context, teamName string) (*model.TeamNode, error) {
+	return s.teamRepo.GetSubtree(ctx, teamName)
+}
+
+func (s *TeamService) GetReviewSettings(ctx context.Context, teamName string) (*model.ReviewSettings, error) {
+	return s.teamRepo.GetReviewSettings(ctx, teamName)
+}
+
+// SetReviewSettings follows the same rule as CreateTeam: a caller bound to a
+// user must lead or administer the team.
+func (s *TeamService) SetReviewSettings(ctx context.Context, settings *model.ReviewSettings) (*model.ReviewSettings, error) {
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+	if bound && !model.CanManageTeam(roles[settings.TeamName]) {
+		return nil, int_errors.ErrForbidden
+	}
+
+	return s.teamRepo.SetReviewSettings(ctx, settings)
+}
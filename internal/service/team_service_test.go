@@ -0,0 +1,162 @@
+package service
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"context"
+	"testing"
+)
+
+func TestTeamServiceCreateTeam(t *testing.T) {
+	member := func(id, role string) *model.TeamMember {
+		return &model.TeamMember{UserID: id, Username: id, Role: role, IsActive: true}
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		team    string
+		members []*model.TeamMember
+		want    error
+	}{
+		{
+			name:    "global token creates any team",
+			ctx:     asGlobal(model.ScopeTeamAdmin),
+			team:    "new-team",
+			members: []*model.TeamMember{member("u-b", model.RoleAdmin)},
+		},
+		{
+			name:    "auth disabled",
+			ctx:     context.Background(),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-b", model.RoleMember)},
+		},
+		{
+			name:    "lead adds a new user",
+			ctx:     asUser("lead-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-new", model.RoleMember)},
+		},
+		{
+			name:    "lead updates a user of their team",
+			ctx:     asUser("lead-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-a", model.RoleLead)},
+		},
+		{
+			name:    "lead pulls in a user of another team",
+			ctx:     asUser("lead-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-new", model.RoleMember), member("u-b", model.RoleMember)},
+			want:    int_errors.ErrForbidden,
+		},
+		{
+			name:    "lead updates a user who is also in another team",
+			ctx:     asUser("lead-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-ab", model.RoleMember)},
+			want:    int_errors.ErrForbidden,
+		},
+		{
+			name:    "lead hands out admin",
+			ctx:     asUser("lead-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-new", model.RoleAdmin)},
+			want:    int_errors.ErrForbidden,
+		},
+		{
+			name:    "admin hands out admin",
+			ctx:     asUser("admin-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-a", model.RoleAdmin)},
+		},
+		{
+			name:    "admin pulls in a user of another team",
+			ctx:     asUser("admin-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-b", model.RoleMember)},
+			want:    int_errors.ErrForbidden,
+		},
+		{
+			name:    "member changes their team",
+			ctx:     asUser("member-a"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-new", model.RoleMember)},
+			want:    int_errors.ErrForbidden,
+		},
+		{
+			name:    "lead changes another team",
+			ctx:     asUser("lead-b"),
+			team:    "backend",
+			members: []*model.TeamMember{member("u-new", model.RoleMember)},
+			want:    int_errors.ErrForbidden,
+		},
+		{
+			name:    "bound caller creates a new team",
+			ctx:     asUser("lead-a"),
+			team:    "new-team",
+			members: []*model.TeamMember{member("u-new", model.RoleMember)},
+			want:    int_errors.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, users := fixture()
+			s := NewTeamService(teams, users, nil)
+
+			_, err := s.CreateTeam(tt.ctx, &model.Team{TeamName: tt.team, Members: tt.members})
+			wantErr(t, err, tt.want)
+
+			wrote := len(teams.added) > 0
+			if wrote != (tt.want == nil) {
+				t.Errorf("AddTeam called = %v, want %v", wrote, tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestTeamServiceSetParent(t *testing.T) {
+	parent := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		team   string
+		parent *string
+		want   error
+	}{
+		{name: "global token", ctx: asGlobal(model.ScopeTeamAdmin), team: "backend", parent: parent("payments")},
+		{name: "lead detaches their team", ctx: asUser("lead-a"), team: "backend"},
+		{name: "admin detaches their team", ctx: asUser("admin-a"), team: "backend"},
+		{name: "member detaches their team", ctx: asUser("member-a"), team: "backend", want: int_errors.ErrForbidden},
+		{
+			name:   "lead attaches under a team they do not lead",
+			ctx:    asUser("lead-a"),
+			team:   "backend",
+			parent: parent("payments"),
+			want:   int_errors.ErrForbidden,
+		},
+		{
+			name:   "lead moves another team under theirs",
+			ctx:    asUser("lead-a"),
+			team:   "payments",
+			parent: parent("backend"),
+			want:   int_errors.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, users := fixture()
+			s := NewTeamService(teams, users, nil)
+
+			_, err := s.SetParent(tt.ctx, tt.team, tt.parent)
+			wantErr(t, err, tt.want)
+
+			if _, wrote := teams.parents[tt.team]; wrote != (tt.want == nil) {
+				t.Errorf("SetParent called = %v, want %v", wrote, tt.want == nil)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"avito-pr-service/internal/int_errors"
 	"avito-pr-service/internal/model"
 	"avito-pr-service/internal/repository"
 	"context"
@@ -8,16 +9,108 @@ import (
 
 type UserService struct {
 	userRepo repository.UserRepository
+	teamRepo repository.TeamRepository
 }
 
-func NewUserService(userRepo repository.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, teamRepo repository.TeamRepository) *UserService {
+	return &UserService{userRepo: userRepo, teamRepo: teamRepo}
 }
 
 func (s *UserService) GetByReviewer(ctx context.Context, id string) ([]model.PullRequest, error) {
 	return s.userRepo.GetUserReviews(ctx, id)
 }
 
+// SetIsActive changes the user's global activity flag when called by a token
+// not bound to a user. A bound caller must lead or administer at least one of
+// the user's teams, and only the memberships in those teams are changed: a
+// lead cannot take the user out of review in other teams.
 func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive bool) (*model.User, error) {
-	return s.userRepo.SetIsActive(ctx, userID, isActive)
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !bound {
+		return s.userRepo.SetIsActive(ctx, userID, isActive)
+	}
+
+	target, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var teams []string
+	for _, m := range target.Memberships {
+		if model.CanManageTeam(roles[m.TeamName]) {
+			teams = append(teams, m.TeamName)
+		}
+	}
+	if len(teams) == 0 {
+		return nil, int_errors.ErrForbidden
+	}
+
+	return s.userRepo.SetMembershipsActive(ctx, userID, teams, isActive)
+}
+
+func (s *UserService) GetUser(ctx context.Context, userID string) (*model.User, error) {
+	return s.userRepo.GetUser(ctx, userID)
+}
+
+// CreateUser follows the same rule as TeamService.CreateTeam: a caller bound
+// to a user must lead or administer the team the new user joins.
+func (s *UserService) CreateUser(ctx context.Context, user *model.User) (*model.User, error) {
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+	if bound && !model.CanManageTeam(roles[user.TeamName]) {
+		return nil, int_errors.ErrForbidden
+	}
+
+	return s.userRepo.CreateUser(ctx, user)
+}
+
+// UpdateUser requires a bound caller to lead or administer every team of the
+// user, since the username is shared by all of them, and, when the user is
+// moved, the team they move to.
+func (s *UserService) UpdateUser(ctx context.Context, req model.UpdateUserRequest) (*model.User, error) {
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if bound {
+		target, err := s.userRepo.GetUser(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !managesUser(roles, target) {
+			return nil, int_errors.ErrForbidden
+		}
+		if req.TeamName != nil && !model.CanManageTeam(roles[*req.TeamName]) {
+			return nil, int_errors.ErrForbidden
+		}
+	}
+
+	return s.userRepo.UpdateUser(ctx, req)
+}
+
+// DeleteUser removes the user from every team, so a bound caller must lead or
+// administer all of them.
+func (s *UserService) DeleteUser(ctx context.Context, userID string) ([]model.ReviewReassignment, error) {
+	_, roles, bound, err := callerRoles(ctx, s.teamRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if bound {
+		target, err := s.userRepo.GetUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !managesUser(roles, target) {
+			return nil, int_errors.ErrForbidden
+		}
+	}
+
+	return s.userRepo.DeleteUser(ctx, userID)
 }
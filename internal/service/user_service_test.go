@@ -0,0 +1,175 @@
+package service
+
+import (
+	"avito-pr-service/internal/int_errors"
+	"avito-pr-service/internal/model"
+	"context"
+	"testing"
+)
+
+func TestUserServiceSetIsActive(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		userID     string
+		want       error
+		wantGlobal bool
+		// wantTeams are the memberships expected to be deactivated.
+		wantTeams []string
+	}{
+		{
+			name:       "global token changes the global flag",
+			ctx:        asGlobal(model.ScopeTeamAdmin),
+			userID:     "u-ab",
+			wantGlobal: true,
+		},
+		{
+			name:      "lead changes their team's membership only",
+			ctx:       asUser("lead-a"),
+			userID:    "u-ab",
+			wantTeams: []string{"backend"},
+		},
+		{
+			name:      "admin changes their team's membership",
+			ctx:       asUser("admin-a"),
+			userID:    "u-a",
+			wantTeams: []string{"backend"},
+		},
+		{
+			name:      "lead of the other team",
+			ctx:       asUser("lead-b"),
+			userID:    "u-ab",
+			wantTeams: []string{"payments"},
+		},
+		{name: "member", ctx: asUser("member-a"), userID: "u-a", want: int_errors.ErrForbidden},
+		{name: "lead of an unrelated team", ctx: asUser("lead-b"), userID: "u-a", want: int_errors.ErrForbidden},
+		{name: "unknown user", ctx: asUser("lead-a"), userID: "u-404", want: int_errors.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, users := fixture()
+			s := NewUserService(users, teams)
+
+			_, err := s.SetIsActive(tt.ctx, tt.userID, false)
+			wantErr(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+
+			u := users.users[tt.userID]
+			if u.IsActive == tt.wantGlobal {
+				t.Errorf("global is_active = %v, want %v", u.IsActive, !tt.wantGlobal)
+			}
+			for _, m := range u.Memberships {
+				deactivated := false
+				for _, name := range tt.wantTeams {
+					deactivated = deactivated || m.TeamName == name
+				}
+				if m.IsActive == deactivated {
+					t.Errorf("membership %s is_active = %v, want %v", m.TeamName, m.IsActive, !deactivated)
+				}
+			}
+		})
+	}
+}
+
+func TestUserServiceCreateUser(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		team string
+		want error
+	}{
+		{name: "global token", ctx: asGlobal(model.ScopeTeamAdmin), team: "payments"},
+		{name: "lead into their team", ctx: asUser("lead-a"), team: "backend"},
+		{name: "admin into their team", ctx: asUser("admin-a"), team: "backend"},
+		{name: "lead into another team", ctx: asUser("lead-a"), team: "payments", want: int_errors.ErrForbidden},
+		{name: "member", ctx: asUser("member-a"), team: "backend", want: int_errors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, users := fixture()
+			s := NewUserService(users, teams)
+
+			_, err := s.CreateUser(tt.ctx, model.NewUser("u-new", "New", tt.team, true))
+			wantErr(t, err, tt.want)
+
+			if _, created := users.users["u-new"]; created != (tt.want == nil) {
+				t.Errorf("user created = %v, want %v", created, tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestUserServiceUpdateUser(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  model.UpdateUserRequest
+		want error
+	}{
+		{name: "global token", ctx: asGlobal(model.ScopeTeamAdmin), req: model.UpdateUserRequest{ID: "u-b", TeamName: str("backend")}},
+		{name: "lead renames a user of their team", ctx: asUser("lead-a"), req: model.UpdateUserRequest{ID: "u-a", Username: str("Alice")}},
+		{
+			name: "lead moves a user out to another team",
+			ctx:  asUser("lead-a"),
+			req:  model.UpdateUserRequest{ID: "u-a", TeamName: str("payments")},
+			want: int_errors.ErrForbidden,
+		},
+		{
+			name: "lead renames a user who is also in another team",
+			ctx:  asUser("lead-a"),
+			req:  model.UpdateUserRequest{ID: "u-ab", Username: str("Ann")},
+			want: int_errors.ErrForbidden,
+		},
+		{
+			name: "lead renames a user of another team",
+			ctx:  asUser("lead-a"),
+			req:  model.UpdateUserRequest{ID: "u-b", Username: str("Bob")},
+			want: int_errors.ErrForbidden,
+		},
+		{name: "member", ctx: asUser("member-a"), req: model.UpdateUserRequest{ID: "u-a"}, want: int_errors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, users := fixture()
+			s := NewUserService(users, teams)
+
+			_, err := s.UpdateUser(tt.ctx, tt.req)
+			wantErr(t, err, tt.want)
+		})
+	}
+}
+
+func TestUserServiceDeleteUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		userID string
+		want   error
+	}{
+		{name: "global token", ctx: asGlobal(model.ScopeTeamAdmin), userID: "u-ab"},
+		{name: "lead of the user's only team", ctx: asUser("lead-a"), userID: "u-a"},
+		{name: "lead of one of the user's teams", ctx: asUser("lead-a"), userID: "u-ab", want: int_errors.ErrForbidden},
+		{name: "member", ctx: asUser("member-a"), userID: "u-a", want: int_errors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, users := fixture()
+			s := NewUserService(users, teams)
+
+			_, err := s.DeleteUser(tt.ctx, tt.userID)
+			wantErr(t, err, tt.want)
+
+			if deleted := len(users.deleted) > 0; deleted != (tt.want == nil) {
+				t.Errorf("DeleteUser called = %v, want %v", deleted, tt.want == nil)
+			}
+		})
+	}
+}
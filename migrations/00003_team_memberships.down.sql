@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_team_memberships_team_active;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS team_name;
+DROP TABLE IF EXISTS team_memberships;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE team_memberships (
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'lead')),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, team_name)
+);
+
+INSERT INTO team_memberships (user_id, team_name, is_active)
+SELECT user_id, team_name, TRUE FROM users;
+
+ALTER TABLE pull_requests
+    ADD COLUMN team_name TEXT REFERENCES teams(team_name) ON DELETE SET NULL;
+
+UPDATE pull_requests pr
+SET team_name = u.team_name
+FROM users u
+WHERE u.user_id = pr.author_id;
+
+CREATE INDEX IF NOT EXISTS idx_team_memberships_team_active
+    ON team_memberships(team_name, is_active) WHERE is_active = TRUE;
\ No newline at end of file
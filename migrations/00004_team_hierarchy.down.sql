@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_teams_parent;
+ALTER TABLE teams DROP COLUMN IF EXISTS parent_team;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    ADD COLUMN parent_team TEXT REFERENCES teams(team_name) ON DELETE SET NULL,
+    ADD CONSTRAINT teams_parent_not_self CHECK (parent_team <> team_name);
+
+CREATE INDEX IF NOT EXISTS idx_teams_parent ON teams(parent_team);
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE api_tokens DROP COLUMN IF EXISTS user_id;
+
+UPDATE team_memberships SET role = 'lead' WHERE role = 'admin';
+ALTER TABLE team_memberships DROP CONSTRAINT IF EXISTS team_memberships_role_check;
+ALTER TABLE team_memberships
+    ADD CONSTRAINT team_memberships_role_check CHECK (role IN ('member', 'lead'));
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE team_memberships DROP CONSTRAINT IF EXISTS team_memberships_role_check;
+ALTER TABLE team_memberships
+    ADD CONSTRAINT team_memberships_role_check CHECK (role IN ('member', 'lead', 'admin'));
+
+ALTER TABLE api_tokens
+    ADD COLUMN user_id TEXT REFERENCES users(user_id) ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1,19 @@
+ALTER TABLE users
+    DROP CONSTRAINT users_team_name_fkey,
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name)
+        REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE team_memberships
+    DROP CONSTRAINT team_memberships_team_name_fkey,
+    ADD CONSTRAINT team_memberships_team_name_fkey FOREIGN KEY (team_name)
+        REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_team_name_fkey,
+    ADD CONSTRAINT pull_requests_team_name_fkey FOREIGN KEY (team_name)
+        REFERENCES teams(team_name) ON DELETE SET NULL;
+
+ALTER TABLE teams
+    DROP CONSTRAINT teams_parent_team_fkey,
+    ADD CONSTRAINT teams_parent_team_fkey FOREIGN KEY (parent_team)
+        REFERENCES teams(team_name) ON DELETE SET NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pr_reassignments;
+
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS assigned_at;
\ No newline at end of file
@@ -0,0 +1,20 @@
+ALTER TABLE pr_reviewers ADD COLUMN assigned_at TIMESTAMPTZ;
+
+UPDATE pr_reviewers prr
+SET assigned_at = COALESCE(pr.created_at, NOW())
+FROM pull_requests pr
+WHERE pr.pull_request_id = prr.pull_request_id;
+
+ALTER TABLE pr_reviewers
+    ALTER COLUMN assigned_at SET DEFAULT NOW(),
+    ALTER COLUMN assigned_at SET NOT NULL;
+
+CREATE TABLE pr_reassignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    old_reviewer_id TEXT NOT NULL,
+    new_reviewer_id TEXT,
+    reassigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_reassignments_pr ON pr_reassignments(pull_request_id);
\ No newline at end of file
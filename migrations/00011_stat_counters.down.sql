@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reviewer_counters;
+DROP TABLE IF EXISTS team_counters;
+
+DROP INDEX IF EXISTS idx_pr_created;
\ No newline at end of file
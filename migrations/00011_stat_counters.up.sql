@@ -0,0 +1,33 @@
+CREATE TABLE team_counters (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    open_prs INTEGER NOT NULL DEFAULT 0,
+    merged_prs INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE reviewer_counters (
+    reviewer_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    open_reviews INTEGER NOT NULL DEFAULT 0,
+    merged_reviews INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (reviewer_id, team_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_counters_team ON reviewer_counters(team_name);
+CREATE INDEX IF NOT EXISTS idx_pr_created ON pull_requests(created_at DESC, pull_request_id);
+
+INSERT INTO team_counters (team_name, open_prs, merged_prs)
+SELECT COALESCE(pr.team_name, a.team_name),
+       COUNT(*) FILTER (WHERE pr.status = 'OPEN'),
+       COUNT(*) FILTER (WHERE pr.status = 'MERGED')
+FROM pull_requests pr
+JOIN users a ON a.user_id = pr.author_id
+GROUP BY 1;
+
+INSERT INTO reviewer_counters (reviewer_id, team_name, open_reviews, merged_reviews)
+SELECT prr.reviewer_id, COALESCE(pr.team_name, a.team_name),
+       COUNT(*) FILTER (WHERE pr.status = 'OPEN'),
+       COUNT(*) FILTER (WHERE pr.status = 'MERGED')
+FROM pr_reviewers prr
+JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+JOIN users a ON a.user_id = pr.author_id
+GROUP BY 1, 2;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS stats_snapshot_runs;
+DROP TABLE IF EXISTS stats_snapshots;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE stats_snapshots (
+    snapshot_date DATE NOT NULL,
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    reviewer_id TEXT NOT NULL DEFAULT '',
+    open_count INTEGER NOT NULL,
+    added_count INTEGER NOT NULL,
+    merged_count INTEGER NOT NULL,
+    PRIMARY KEY (snapshot_date, team_name, reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stats_snapshots_team ON stats_snapshots(team_name, snapshot_date);
+
+CREATE TABLE stats_snapshot_runs (
+    snapshot_date DATE PRIMARY KEY,
+    written_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pr_author_created;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS diversity_lookback_prs,
+    DROP COLUMN IF EXISTS diversity_lookback_days,
+    DROP COLUMN IF EXISTS pairing_diversity;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS pairing_diversity BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS diversity_lookback_days INTEGER CHECK (diversity_lookback_days > 0),
+    ADD COLUMN IF NOT EXISTS diversity_lookback_prs INTEGER CHECK (diversity_lookback_prs > 0);
+
+-- Recent PRs of an author, looked up for every assignment in teams with
+-- pairing diversity enabled.
+CREATE INDEX IF NOT EXISTS idx_pr_author_created ON pull_requests(author_id, created_at DESC);
\ No newline at end of file
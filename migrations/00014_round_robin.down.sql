@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS review_cursors;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS review_strategy;
\ No newline at end of file
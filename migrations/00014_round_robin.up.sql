@@ -0,0 +1,12 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS review_strategy TEXT NOT NULL DEFAULT 'random'
+        CHECK (review_strategy IN ('random', 'round_robin'));
+
+-- The member of a round-robin team picked last; the rotation continues after
+-- them in user_id order. The row is locked for the rest of any transaction
+-- that picks reviewers from the team.
+CREATE TABLE IF NOT EXISTS review_cursors (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    last_user_id TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
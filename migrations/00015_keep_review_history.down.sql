@@ -0,0 +1,9 @@
+ALTER TABLE pr_reviewers
+    DROP CONSTRAINT pr_reviewers_reviewer_id_fkey,
+    ADD CONSTRAINT pr_reviewers_reviewer_id_fkey FOREIGN KEY (reviewer_id)
+        REFERENCES users(user_id) ON DELETE CASCADE;
+
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_author_id_fkey,
+    ADD CONSTRAINT pull_requests_author_id_fkey FOREIGN KEY (author_id)
+        REFERENCES users(user_id) ON DELETE CASCADE;
\ No newline at end of file
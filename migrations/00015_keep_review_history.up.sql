@@ -0,0 +1,12 @@
+-- Users with PRs or reviews must not take them with them when deleted:
+-- the history feeds /statistics and its timing, history, fairness and pairs
+-- views. Such users are deactivated instead.
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_author_id_fkey,
+    ADD CONSTRAINT pull_requests_author_id_fkey FOREIGN KEY (author_id)
+        REFERENCES users(user_id) ON DELETE RESTRICT;
+
+ALTER TABLE pr_reviewers
+    DROP CONSTRAINT pr_reviewers_reviewer_id_fkey,
+    ADD CONSTRAINT pr_reviewers_reviewer_id_fkey FOREIGN KEY (reviewer_id)
+        REFERENCES users(user_id) ON DELETE RESTRICT;
\ No newline at end of file
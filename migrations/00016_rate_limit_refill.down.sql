@@ -0,0 +1 @@
+ALTER TABLE rate_limit_buckets DROP COLUMN IF EXISTS refill_secs;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Seconds an empty bucket takes to refill under the limit it was last used
+-- with. Idle buckets are purged only once they have had that long, so slow
+-- limits are not reset early.
+ALTER TABLE rate_limit_buckets
+    ADD COLUMN IF NOT EXISTS refill_secs DOUBLE PRECISION NOT NULL DEFAULT 0;
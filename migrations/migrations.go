@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations so the service binary can
+// apply them itself. File names follow golang-migrate:
+// <version>_<name>.up.sql and <version>_<name>.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS